@@ -0,0 +1,274 @@
+// Package analyzer extracts declarations from Oxide/uMod plugin C# source
+// without compiling it.
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Command types, the same as stored in plugin_commands.cmd_type
+const (
+	TypeChat    = "chat"
+	TypeConsole = "console"
+	TypeBoth    = "both"
+)
+
+// Command declared in plugin source
+type Command struct {
+	Name    string
+	Type    string
+	Aliases []string
+	// permission required by Covalence commands, if declared
+	Permission string
+	// method handling the command
+	Handler string
+	// line of the first declaration
+	Line int
+}
+
+// Permission registered by plugin source
+type Permission struct {
+	Name string
+	// line of the first registration
+	Line int
+}
+
+// Declarations found in plugin source
+type Result struct {
+	Commands    []Command
+	Permissions []Permission
+	// declarations with arguments that couldn't be resolved to strings,
+	// e.g. command names read from config
+	Unresolved []string
+}
+
+var (
+	// [ChatCommand("kit")], [ConsoleCommand("kit.reset")], [Command("kit", "kits")]
+	commandAttrPattern = regexp.MustCompile(`[\[,]\s*(ChatCommand|ConsoleCommand|Command)\s*\(`)
+	// cmd.AddChatCommand("kit", this, "CmdKit"), AddCovalenceCommand(new[] {"kit"}, nameof(CmdKit))
+	commandCallPattern = regexp.MustCompile(`\b(AddChatCommand|AddConsoleCommand|AddCovalenceCommand)\s*\(`)
+	// method declaration following attributes, the name is captured
+	methodPattern = regexp.MustCompile(`^\s*(?:(?:private|public|protected|internal|static|async|override|virtual)\s+)*[\w<>\[\],.?]+\s+(\w+)\s*\(`)
+	// [Permission("kits.use")] next to a Covalence command attribute
+	permissionAttrPattern = regexp.MustCompile(`\bPermission\s*\(`)
+	// permission.RegisterPermission("kits.vip", this)
+	registerPermissionPattern = regexp.MustCompile(`\bRegisterPermission\s*\(`)
+)
+
+// Extract command and permission declarations from plugin source
+func Analyze(raw []byte) (result Result) {
+	src := newSource(raw)
+
+	var commands []Command
+	for _, match := range commandAttrPattern.FindAllStringSubmatchIndex(src.skeleton, -1) {
+		kind := src.code[match[2]:match[3]]
+		command, err := src.commandAttribute(kind, match[1]-1)
+		if err != nil {
+			result.Unresolved = append(result.Unresolved, fmt.Sprintf("line %d: %s", src.line(match[2]), err))
+			continue
+		}
+		command.Line = src.line(match[2])
+		commands = append(commands, command)
+	}
+	for _, match := range commandCallPattern.FindAllStringSubmatchIndex(src.skeleton, -1) {
+		kind := src.code[match[2]:match[3]]
+		command, err := src.commandCall(kind, match[1]-1)
+		if err != nil {
+			result.Unresolved = append(result.Unresolved, fmt.Sprintf("line %d: %s", src.line(match[2]), err))
+			continue
+		}
+		command.Line = src.line(match[2])
+		commands = append(commands, command)
+	}
+
+	result.Commands = mergeCommands(commands)
+
+	var permissions []Permission
+	for _, match := range registerPermissionPattern.FindAllStringIndex(src.skeleton, -1) {
+		line := src.line(match[0])
+		args, _ := src.arguments(match[1] - 1)
+		if len(args) == 0 {
+			continue
+		}
+		name, ok := src.stringValue(args[0])
+		if !ok {
+			result.Unresolved = append(result.Unresolved, fmt.Sprintf("line %d: RegisterPermission(%s)", line, strings.Join(args, ", ")))
+			continue
+		}
+		permissions = append(permissions, Permission{Name: name, Line: line})
+	}
+	// Covalence registers permissions of its commands automatically
+	for _, command := range result.Commands {
+		if command.Permission != "" {
+			permissions = append(permissions, Permission{Name: command.Permission, Line: command.Line})
+		}
+	}
+	result.Permissions = mergePermissions(permissions)
+
+	return result
+}
+
+// Parse a command attribute with arguments at the offset and find
+// the method it's attached to
+func (src *source) commandAttribute(kind string, open int) (command Command, err error) {
+	args, end := src.arguments(open)
+	if len(args) == 0 {
+		return command, fmt.Errorf("%s without arguments", kind)
+	}
+
+	var names []string
+	for _, arg := range args {
+		name, ok := src.stringValue(arg)
+		if !ok {
+			return command, fmt.Errorf("%s(%s) name isn't a constant", kind, strings.Join(args, ", "))
+		}
+		names = append(names, name)
+	}
+	command.Name, command.Aliases = names[0], names[1:]
+	command.Type = typeOf(kind)
+
+	// the rest of the attribute list and other attributes precede the method
+	attrsEnd, method := src.attributesUntilMethod(end)
+	command.Handler = method
+	if loc := permissionAttrPattern.FindStringIndex(src.skeleton[end:attrsEnd]); loc != nil {
+		if permArgs, _ := src.arguments(end + loc[1] - 1); len(permArgs) > 0 {
+			command.Permission, _ = src.stringValue(permArgs[0])
+		}
+	}
+
+	return command, nil
+}
+
+// Parse a command registration call with arguments at the offset
+func (src *source) commandCall(kind string, open int) (command Command, err error) {
+	args, _ := src.arguments(open)
+	call := fmt.Sprintf("%s(%s)", kind, strings.Join(args, ", "))
+
+	// AddChatCommand(name, plugin, callback) and AddConsoleCommand have the
+	// same signature, AddCovalenceCommand(names, callback, permission)
+	handlerArg := 2
+	if kind == "AddCovalenceCommand" {
+		handlerArg = 1
+	}
+	if len(args) <= handlerArg {
+		return command, fmt.Errorf("%s has too few arguments", call)
+	}
+
+	names, ok := src.stringValues(args[0])
+	if !ok {
+		return command, fmt.Errorf("%s name isn't a constant", call)
+	}
+	command.Name, command.Aliases = names[0], names[1:]
+	command.Type = typeOf(kind)
+	command.Handler = src.handlerName(args[handlerArg])
+	if kind == "AddCovalenceCommand" && len(args) > 2 {
+		command.Permission, _ = src.stringValue(args[2])
+	}
+
+	return command, nil
+}
+
+// Skip the rest of the attribute list and following attributes, return
+// the offset where they end and the name of the method declared after them
+func (src *source) attributesUntilMethod(offset int) (attrsEnd int, method string) {
+	for {
+		// end of the current attribute list
+		closing := strings.IndexByte(src.skeleton[offset:], ']')
+		if closing == -1 {
+			return len(src.code), ""
+		}
+		offset += closing + 1
+
+		rest := strings.TrimLeft(src.skeleton[offset:], " \t\r\n")
+		if !strings.HasPrefix(rest, "[") {
+			break
+		}
+		offset = len(src.skeleton) - len(rest) + 1
+	}
+
+	match := methodPattern.FindStringSubmatch(src.code[offset:])
+	if match == nil {
+		return offset, ""
+	}
+
+	return offset, match[1]
+}
+
+// Handler of a registration call: a method group, nameof(), or a string
+func (src *source) handlerName(arg string) string {
+	if name, ok := src.stringValue(arg); ok {
+		return name
+	}
+	if identifierPattern.MatchString(arg) {
+		return lastPart(arg)
+	}
+
+	return ""
+}
+
+// Command type by attribute or method name
+func typeOf(kind string) string {
+	switch kind {
+	case "ChatCommand", "AddChatCommand":
+		return TypeChat
+	case "ConsoleCommand", "AddConsoleCommand":
+		return TypeConsole
+	}
+
+	// Covalence commands work in both chat and console
+	return TypeBoth
+}
+
+// Merge declarations of the same command, e.g. a chat and a console one,
+// and order commands by their first declaration
+func mergeCommands(commands []Command) (merged []Command) {
+	slices.SortStableFunc(commands, func(a, b Command) int {
+		return a.Line - b.Line
+	})
+
+	byName := map[string]int{}
+	for _, command := range commands {
+		i, exists := byName[command.Name]
+		if !exists {
+			byName[command.Name] = len(merged)
+			merged = append(merged, command)
+			continue
+		}
+
+		existing := &merged[i]
+		if existing.Type != command.Type {
+			existing.Type = TypeBoth
+		}
+		for _, alias := range command.Aliases {
+			if !slices.Contains(existing.Aliases, alias) {
+				existing.Aliases = append(existing.Aliases, alias)
+			}
+		}
+		if existing.Permission == "" {
+			existing.Permission = command.Permission
+		}
+		if existing.Handler == "" {
+			existing.Handler = command.Handler
+		}
+	}
+
+	return merged
+}
+
+// Remove repeated registrations and order permissions by the first one
+func mergePermissions(permissions []Permission) (merged []Permission) {
+	slices.SortStableFunc(permissions, func(a, b Permission) int {
+		return a.Line - b.Line
+	})
+
+	for _, permission := range permissions {
+		if !slices.ContainsFunc(merged, func(p Permission) bool { return p.Name == permission.Name }) {
+			merged = append(merged, permission)
+		}
+	}
+
+	return merged
+}
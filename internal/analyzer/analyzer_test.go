@@ -0,0 +1,140 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Plugin declaring commands in every supported way
+const kitsSource = `using System.Collections.Generic;
+using Oxide.Core.Libraries.Covalence;
+
+namespace Oxide.Plugins
+{
+    [Info("Kits", "Author", "4.4.2")]
+    public class Kits : RustPlugin
+    {
+        private const string PermPrefix = "kits.";
+        private const string PermAdmin = PermPrefix + "admin";
+        private const string CmdGive = "kit.give";
+        private static readonly string ShopCommand = @"kitshop";
+
+        private void Init()
+        {
+            permission.RegisterPermission(PermPrefix + "vip", this);
+            permission.RegisterPermission(PermAdmin, this);
+            foreach (var kit in config.Kits)
+                permission.RegisterPermission($"kits.{kit.Name}", this);
+            cmd.AddChatCommand("kits", this, nameof(CmdListKits));
+            cmd.AddConsoleCommand(CmdGive, this, "CCmdGive");
+            AddCovalenceCommand(new[] { "kitshop.open", ShopCommand }, nameof(CmdShop), "kits.shop");
+            // cmd.AddChatCommand("commented", this, "CmdCommented");
+            /* AddCovalenceCommand("commented.block", "CmdCommented"); */
+            foreach (var command in config.Commands)
+                cmd.AddChatCommand(command, this, CmdCustom);
+        }
+
+        [ChatCommand("kit")]
+        private void CmdKit(BasePlayer player, string command, string[] args)
+        {
+            SendReply(player, "Use /kit <name>, not [ChatCommand(\"fake\")] // in text");
+        }
+
+        [ConsoleCommand("kit")]
+        private void CCmdKit(ConsoleSystem.Arg arg) { }
+
+        [Command("kit.reset", "kitreset"), Permission(PermAdmin)]
+        [HookMethod("Reset")]
+        private void CmdReset(IPlayer player, string command, string[] args) { }
+
+        private void CmdListKits(BasePlayer player, string command, string[] args) { }
+    }
+}`
+
+func TestAnalyze(t *testing.T) {
+	result := Analyze([]byte(kitsSource))
+
+	expectedCommands := []Command{
+		{Name: "kits", Type: TypeChat, Aliases: []string{}, Handler: "CmdListKits", Line: 20},
+		{Name: "kit.give", Type: TypeConsole, Aliases: []string{}, Handler: "CCmdGive", Line: 21},
+		{Name: "kitshop.open", Type: TypeBoth, Aliases: []string{"kitshop"}, Permission: "kits.shop", Handler: "CmdShop", Line: 22},
+		{Name: "kit", Type: TypeBoth, Aliases: []string{}, Handler: "CmdKit", Line: 29},
+		{Name: "kit.reset", Type: TypeBoth, Aliases: []string{"kitreset"}, Permission: "kits.admin", Handler: "CmdReset", Line: 38},
+	}
+	if !reflect.DeepEqual(result.Commands, expectedCommands) {
+		t.Errorf("Analyze() commands =\n%+v\nwant\n%+v", result.Commands, expectedCommands)
+	}
+
+	expectedPermissions := []Permission{
+		{Name: "kits.vip", Line: 16},
+		{Name: "kits.admin", Line: 17},
+		{Name: "kits.shop", Line: 22},
+	}
+	if !reflect.DeepEqual(result.Permissions, expectedPermissions) {
+		t.Errorf("Analyze() permissions = %+v, want %+v", result.Permissions, expectedPermissions)
+	}
+
+	// names from config can't be known without running the plugin
+	if len(result.Unresolved) != 2 {
+		t.Errorf("Analyze() unresolved = %v, want a command and a permission", result.Unresolved)
+	}
+}
+
+func TestStripComments(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		expectedOutput string
+	}{
+		{name: "line comment", input: "a // b\nc", expectedOutput: "a     \nc"},
+		{name: "block comment keeps lines", input: "a /* b\nc */ d", expectedOutput: "a     \n     d"},
+		{name: "comment in string", input: `s = "// not a comment";`, expectedOutput: `s = "// not a comment";`},
+		{name: "escaped quote", input: `s = "\" // x"; // y`, expectedOutput: `s = "\" // x";     `},
+		{name: "verbatim string", input: `s = @"C:\dir\"" // x"; // y`, expectedOutput: `s = @"C:\dir\"" // x";     `},
+		{name: "char literal", input: `c = '"'; // x`, expectedOutput: `c = '"';     `},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if output := stripComments(test.input); output != test.expectedOutput {
+				t.Errorf("stripComments() = %q, want %q", output, test.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestReadInfo(t *testing.T) {
+	tests := []struct {
+		name         string
+		source       string
+		expectedInfo Info
+	}{
+		{
+			name:         "declared",
+			source:       kitsSource,
+			expectedInfo: Info{Title: "Kits", Author: "Author", Version: "4.4.2"},
+		},
+		{
+			name: "with description",
+			source: `// [Info("Commented", "Nobody", "0.0.1")]
+[Info("Better Chat", "LaserHydra", "5.2.15")]
+[Description("Allows to manage chat groups")]
+public class BetterChat : CovalencePlugin {}`,
+			expectedInfo: Info{Title: "Better Chat", Author: "LaserHydra", Version: "5.2.15", Description: "Allows to manage chat groups"},
+		},
+		{
+			name:         "numeric version",
+			source:       `[Info("Old", "Someone", 0.1)] class Old : RustPlugin {}`,
+			expectedInfo: Info{Title: "Old", Author: "Someone", Version: "0.1"},
+		},
+		{name: "missing", source: `class Broken {}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if info := ReadInfo([]byte(test.source)); info != test.expectedInfo {
+				t.Errorf("ReadInfo() = %+v, want %+v", info, test.expectedInfo)
+			}
+		})
+	}
+}
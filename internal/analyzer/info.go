@@ -0,0 +1,45 @@
+package analyzer
+
+import "regexp"
+
+// Plugin metadata declared with Info and Description attributes
+type Info struct {
+	Title       string
+	Author      string
+	Version     string
+	Description string
+}
+
+var (
+	// [Info("Kits", "Author", "4.4.2")]
+	infoAttrPattern = regexp.MustCompile(`\[\s*Info\s*\(`)
+	// [Description("Create kits of items")]
+	descriptionAttrPattern = regexp.MustCompile(`\[\s*Description\s*\(`)
+	// old plugins declare versions as numbers, e.g. 0.1
+	numericVersionPattern = regexp.MustCompile(`^\d+(\.\d+)*$`)
+)
+
+// Read plugin metadata from source, missing values are left empty
+func ReadInfo(raw []byte) (info Info) {
+	src := newSource(raw)
+
+	if loc := infoAttrPattern.FindStringIndex(src.skeleton); loc != nil {
+		args, _ := src.arguments(loc[1] - 1)
+		values := make([]string, 3)
+		for i := 0; i < len(args) && i < len(values); i++ {
+			value, ok := src.stringValue(args[i])
+			if !ok && numericVersionPattern.MatchString(args[i]) {
+				value = args[i]
+			}
+			values[i] = value
+		}
+		info.Title, info.Author, info.Version = values[0], values[1], values[2]
+	}
+	if loc := descriptionAttrPattern.FindStringIndex(src.skeleton); loc != nil {
+		if args, _ := src.arguments(loc[1] - 1); len(args) > 0 {
+			info.Description, _ = src.stringValue(args[0])
+		}
+	}
+
+	return info
+}
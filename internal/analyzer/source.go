@@ -0,0 +1,286 @@
+package analyzer
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Plugin source prepared for searching declarations
+type source struct {
+	// code with comments blanked out, line breaks are kept for line numbers
+	code string
+	// code with string contents blanked out too, to search declarations
+	// without matching text inside strings, offsets are the same as in code
+	skeleton string
+	// string constants declared in the plugin
+	consts map[string]string
+}
+
+// String constant or static readonly field, e.g. const string Perm = "kits.use";
+var constPattern = regexp.MustCompile(`(?:\bconst|\bstatic\s+readonly)\s+string\s+(\w+)\s*=([^;]+);`)
+
+// Blank out comments and collect string constants of the source
+func newSource(raw []byte) *source {
+	code := stripComments(string(raw))
+	src := &source{
+		code:     code,
+		skeleton: blankStrings(code),
+		consts:   map[string]string{},
+	}
+
+	// constants may be built of other constants declared later,
+	// so resolving is repeated while new ones get resolved
+	pending := map[string]string{}
+	for _, match := range constPattern.FindAllStringSubmatchIndex(src.skeleton, -1) {
+		pending[code[match[2]:match[3]]] = strings.TrimSpace(code[match[4]:match[5]])
+	}
+	for resolved := true; resolved; {
+		resolved = false
+		for name, expr := range pending {
+			if value, ok := src.stringValue(expr); ok {
+				src.consts[name] = value
+				delete(pending, name)
+				resolved = true
+			}
+		}
+	}
+
+	return src
+}
+
+// Line number of the offset in the source
+func (src *source) line(offset int) int {
+	return strings.Count(src.code[:offset], "\n") + 1
+}
+
+// Replace comments with spaces keeping string literals and line breaks
+func stripComments(code string) string {
+	out := []byte(code)
+	for i := 0; i < len(out); i++ {
+		switch {
+		case out[i] == '"' || (out[i] == '@' && i+1 < len(out) && out[i+1] == '"'):
+			i = stringEnd(code, i)
+		case out[i] == '\'':
+			i = charEnd(code, i)
+		case out[i] == '/' && i+1 < len(out) && out[i+1] == '/':
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				out[i] = ' '
+			}
+		case out[i] == '/' && i+1 < len(out) && out[i+1] == '*':
+			for ; i < len(out) && !(out[i] == '*' && i+1 < len(out) && out[i+1] == '/'); i++ {
+				if out[i] != '\n' {
+					out[i] = ' '
+				}
+			}
+			if i+1 < len(out) {
+				out[i], out[i+1] = ' ', ' '
+				i++
+			}
+		}
+	}
+
+	return string(out)
+}
+
+// Replace contents of string and char literals with spaces
+func blankStrings(code string) string {
+	out := []byte(code)
+	for i := 0; i < len(out); i++ {
+		end := i
+		switch {
+		case out[i] == '"' || (out[i] == '@' && i+1 < len(out) && out[i+1] == '"'):
+			end = stringEnd(code, i)
+		case out[i] == '\'':
+			end = charEnd(code, i)
+		}
+		for j := i + 1; j < end && j < len(out); j++ {
+			if out[j] != '"' && out[j] != '\n' {
+				out[j] = ' '
+			}
+		}
+		i = end
+	}
+
+	return string(out)
+}
+
+// Index of the closing quote of a string literal starting at i
+func stringEnd(code string, i int) int {
+	verbatim := code[i] == '@'
+	if verbatim {
+		i++
+	}
+	for i++; i < len(code); i++ {
+		switch {
+		case verbatim && code[i] == '"' && i+1 < len(code) && code[i+1] == '"':
+			i++
+		case !verbatim && code[i] == '\\':
+			i++
+		case code[i] == '"':
+			return i
+		case !verbatim && code[i] == '\n':
+			// unterminated literal, don't swallow the rest of the file
+			return i
+		}
+	}
+
+	return len(code)
+}
+
+// Index of the closing quote of a char literal starting at i, e.g. '"'
+func charEnd(code string, i int) int {
+	for i++; i < len(code) && code[i] != '\''; i++ {
+		if code[i] == '\\' {
+			i++
+		}
+	}
+
+	return i
+}
+
+// Arguments of a call or attribute whose opening parenthesis is at
+// the offset, split on top-level commas. Returns the offset after the
+// closing parenthesis.
+func (src *source) arguments(open int) (args []string, end int) {
+	depth := 0
+	start := open + 1
+	code := src.code
+	for i := open; i < len(code); i++ {
+		switch code[i] {
+		case '"':
+			i = stringEnd(code, i)
+		case '@':
+			if i+1 < len(code) && code[i+1] == '"' {
+				i = stringEnd(code, i)
+			}
+		case '\'':
+			i = charEnd(code, i)
+		case '(', '{', '[':
+			depth++
+		case ')', '}', ']':
+			depth--
+			if depth == 0 {
+				if arg := strings.TrimSpace(code[start:i]); arg != "" {
+					args = append(args, arg)
+				}
+				return args, i + 1
+			}
+		case ',':
+			if depth == 1 {
+				args = append(args, strings.TrimSpace(code[start:i]))
+				start = i + 1
+			}
+		}
+	}
+
+	return nil, len(code)
+}
+
+var (
+	// nameof(CmdKit) resolves to the method name
+	nameofPattern = regexp.MustCompile(`^nameof\s*\(\s*([\w.]+)\s*\)$`)
+	// new[] { "kit", "kits" } or new string[] { ... }
+	arrayPattern = regexp.MustCompile(`(?s)^new\s*(?:string\s*)?\[\s*\]\s*\{(.*)\}$`)
+	// identifiers like CmdName or Plugin.CmdName
+	identifierPattern = regexp.MustCompile(`^[A-Za-z_][\w.]*$`)
+)
+
+// Evaluate a string argument: a literal, a constant, nameof(),
+// or a concatenation of them like PermPrefix + "vip"
+func (src *source) stringValue(arg string) (value string, ok bool) {
+	if parts := splitConcatenation(arg); len(parts) > 1 {
+		var sb strings.Builder
+		for _, part := range parts {
+			partValue, ok := src.stringValue(part)
+			if !ok {
+				return "", false
+			}
+			sb.WriteString(partValue)
+		}
+		return sb.String(), true
+	}
+
+	if value, ok = unquote(arg); ok {
+		return value, true
+	}
+	if match := nameofPattern.FindStringSubmatch(arg); match != nil {
+		return lastPart(match[1]), true
+	}
+	if identifierPattern.MatchString(arg) {
+		value, ok = src.consts[lastPart(arg)]
+		return value, ok
+	}
+
+	return "", false
+}
+
+// Evaluate an argument that is a string or an array of strings
+func (src *source) stringValues(arg string) (values []string, ok bool) {
+	match := arrayPattern.FindStringSubmatch(arg)
+	if match == nil {
+		value, ok := src.stringValue(arg)
+		if !ok {
+			return nil, false
+		}
+		return []string{value}, true
+	}
+
+	for _, element := range splitTopLevel(match[1]) {
+		value, ok := src.stringValue(element)
+		if !ok {
+			return nil, false
+		}
+		values = append(values, value)
+	}
+
+	return values, len(values) > 0
+}
+
+// Split an expression on "+" operators outside of string literals
+func splitConcatenation(expr string) (parts []string) {
+	start := 0
+	for i := 0; i < len(expr); i++ {
+		switch expr[i] {
+		case '"':
+			i = stringEnd(expr, i)
+		case '@':
+			if i+1 < len(expr) && expr[i+1] == '"' {
+				i = stringEnd(expr, i)
+			}
+		case '\'':
+			i = charEnd(expr, i)
+		case '+':
+			parts = append(parts, strings.TrimSpace(expr[start:i]))
+			start = i + 1
+		}
+	}
+
+	return append(parts, strings.TrimSpace(expr[start:]))
+}
+
+// Split array elements on commas outside of string literals
+func splitTopLevel(list string) (parts []string) {
+	inner := &source{code: "(" + list + ")"}
+	parts, _ = inner.arguments(0)
+
+	return parts
+}
+
+// Convert a C# string literal to its value
+func unquote(literal string) (value string, ok bool) {
+	switch {
+	case strings.HasPrefix(literal, `@"`) && strings.HasSuffix(literal, `"`) && len(literal) >= 3:
+		return strings.ReplaceAll(literal[2:len(literal)-1], `""`, `"`), true
+	case strings.HasPrefix(literal, `"`) && strings.HasSuffix(literal, `"`) && len(literal) >= 2:
+		value, err := strconv.Unquote(literal)
+		return value, err == nil
+	}
+
+	return "", false
+}
+
+// Last part of a dotted name, e.g. CmdName of Plugin.CmdName
+func lastPart(name string) string {
+	return name[strings.LastIndex(name, ".")+1:]
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -10,7 +11,7 @@ import (
 	"time"
 
 	_ "github.com/joho/godotenv/autoload"
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 )
 
 // Service represents a service that interacts with a database.
@@ -21,6 +22,10 @@ type Service interface {
 
 	Queries() *Queries
 
+	// InTx runs fn with queries bound to a transaction. The transaction
+	// is committed if fn succeeds and rolled back otherwise.
+	InTx(ctx context.Context, fn func(q *Queries) error) error
+
 	// Close terminates the database connection.
 	// It returns an error if the connection cannot be closed.
 	Close() error
@@ -68,6 +73,35 @@ func (s *service) Queries() *Queries {
 	return s.queries
 }
 
+func (s *service) InTx(ctx context.Context, fn func(q *Queries) error) error {
+	return RunInTx(ctx, s.db, fn)
+}
+
+// RunInTx begins a transaction on db and runs fn with queries bound to it.
+// The transaction is committed if fn succeeds and rolled back otherwise.
+func RunInTx(ctx context.Context, db *sql.DB, fn func(q *Queries) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err = fn(New(tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w (rollback failed: %s)", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// IsUniqueViolation reports whether err comes from a write breaking
+// a UNIQUE constraint.
+func IsUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+}
+
 // Health checks the health of the database connection by pinging the database.
 // It returns a map with keys indicating various health statistics.
 func (s *service) Health() map[string]string {
@@ -0,0 +1,58 @@
+// Package dbtest creates databases for tests of packages using queries.
+package dbtest
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Open an in-memory database with all "Up" migrations applied, it's
+// closed when the test finishes
+func Open(t testing.TB) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	// every new connection would get its own empty in-memory database
+	db.SetMaxOpenConns(1)
+	if _, err = db.Exec("PRAGMA foreign_keys = ON;"); err != nil {
+		t.Fatal(err)
+	}
+
+	migrations, err := filepath.Glob(filepath.Join(schemaDir(t), "*.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, migration := range migrations {
+		content, err := os.ReadFile(migration)
+		if err != nil {
+			t.Fatal(err)
+		}
+		up, _, _ := strings.Cut(string(content), "-- +goose Down")
+		if _, err = db.Exec(up); err != nil {
+			t.Fatalf("error applying %s: %v", migration, err)
+		}
+	}
+
+	return db
+}
+
+// Path to goose migrations, found next to this file since tests of
+// every package run in their own directory
+func schemaDir(t testing.TB) string {
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatal("no path of the dbtest package")
+	}
+
+	return filepath.Join(filepath.Dir(file), "..", "..", "..", "sql", "schema")
+}
@@ -0,0 +1,147 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: exports.sql
+
+package database
+
+import (
+	"context"
+)
+
+const getExportConfigs = `-- name: GetExportConfigs :many
+SELECT plugin_id, config_json, updated_at
+FROM plugin_configs
+`
+
+type GetExportConfigsRow struct {
+	PluginID   int64
+	ConfigJson string
+	UpdatedAt  string
+}
+
+func (q *Queries) GetExportConfigs(ctx context.Context) ([]GetExportConfigsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getExportConfigs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetExportConfigsRow
+	for rows.Next() {
+		var i GetExportConfigsRow
+		if err := rows.Scan(&i.PluginID, &i.ConfigJson, &i.UpdatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getExportLocales = `-- name: GetExportLocales :many
+SELECT plugin_id, lang_code, content_json, updated_at
+FROM plugin_locales
+ORDER BY lang_code
+`
+
+type GetExportLocalesRow struct {
+	PluginID    int64
+	LangCode    string
+	ContentJson string
+	UpdatedAt   string
+}
+
+func (q *Queries) GetExportLocales(ctx context.Context) ([]GetExportLocalesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getExportLocales)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetExportLocalesRow
+	for rows.Next() {
+		var i GetExportLocalesRow
+		if err := rows.Scan(
+			&i.PluginID,
+			&i.LangCode,
+			&i.ContentJson,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getExportPlugins = `-- name: GetExportPlugins :many
+SELECT plugins.id, plugins.name, plugins.slug,
+    CAST(COALESCE((
+        SELECT plugin_sources.file_name
+        FROM plugin_sources
+        WHERE plugin_sources.plugin_id = plugins.id
+        ORDER BY plugin_sources.updated_at DESC, plugin_sources.id DESC
+        LIMIT 1
+    ), '') AS TEXT) AS source_file_name,
+    EXISTS (
+        SELECT 1
+        FROM plugin_configs
+        WHERE plugin_configs.plugin_id = plugins.id
+    ) AS has_config,
+    (
+        SELECT COUNT(*)
+        FROM plugin_locales
+        WHERE plugin_locales.plugin_id = plugins.id
+    ) AS locale_count
+FROM plugins
+ORDER BY plugins.name
+`
+
+type GetExportPluginsRow struct {
+	ID             int64
+	Name           string
+	Slug           string
+	SourceFileName string
+	HasConfig      int64
+	LocaleCount    int64
+}
+
+func (q *Queries) GetExportPlugins(ctx context.Context) ([]GetExportPluginsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getExportPlugins)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetExportPluginsRow
+	for rows.Next() {
+		var i GetExportPluginsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.SourceFileName,
+			&i.HasConfig,
+			&i.LocaleCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
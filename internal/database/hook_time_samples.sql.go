@@ -0,0 +1,146 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: hook_time_samples.sql
+
+package database
+
+import (
+	"context"
+)
+
+const addHookTimeSample = `-- name: AddHookTimeSample :exec
+INSERT INTO hook_time_samples(server_id, title, file_name, version, hook_time, sampled_at)
+VALUES (?, ?, ?, ?, ?, datetime('now'))
+`
+
+type AddHookTimeSampleParams struct {
+	ServerID int64
+	Title    string
+	FileName string
+	Version  string
+	HookTime float64
+}
+
+func (q *Queries) AddHookTimeSample(ctx context.Context, arg AddHookTimeSampleParams) error {
+	_, err := q.db.ExecContext(ctx, addHookTimeSample,
+		arg.ServerID,
+		arg.Title,
+		arg.FileName,
+		arg.Version,
+		arg.HookTime,
+	)
+	return err
+}
+
+const deleteHookTimeSamplesBefore = `-- name: DeleteHookTimeSamplesBefore :exec
+DELETE
+FROM hook_time_samples
+WHERE sampled_at < ?
+`
+
+func (q *Queries) DeleteHookTimeSamplesBefore(ctx context.Context, sampledAt string) error {
+	_, err := q.db.ExecContext(ctx, deleteHookTimeSamplesBefore, sampledAt)
+	return err
+}
+
+const getPluginHookTimes = `-- name: GetPluginHookTimes :many
+SELECT servers.name AS server_name, servers.slug AS server_slug,
+    hook_time_samples.sampled_at, hook_time_samples.version, hook_time_samples.hook_time
+FROM hook_time_samples
+JOIN servers ON servers.id = hook_time_samples.server_id
+WHERE hook_time_samples.file_name = ? COLLATE NOCASE
+    AND hook_time_samples.sampled_at >= ?
+ORDER BY servers.name, servers.id, hook_time_samples.sampled_at, hook_time_samples.id
+`
+
+type GetPluginHookTimesParams struct {
+	FileName  string
+	SampledAt string
+}
+
+type GetPluginHookTimesRow struct {
+	ServerName string
+	ServerSlug string
+	SampledAt  string
+	Version    string
+	HookTime   float64
+}
+
+func (q *Queries) GetPluginHookTimes(ctx context.Context, arg GetPluginHookTimesParams) ([]GetPluginHookTimesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPluginHookTimes, arg.FileName, arg.SampledAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPluginHookTimesRow
+	for rows.Next() {
+		var i GetPluginHookTimesRow
+		if err := rows.Scan(
+			&i.ServerName,
+			&i.ServerSlug,
+			&i.SampledAt,
+			&i.Version,
+			&i.HookTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getServerHookTimes = `-- name: GetServerHookTimes :many
+SELECT title, file_name, sampled_at, version, hook_time
+FROM hook_time_samples
+WHERE server_id = ? AND sampled_at >= ?
+ORDER BY file_name COLLATE NOCASE, sampled_at, id
+`
+
+type GetServerHookTimesParams struct {
+	ServerID  int64
+	SampledAt string
+}
+
+type GetServerHookTimesRow struct {
+	Title     string
+	FileName  string
+	SampledAt string
+	Version   string
+	HookTime  float64
+}
+
+func (q *Queries) GetServerHookTimes(ctx context.Context, arg GetServerHookTimesParams) ([]GetServerHookTimesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getServerHookTimes, arg.ServerID, arg.SampledAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetServerHookTimesRow
+	for rows.Next() {
+		var i GetServerHookTimesRow
+		if err := rows.Scan(
+			&i.Title,
+			&i.FileName,
+			&i.SampledAt,
+			&i.Version,
+			&i.HookTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
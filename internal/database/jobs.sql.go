@@ -0,0 +1,206 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: jobs.sql
+
+package database
+
+import (
+	"context"
+)
+
+const addJobRun = `-- name: AddJobRun :one
+INSERT INTO job_runs(job_id, trigger, status, started_at)
+VALUES (?, ?, 'running', datetime('now'))
+RETURNING id, job_id, "trigger", status, error, started_at, finished_at
+`
+
+type AddJobRunParams struct {
+	JobID   int64
+	Trigger string
+}
+
+func (q *Queries) AddJobRun(ctx context.Context, arg AddJobRunParams) (JobRun, error) {
+	row := q.db.QueryRowContext(ctx, addJobRun, arg.JobID, arg.Trigger)
+	var i JobRun
+	err := row.Scan(
+		&i.ID,
+		&i.JobID,
+		&i.Trigger,
+		&i.Status,
+		&i.Error,
+		&i.StartedAt,
+		&i.FinishedAt,
+	)
+	return i, err
+}
+
+const ensureJob = `-- name: EnsureJob :one
+INSERT INTO jobs(name, schedule, created_at, updated_at)
+VALUES (?, ?, datetime('now'), datetime('now'))
+ON CONFLICT(name) DO UPDATE
+SET name = excluded.name
+RETURNING id, name, schedule, created_at, updated_at
+`
+
+type EnsureJobParams struct {
+	Name     string
+	Schedule string
+}
+
+func (q *Queries) EnsureJob(ctx context.Context, arg EnsureJobParams) (Job, error) {
+	row := q.db.QueryRowContext(ctx, ensureJob, arg.Name, arg.Schedule)
+	var i Job
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Schedule,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const failUnfinishedJobRuns = `-- name: FailUnfinishedJobRuns :exec
+UPDATE job_runs
+SET status = 'failed',
+    error = ?,
+    finished_at = datetime('now')
+WHERE status = 'running'
+`
+
+func (q *Queries) FailUnfinishedJobRuns(ctx context.Context, error string) error {
+	_, err := q.db.ExecContext(ctx, failUnfinishedJobRuns, error)
+	return err
+}
+
+const finishJobRun = `-- name: FinishJobRun :exec
+UPDATE job_runs
+SET status = ?,
+    error = ?,
+    finished_at = datetime('now')
+WHERE id = ?
+`
+
+type FinishJobRunParams struct {
+	Status string
+	Error  string
+	ID     int64
+}
+
+func (q *Queries) FinishJobRun(ctx context.Context, arg FinishJobRunParams) error {
+	_, err := q.db.ExecContext(ctx, finishJobRun, arg.Status, arg.Error, arg.ID)
+	return err
+}
+
+const getJobs = `-- name: GetJobs :many
+SELECT id, name, schedule, created_at, updated_at
+FROM jobs
+ORDER BY name
+`
+
+func (q *Queries) GetJobs(ctx context.Context) ([]Job, error) {
+	rows, err := q.db.QueryContext(ctx, getJobs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Job
+	for rows.Next() {
+		var i Job
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Schedule,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecentJobRuns = `-- name: GetRecentJobRuns :many
+SELECT job_runs.id, job_runs.job_id, job_runs."trigger", job_runs.status, job_runs.error, job_runs.started_at, job_runs.finished_at, jobs.name AS job_name
+FROM job_runs
+JOIN jobs ON jobs.id = job_runs.job_id
+ORDER BY job_runs.started_at DESC, job_runs.id DESC
+LIMIT ?
+`
+
+type GetRecentJobRunsRow struct {
+	ID         int64
+	JobID      int64
+	Trigger    string
+	Status     string
+	Error      string
+	StartedAt  string
+	FinishedAt string
+	JobName    string
+}
+
+func (q *Queries) GetRecentJobRuns(ctx context.Context, limit int64) ([]GetRecentJobRunsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getRecentJobRuns, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRecentJobRunsRow
+	for rows.Next() {
+		var i GetRecentJobRunsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.JobID,
+			&i.Trigger,
+			&i.Status,
+			&i.Error,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.JobName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateJobSchedule = `-- name: UpdateJobSchedule :one
+UPDATE jobs
+SET schedule = ?,
+    updated_at = datetime('now')
+WHERE name = ?
+RETURNING id, name, schedule, created_at, updated_at
+`
+
+type UpdateJobScheduleParams struct {
+	Schedule string
+	Name     string
+}
+
+func (q *Queries) UpdateJobSchedule(ctx context.Context, arg UpdateJobScheduleParams) (Job, error) {
+	row := q.db.QueryRowContext(ctx, updateJobSchedule, arg.Schedule, arg.Name)
+	var i Job
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Schedule,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -4,6 +4,38 @@
 
 package database
 
+import (
+	"database/sql"
+)
+
+type HookTimeSample struct {
+	ID        int64
+	ServerID  int64
+	Title     string
+	FileName  string
+	Version   string
+	HookTime  float64
+	SampledAt string
+}
+
+type Job struct {
+	ID        int64
+	Name      string
+	Schedule  string
+	CreatedAt string
+	UpdatedAt string
+}
+
+type JobRun struct {
+	ID         int64
+	JobID      int64
+	Trigger    string
+	Status     string
+	Error      string
+	StartedAt  string
+	FinishedAt string
+}
+
 type Plugin struct {
 	ID                int64
 	Name              string
@@ -33,6 +65,11 @@ type PluginCommand struct {
 	Description string
 	CreatedAt   string
 	UpdatedAt   string
+	CmdType     string
+	Args        string
+	Aliases     string
+	Permission  string
+	Position    int64
 }
 
 type PluginConfig struct {
@@ -52,11 +89,20 @@ type PluginDoc struct {
 }
 
 type PluginImage struct {
-	ID        int64
-	PluginID  int64
-	Image     []byte
-	CreatedAt string
-	UpdatedAt string
+	ID            int64
+	PluginID      int64
+	Image         []byte
+	CreatedAt     string
+	UpdatedAt     string
+	FileName      string
+	MimeType      string
+	Size          int64
+	Width         int64
+	Height        int64
+	Sha256        string
+	Thumbnail     []byte
+	ThumbnailType string
+	IsCover       int64
 }
 
 type PluginLocale struct {
@@ -78,6 +124,8 @@ type PluginManualCodeChange struct {
 	IsRelevant   int64
 	CreatedAt    string
 	UpdatedAt    string
+	SourceID     sql.NullInt64
+	Hunk         string
 }
 
 type PluginOrigin struct {
@@ -89,4 +137,86 @@ type PluginOrigin struct {
 	HasApi           int64
 	CreatedAt        string
 	UpdatedAt        string
+	Adapter          string
+}
+
+type PluginPermission struct {
+	ID          int64
+	PluginID    int64
+	Permission  string
+	Description string
+	CreatedAt   string
+	UpdatedAt   string
+}
+
+type PluginSource struct {
+	ID          int64
+	PluginID    int64
+	ChangelogID int64
+	FileName    string
+	Sha256      string
+	CreatedAt   string
+	UpdatedAt   string
+}
+
+type RconAction struct {
+	ID         int64
+	ServerID   int64
+	PluginID   int64
+	Action     string
+	Command    string
+	Actor      string
+	Status     string
+	Output     string
+	CreatedAt  string
+	FinishedAt string
+}
+
+type Server struct {
+	ID            int64
+	Name          string
+	Slug          string
+	Framework     string
+	RootPath      string
+	FrameworkPath string
+	Notes         string
+	CreatedAt     string
+	UpdatedAt     string
+	RconHost      string
+	RconPort      int64
+	RconPassword  string
+}
+
+type ServerPlugin struct {
+	ID          int64
+	ServerID    int64
+	PluginID    int64
+	Version     string
+	InstalledAt string
+	CreatedAt   string
+	UpdatedAt   string
+}
+
+type ServerSnapshot struct {
+	ID        int64
+	ServerID  int64
+	CreatedAt string
+}
+
+type ServerSnapshotPlugin struct {
+	ID         int64
+	SnapshotID int64
+	Title      string
+	Version    string
+	Author     string
+	HookTime   float64
+	FileName   string
+	Error      string
+}
+
+type SourceBlob struct {
+	Sha256    string
+	Content   []byte
+	Size      int64
+	CreatedAt string
 }
@@ -10,9 +10,9 @@ import (
 )
 
 const addOrigin = `-- name: AddOrigin :one
-INSERT INTO plugin_origins(name, slug, url, path_to_plugin_list, has_api, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, datetime('now'), datetime('now'))
-RETURNING id, name, slug, url, path_to_plugin_list, has_api, created_at, updated_at
+INSERT INTO plugin_origins(name, slug, url, path_to_plugin_list, has_api, adapter, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))
+RETURNING id, name, slug, url, path_to_plugin_list, has_api, created_at, updated_at, adapter
 `
 
 type AddOriginParams struct {
@@ -21,6 +21,7 @@ type AddOriginParams struct {
 	Url              string
 	PathToPluginList string
 	HasApi           int64
+	Adapter          string
 }
 
 func (q *Queries) AddOrigin(ctx context.Context, arg AddOriginParams) (PluginOrigin, error) {
@@ -30,6 +31,7 @@ func (q *Queries) AddOrigin(ctx context.Context, arg AddOriginParams) (PluginOri
 		arg.Url,
 		arg.PathToPluginList,
 		arg.HasApi,
+		arg.Adapter,
 	)
 	var i PluginOrigin
 	err := row.Scan(
@@ -41,6 +43,7 @@ func (q *Queries) AddOrigin(ctx context.Context, arg AddOriginParams) (PluginOri
 		&i.HasApi,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Adapter,
 	)
 	return i, err
 }
@@ -49,7 +52,7 @@ const deleteOrigin = `-- name: DeleteOrigin :one
 DELETE
 FROM plugin_origins
 WHERE slug = ?
-RETURNING id, name, slug, url, path_to_plugin_list, has_api, created_at, updated_at
+RETURNING id, name, slug, url, path_to_plugin_list, has_api, created_at, updated_at, adapter
 `
 
 func (q *Queries) DeleteOrigin(ctx context.Context, slug string) (PluginOrigin, error) {
@@ -64,12 +67,13 @@ func (q *Queries) DeleteOrigin(ctx context.Context, slug string) (PluginOrigin,
 		&i.HasApi,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Adapter,
 	)
 	return i, err
 }
 
 const getOrigin = `-- name: GetOrigin :one
-SELECT id, name, slug, url, path_to_plugin_list, has_api, created_at, updated_at
+SELECT id, name, slug, url, path_to_plugin_list, has_api, created_at, updated_at, adapter
 FROM plugin_origins
 WHERE slug = ?
 `
@@ -86,12 +90,36 @@ func (q *Queries) GetOrigin(ctx context.Context, slug string) (PluginOrigin, err
 		&i.HasApi,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Adapter,
+	)
+	return i, err
+}
+
+const getOriginByID = `-- name: GetOriginByID :one
+SELECT id, name, slug, url, path_to_plugin_list, has_api, created_at, updated_at, adapter
+FROM plugin_origins
+WHERE id = ?
+`
+
+func (q *Queries) GetOriginByID(ctx context.Context, id int64) (PluginOrigin, error) {
+	row := q.db.QueryRowContext(ctx, getOriginByID, id)
+	var i PluginOrigin
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.Url,
+		&i.PathToPluginList,
+		&i.HasApi,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Adapter,
 	)
 	return i, err
 }
 
 const getOrigins = `-- name: GetOrigins :many
-SELECT id, name, slug, url, path_to_plugin_list, has_api, created_at, updated_at
+SELECT id, name, slug, url, path_to_plugin_list, has_api, created_at, updated_at, adapter
 FROM plugin_origins
 ORDER BY name
 `
@@ -114,6 +142,7 @@ func (q *Queries) GetOrigins(ctx context.Context) ([]PluginOrigin, error) {
 			&i.HasApi,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Adapter,
 		); err != nil {
 			return nil, err
 		}
@@ -133,15 +162,17 @@ UPDATE plugin_origins
 SET url = ?,
     path_to_plugin_list = ?,
     has_api = ?,
+    adapter = ?,
     updated_at = datetime('now')
 WHERE slug = ?
-RETURNING id, name, slug, url, path_to_plugin_list, has_api, created_at, updated_at
+RETURNING id, name, slug, url, path_to_plugin_list, has_api, created_at, updated_at, adapter
 `
 
 type UpdateOriginParams struct {
 	Url              string
 	PathToPluginList string
 	HasApi           int64
+	Adapter          string
 	Slug             string
 }
 
@@ -150,6 +181,7 @@ func (q *Queries) UpdateOrigin(ctx context.Context, arg UpdateOriginParams) (Plu
 		arg.Url,
 		arg.PathToPluginList,
 		arg.HasApi,
+		arg.Adapter,
 		arg.Slug,
 	)
 	var i PluginOrigin
@@ -162,6 +194,7 @@ func (q *Queries) UpdateOrigin(ctx context.Context, arg UpdateOriginParams) (Plu
 		&i.HasApi,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Adapter,
 	)
 	return i, err
 }
@@ -42,6 +42,37 @@ func (q *Queries) AddPluginChangelog(ctx context.Context, arg AddPluginChangelog
 	return i, err
 }
 
+const deletePluginChangelog = `-- name: DeletePluginChangelog :one
+DELETE
+FROM plugin_changelogs
+WHERE plugin_changelogs.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+RETURNING id, plugin_id, version, changelog, update_date, created_at, updated_at
+`
+
+type DeletePluginChangelogParams struct {
+	ID   int64
+	Slug string
+}
+
+func (q *Queries) DeletePluginChangelog(ctx context.Context, arg DeletePluginChangelogParams) (PluginChangelog, error) {
+	row := q.db.QueryRowContext(ctx, deletePluginChangelog, arg.ID, arg.Slug)
+	var i PluginChangelog
+	err := row.Scan(
+		&i.ID,
+		&i.PluginID,
+		&i.Version,
+		&i.Changelog,
+		&i.UpdateDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const getPluginChangelog = `-- name: GetPluginChangelog :many
 SELECT id, plugin_id, version, changelog, update_date, created_at, updated_at
 FROM plugin_changelogs
@@ -83,3 +114,76 @@ func (q *Queries) GetPluginChangelog(ctx context.Context, slug string) ([]Plugin
 	}
 	return items, nil
 }
+
+const getPluginChangelogEntry = `-- name: GetPluginChangelogEntry :one
+SELECT id, plugin_id, version, changelog, update_date, created_at, updated_at
+FROM plugin_changelogs
+WHERE plugin_changelogs.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+`
+
+type GetPluginChangelogEntryParams struct {
+	ID   int64
+	Slug string
+}
+
+func (q *Queries) GetPluginChangelogEntry(ctx context.Context, arg GetPluginChangelogEntryParams) (PluginChangelog, error) {
+	row := q.db.QueryRowContext(ctx, getPluginChangelogEntry, arg.ID, arg.Slug)
+	var i PluginChangelog
+	err := row.Scan(
+		&i.ID,
+		&i.PluginID,
+		&i.Version,
+		&i.Changelog,
+		&i.UpdateDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updatePluginChangelog = `-- name: UpdatePluginChangelog :one
+UPDATE plugin_changelogs
+SET version = ?,
+    changelog = ?,
+    update_date = ?,
+    updated_at = datetime('now')
+WHERE plugin_changelogs.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+RETURNING id, plugin_id, version, changelog, update_date, created_at, updated_at
+`
+
+type UpdatePluginChangelogParams struct {
+	Version    string
+	Changelog  string
+	UpdateDate string
+	ID         int64
+	Slug       string
+}
+
+func (q *Queries) UpdatePluginChangelog(ctx context.Context, arg UpdatePluginChangelogParams) (PluginChangelog, error) {
+	row := q.db.QueryRowContext(ctx, updatePluginChangelog,
+		arg.Version,
+		arg.Changelog,
+		arg.UpdateDate,
+		arg.ID,
+		arg.Slug,
+	)
+	var i PluginChangelog
+	err := row.Scan(
+		&i.ID,
+		&i.PluginID,
+		&i.Version,
+		&i.Changelog,
+		&i.UpdateDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
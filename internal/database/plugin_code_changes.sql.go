@@ -0,0 +1,337 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: plugin_code_changes.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+)
+
+const addPluginCodeChange = `-- name: AddPluginCodeChange :one
+INSERT INTO plugin_manual_code_changes(plugin_id, modified_part, row_start, row_end, comment, is_relevant, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))
+RETURNING id, plugin_id, modified_part, row_start, row_end, comment, is_relevant, created_at, updated_at, source_id, hunk
+`
+
+type AddPluginCodeChangeParams struct {
+	PluginID     int64
+	ModifiedPart string
+	RowStart     int64
+	RowEnd       int64
+	Comment      string
+	IsRelevant   int64
+}
+
+func (q *Queries) AddPluginCodeChange(ctx context.Context, arg AddPluginCodeChangeParams) (PluginManualCodeChange, error) {
+	row := q.db.QueryRowContext(ctx, addPluginCodeChange,
+		arg.PluginID,
+		arg.ModifiedPart,
+		arg.RowStart,
+		arg.RowEnd,
+		arg.Comment,
+		arg.IsRelevant,
+	)
+	var i PluginManualCodeChange
+	err := row.Scan(
+		&i.ID,
+		&i.PluginID,
+		&i.ModifiedPart,
+		&i.RowStart,
+		&i.RowEnd,
+		&i.Comment,
+		&i.IsRelevant,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SourceID,
+		&i.Hunk,
+	)
+	return i, err
+}
+
+const addPluginCodeHunk = `-- name: AddPluginCodeHunk :one
+INSERT INTO plugin_manual_code_changes(plugin_id, source_id, hunk, modified_part, row_start, row_end, comment, is_relevant, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, 1, datetime('now'), datetime('now'))
+RETURNING id, plugin_id, modified_part, row_start, row_end, comment, is_relevant, created_at, updated_at, source_id, hunk
+`
+
+type AddPluginCodeHunkParams struct {
+	PluginID     int64
+	SourceID     sql.NullInt64
+	Hunk         string
+	ModifiedPart string
+	RowStart     int64
+	RowEnd       int64
+	Comment      string
+}
+
+func (q *Queries) AddPluginCodeHunk(ctx context.Context, arg AddPluginCodeHunkParams) (PluginManualCodeChange, error) {
+	row := q.db.QueryRowContext(ctx, addPluginCodeHunk,
+		arg.PluginID,
+		arg.SourceID,
+		arg.Hunk,
+		arg.ModifiedPart,
+		arg.RowStart,
+		arg.RowEnd,
+		arg.Comment,
+	)
+	var i PluginManualCodeChange
+	err := row.Scan(
+		&i.ID,
+		&i.PluginID,
+		&i.ModifiedPart,
+		&i.RowStart,
+		&i.RowEnd,
+		&i.Comment,
+		&i.IsRelevant,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SourceID,
+		&i.Hunk,
+	)
+	return i, err
+}
+
+const deletePluginCodeChange = `-- name: DeletePluginCodeChange :one
+DELETE
+FROM plugin_manual_code_changes
+WHERE plugin_manual_code_changes.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+RETURNING id, plugin_id, modified_part, row_start, row_end, comment, is_relevant, created_at, updated_at, source_id, hunk
+`
+
+type DeletePluginCodeChangeParams struct {
+	ID   int64
+	Slug string
+}
+
+func (q *Queries) DeletePluginCodeChange(ctx context.Context, arg DeletePluginCodeChangeParams) (PluginManualCodeChange, error) {
+	row := q.db.QueryRowContext(ctx, deletePluginCodeChange, arg.ID, arg.Slug)
+	var i PluginManualCodeChange
+	err := row.Scan(
+		&i.ID,
+		&i.PluginID,
+		&i.ModifiedPart,
+		&i.RowStart,
+		&i.RowEnd,
+		&i.Comment,
+		&i.IsRelevant,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SourceID,
+		&i.Hunk,
+	)
+	return i, err
+}
+
+const getPluginCodeChange = `-- name: GetPluginCodeChange :one
+SELECT id, plugin_id, modified_part, row_start, row_end, comment, is_relevant, created_at, updated_at, source_id, hunk
+FROM plugin_manual_code_changes
+WHERE plugin_manual_code_changes.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+`
+
+type GetPluginCodeChangeParams struct {
+	ID   int64
+	Slug string
+}
+
+func (q *Queries) GetPluginCodeChange(ctx context.Context, arg GetPluginCodeChangeParams) (PluginManualCodeChange, error) {
+	row := q.db.QueryRowContext(ctx, getPluginCodeChange, arg.ID, arg.Slug)
+	var i PluginManualCodeChange
+	err := row.Scan(
+		&i.ID,
+		&i.PluginID,
+		&i.ModifiedPart,
+		&i.RowStart,
+		&i.RowEnd,
+		&i.Comment,
+		&i.IsRelevant,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SourceID,
+		&i.Hunk,
+	)
+	return i, err
+}
+
+const getPluginCodeChanges = `-- name: GetPluginCodeChanges :many
+SELECT id, plugin_id, modified_part, row_start, row_end, comment, is_relevant, created_at, updated_at, source_id, hunk
+FROM plugin_manual_code_changes
+WHERE plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+ORDER BY row_start, id
+`
+
+func (q *Queries) GetPluginCodeChanges(ctx context.Context, slug string) ([]PluginManualCodeChange, error) {
+	rows, err := q.db.QueryContext(ctx, getPluginCodeChanges, slug)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PluginManualCodeChange
+	for rows.Next() {
+		var i PluginManualCodeChange
+		if err := rows.Scan(
+			&i.ID,
+			&i.PluginID,
+			&i.ModifiedPart,
+			&i.RowStart,
+			&i.RowEnd,
+			&i.Comment,
+			&i.IsRelevant,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SourceID,
+			&i.Hunk,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPluginCodeHunks = `-- name: GetPluginCodeHunks :many
+SELECT id, plugin_id, modified_part, row_start, row_end, comment, is_relevant, created_at, updated_at, source_id, hunk
+FROM plugin_manual_code_changes
+WHERE hunk != '' AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+ORDER BY row_start, id
+`
+
+func (q *Queries) GetPluginCodeHunks(ctx context.Context, slug string) ([]PluginManualCodeChange, error) {
+	rows, err := q.db.QueryContext(ctx, getPluginCodeHunks, slug)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PluginManualCodeChange
+	for rows.Next() {
+		var i PluginManualCodeChange
+		if err := rows.Scan(
+			&i.ID,
+			&i.PluginID,
+			&i.ModifiedPart,
+			&i.RowStart,
+			&i.RowEnd,
+			&i.Comment,
+			&i.IsRelevant,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SourceID,
+			&i.Hunk,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setPluginCodeChangeRelevance = `-- name: SetPluginCodeChangeRelevance :exec
+UPDATE plugin_manual_code_changes
+SET is_relevant = ?,
+    row_start = ?,
+    row_end = ?,
+    updated_at = datetime('now')
+WHERE id = ?
+`
+
+type SetPluginCodeChangeRelevanceParams struct {
+	IsRelevant int64
+	RowStart   int64
+	RowEnd     int64
+	ID         int64
+}
+
+func (q *Queries) SetPluginCodeChangeRelevance(ctx context.Context, arg SetPluginCodeChangeRelevanceParams) error {
+	_, err := q.db.ExecContext(ctx, setPluginCodeChangeRelevance,
+		arg.IsRelevant,
+		arg.RowStart,
+		arg.RowEnd,
+		arg.ID,
+	)
+	return err
+}
+
+const updatePluginCodeChange = `-- name: UpdatePluginCodeChange :one
+UPDATE plugin_manual_code_changes
+SET hunk = CASE WHEN modified_part = ?1 THEN hunk ELSE '' END,
+    modified_part = ?1,
+    row_start = ?2,
+    row_end = ?3,
+    comment = ?4,
+    is_relevant = ?5,
+    updated_at = datetime('now')
+WHERE plugin_manual_code_changes.id = ?6 AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?7
+)
+RETURNING id, plugin_id, modified_part, row_start, row_end, comment, is_relevant, created_at, updated_at, source_id, hunk
+`
+
+type UpdatePluginCodeChangeParams struct {
+	ModifiedPart string
+	RowStart     int64
+	RowEnd       int64
+	Comment      string
+	IsRelevant   int64
+	ID           int64
+	Slug         string
+}
+
+// the recorded hunk is dropped once the modified part is edited
+func (q *Queries) UpdatePluginCodeChange(ctx context.Context, arg UpdatePluginCodeChangeParams) (PluginManualCodeChange, error) {
+	row := q.db.QueryRowContext(ctx, updatePluginCodeChange,
+		arg.ModifiedPart,
+		arg.RowStart,
+		arg.RowEnd,
+		arg.Comment,
+		arg.IsRelevant,
+		arg.ID,
+		arg.Slug,
+	)
+	var i PluginManualCodeChange
+	err := row.Scan(
+		&i.ID,
+		&i.PluginID,
+		&i.ModifiedPart,
+		&i.RowStart,
+		&i.RowEnd,
+		&i.Comment,
+		&i.IsRelevant,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SourceID,
+		&i.Hunk,
+	)
+	return i, err
+}
@@ -10,19 +10,33 @@ import (
 )
 
 const addPluginCommand = `-- name: AddPluginCommand :one
-INSERT INTO plugin_commands(plugin_id, command, description, created_at, updated_at)
-VALUES (?, ?, ?, datetime('now'), datetime('now'))
-RETURNING id, plugin_id, command, description, created_at, updated_at
+INSERT INTO plugin_commands(plugin_id, command, cmd_type, args, aliases, permission, description, position, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))
+RETURNING id, plugin_id, command, description, created_at, updated_at, cmd_type, args, aliases, permission, position
 `
 
 type AddPluginCommandParams struct {
 	PluginID    int64
 	Command     string
+	CmdType     string
+	Args        string
+	Aliases     string
+	Permission  string
 	Description string
+	Position    int64
 }
 
 func (q *Queries) AddPluginCommand(ctx context.Context, arg AddPluginCommandParams) (PluginCommand, error) {
-	row := q.db.QueryRowContext(ctx, addPluginCommand, arg.PluginID, arg.Command, arg.Description)
+	row := q.db.QueryRowContext(ctx, addPluginCommand,
+		arg.PluginID,
+		arg.Command,
+		arg.CmdType,
+		arg.Args,
+		arg.Aliases,
+		arg.Permission,
+		arg.Description,
+		arg.Position,
+	)
 	var i PluginCommand
 	err := row.Scan(
 		&i.ID,
@@ -31,6 +45,11 @@ func (q *Queries) AddPluginCommand(ctx context.Context, arg AddPluginCommandPara
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CmdType,
+		&i.Args,
+		&i.Aliases,
+		&i.Permission,
+		&i.Position,
 	)
 	return i, err
 }
@@ -38,12 +57,79 @@ func (q *Queries) AddPluginCommand(ctx context.Context, arg AddPluginCommandPara
 const deletePluginCommand = `-- name: DeletePluginCommand :one
 DELETE
 FROM plugin_commands
+WHERE plugin_commands.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+RETURNING id, plugin_id, command, description, created_at, updated_at, cmd_type, args, aliases, permission, position
+`
+
+type DeletePluginCommandParams struct {
+	ID   int64
+	Slug string
+}
+
+func (q *Queries) DeletePluginCommand(ctx context.Context, arg DeletePluginCommandParams) (PluginCommand, error) {
+	row := q.db.QueryRowContext(ctx, deletePluginCommand, arg.ID, arg.Slug)
+	var i PluginCommand
+	err := row.Scan(
+		&i.ID,
+		&i.PluginID,
+		&i.Command,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CmdType,
+		&i.Args,
+		&i.Aliases,
+		&i.Permission,
+		&i.Position,
+	)
+	return i, err
+}
+
+const deletePluginCommands = `-- name: DeletePluginCommands :exec
+DELETE
+FROM plugin_commands
+WHERE plugin_id = ?
+`
+
+func (q *Queries) DeletePluginCommands(ctx context.Context, pluginID int64) error {
+	_, err := q.db.ExecContext(ctx, deletePluginCommands, pluginID)
+	return err
+}
+
+const getLastPluginCommandPosition = `-- name: GetLastPluginCommandPosition :one
+SELECT CAST(COALESCE(MAX(position), 0) AS INTEGER)
+FROM plugin_commands
 WHERE plugin_id = ?
-RETURNING id, plugin_id, command, description, created_at, updated_at
 `
 
-func (q *Queries) DeletePluginCommand(ctx context.Context, pluginID int64) (PluginCommand, error) {
-	row := q.db.QueryRowContext(ctx, deletePluginCommand, pluginID)
+func (q *Queries) GetLastPluginCommandPosition(ctx context.Context, pluginID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getLastPluginCommandPosition, pluginID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const getPluginCommand = `-- name: GetPluginCommand :one
+SELECT id, plugin_id, command, description, created_at, updated_at, cmd_type, args, aliases, permission, position
+FROM plugin_commands
+WHERE plugin_commands.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+`
+
+type GetPluginCommandParams struct {
+	ID   int64
+	Slug string
+}
+
+func (q *Queries) GetPluginCommand(ctx context.Context, arg GetPluginCommandParams) (PluginCommand, error) {
+	row := q.db.QueryRowContext(ctx, getPluginCommand, arg.ID, arg.Slug)
 	var i PluginCommand
 	err := row.Scan(
 		&i.ID,
@@ -52,18 +138,24 @@ func (q *Queries) DeletePluginCommand(ctx context.Context, pluginID int64) (Plug
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CmdType,
+		&i.Args,
+		&i.Aliases,
+		&i.Permission,
+		&i.Position,
 	)
 	return i, err
 }
 
 const getPluginCommands = `-- name: GetPluginCommands :many
-SELECT id, plugin_id, command, description, created_at, updated_at
+SELECT id, plugin_id, command, description, created_at, updated_at, cmd_type, args, aliases, permission, position
 FROM plugin_commands
 WHERE plugin_id = (
     SELECT id
     FROM plugins
     WHERE slug = ?
 )
+ORDER BY position, id
 `
 
 func (q *Queries) GetPluginCommands(ctx context.Context, slug string) ([]PluginCommand, error) {
@@ -82,6 +174,11 @@ func (q *Queries) GetPluginCommands(ctx context.Context, slug string) ([]PluginC
 			&i.Description,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CmdType,
+			&i.Args,
+			&i.Aliases,
+			&i.Permission,
+			&i.Position,
 		); err != nil {
 			return nil, err
 		}
@@ -95,3 +192,59 @@ func (q *Queries) GetPluginCommands(ctx context.Context, slug string) ([]PluginC
 	}
 	return items, nil
 }
+
+const updatePluginCommand = `-- name: UpdatePluginCommand :one
+UPDATE plugin_commands
+SET command = ?,
+    cmd_type = ?,
+    args = ?,
+    aliases = ?,
+    permission = ?,
+    description = ?,
+    updated_at = datetime('now')
+WHERE plugin_commands.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+RETURNING id, plugin_id, command, description, created_at, updated_at, cmd_type, args, aliases, permission, position
+`
+
+type UpdatePluginCommandParams struct {
+	Command     string
+	CmdType     string
+	Args        string
+	Aliases     string
+	Permission  string
+	Description string
+	ID          int64
+	Slug        string
+}
+
+func (q *Queries) UpdatePluginCommand(ctx context.Context, arg UpdatePluginCommandParams) (PluginCommand, error) {
+	row := q.db.QueryRowContext(ctx, updatePluginCommand,
+		arg.Command,
+		arg.CmdType,
+		arg.Args,
+		arg.Aliases,
+		arg.Permission,
+		arg.Description,
+		arg.ID,
+		arg.Slug,
+	)
+	var i PluginCommand
+	err := row.Scan(
+		&i.ID,
+		&i.PluginID,
+		&i.Command,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CmdType,
+		&i.Args,
+		&i.Aliases,
+		&i.Permission,
+		&i.Position,
+	)
+	return i, err
+}
@@ -82,6 +82,24 @@ func (q *Queries) GetPluginConfig(ctx context.Context, slug string) (PluginConfi
 	return i, err
 }
 
+const setPluginConfig = `-- name: SetPluginConfig :exec
+INSERT INTO plugin_configs(plugin_id, config_json, created_at, updated_at)
+VALUES (?, ?, datetime('now'), datetime('now'))
+ON CONFLICT (plugin_id) DO UPDATE
+SET config_json = excluded.config_json,
+    updated_at = datetime('now')
+`
+
+type SetPluginConfigParams struct {
+	PluginID   int64
+	ConfigJson string
+}
+
+func (q *Queries) SetPluginConfig(ctx context.Context, arg SetPluginConfigParams) error {
+	_, err := q.db.ExecContext(ctx, setPluginConfig, arg.PluginID, arg.ConfigJson)
+	return err
+}
+
 const updatePluginConfig = `-- name: UpdatePluginConfig :one
 UPDATE plugin_configs
 SET config_json = ?, updated_at = datetime('now')
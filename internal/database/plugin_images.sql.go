@@ -0,0 +1,318 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: plugin_images.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+)
+
+const addPluginImage = `-- name: AddPluginImage :one
+INSERT INTO plugin_images(plugin_id, file_name, mime_type, size, width, height, sha256, image, thumbnail, thumbnail_type, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))
+RETURNING id
+`
+
+type AddPluginImageParams struct {
+	PluginID      int64
+	FileName      string
+	MimeType      string
+	Size          int64
+	Width         int64
+	Height        int64
+	Sha256        string
+	Image         []byte
+	Thumbnail     []byte
+	ThumbnailType string
+}
+
+func (q *Queries) AddPluginImage(ctx context.Context, arg AddPluginImageParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, addPluginImage,
+		arg.PluginID,
+		arg.FileName,
+		arg.MimeType,
+		arg.Size,
+		arg.Width,
+		arg.Height,
+		arg.Sha256,
+		arg.Image,
+		arg.Thumbnail,
+		arg.ThumbnailType,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const clearPluginCover = `-- name: ClearPluginCover :exec
+UPDATE plugin_images
+SET is_cover = 0,
+    updated_at = datetime('now')
+WHERE is_cover = 1 AND plugin_id = ?
+`
+
+func (q *Queries) ClearPluginCover(ctx context.Context, pluginID int64) error {
+	_, err := q.db.ExecContext(ctx, clearPluginCover, pluginID)
+	return err
+}
+
+const deletePluginImage = `-- name: DeletePluginImage :one
+DELETE
+FROM plugin_images
+WHERE plugin_images.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+RETURNING id, plugin_id
+`
+
+type DeletePluginImageParams struct {
+	ID   int64
+	Slug string
+}
+
+type DeletePluginImageRow struct {
+	ID       int64
+	PluginID int64
+}
+
+func (q *Queries) DeletePluginImage(ctx context.Context, arg DeletePluginImageParams) (DeletePluginImageRow, error) {
+	row := q.db.QueryRowContext(ctx, deletePluginImage, arg.ID, arg.Slug)
+	var i DeletePluginImageRow
+	err := row.Scan(&i.ID, &i.PluginID)
+	return i, err
+}
+
+const getPluginImage = `-- name: GetPluginImage :one
+SELECT id, file_name, mime_type, sha256, image, created_at
+FROM plugin_images
+WHERE plugin_images.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+`
+
+type GetPluginImageParams struct {
+	ID   int64
+	Slug string
+}
+
+type GetPluginImageRow struct {
+	ID        int64
+	FileName  string
+	MimeType  string
+	Sha256    string
+	Image     []byte
+	CreatedAt string
+}
+
+func (q *Queries) GetPluginImage(ctx context.Context, arg GetPluginImageParams) (GetPluginImageRow, error) {
+	row := q.db.QueryRowContext(ctx, getPluginImage, arg.ID, arg.Slug)
+	var i GetPluginImageRow
+	err := row.Scan(
+		&i.ID,
+		&i.FileName,
+		&i.MimeType,
+		&i.Sha256,
+		&i.Image,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPluginImageThumbnail = `-- name: GetPluginImageThumbnail :one
+SELECT id, file_name, mime_type, sha256, image, thumbnail, thumbnail_type, created_at
+FROM plugin_images
+WHERE plugin_images.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+`
+
+type GetPluginImageThumbnailParams struct {
+	ID   int64
+	Slug string
+}
+
+type GetPluginImageThumbnailRow struct {
+	ID            int64
+	FileName      string
+	MimeType      string
+	Sha256        string
+	Image         []byte
+	Thumbnail     []byte
+	ThumbnailType string
+	CreatedAt     string
+}
+
+func (q *Queries) GetPluginImageThumbnail(ctx context.Context, arg GetPluginImageThumbnailParams) (GetPluginImageThumbnailRow, error) {
+	row := q.db.QueryRowContext(ctx, getPluginImageThumbnail, arg.ID, arg.Slug)
+	var i GetPluginImageThumbnailRow
+	err := row.Scan(
+		&i.ID,
+		&i.FileName,
+		&i.MimeType,
+		&i.Sha256,
+		&i.Image,
+		&i.Thumbnail,
+		&i.ThumbnailType,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPluginImages = `-- name: GetPluginImages :many
+SELECT id, plugin_id, file_name, mime_type, size, width, height, is_cover, created_at
+FROM plugin_images
+WHERE plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+ORDER BY is_cover DESC, id
+`
+
+type GetPluginImagesRow struct {
+	ID        int64
+	PluginID  int64
+	FileName  string
+	MimeType  string
+	Size      int64
+	Width     int64
+	Height    int64
+	IsCover   int64
+	CreatedAt string
+}
+
+func (q *Queries) GetPluginImages(ctx context.Context, slug string) ([]GetPluginImagesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPluginImages, slug)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPluginImagesRow
+	for rows.Next() {
+		var i GetPluginImagesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.PluginID,
+			&i.FileName,
+			&i.MimeType,
+			&i.Size,
+			&i.Width,
+			&i.Height,
+			&i.IsCover,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPluginsWithCovers = `-- name: GetPluginsWithCovers :many
+SELECT plugins.id, plugins.name, plugins.slug, plugins.description, plugins.url, plugins.origin_id, plugins.is_updated_on_server, plugins.created_at, plugins.updated_at, plugin_images.id AS cover_image_id
+FROM plugins
+LEFT JOIN plugin_images ON plugin_images.plugin_id = plugins.id AND plugin_images.is_cover = 1
+ORDER BY plugins.name
+`
+
+type GetPluginsWithCoversRow struct {
+	ID                int64
+	Name              string
+	Slug              string
+	Description       string
+	Url               string
+	OriginID          int64
+	IsUpdatedOnServer int64
+	CreatedAt         string
+	UpdatedAt         string
+	CoverImageID      sql.NullInt64
+}
+
+func (q *Queries) GetPluginsWithCovers(ctx context.Context) ([]GetPluginsWithCoversRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPluginsWithCovers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPluginsWithCoversRow
+	for rows.Next() {
+		var i GetPluginsWithCoversRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Description,
+			&i.Url,
+			&i.OriginID,
+			&i.IsUpdatedOnServer,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CoverImageID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setDefaultPluginCover = `-- name: SetDefaultPluginCover :exec
+UPDATE plugin_images
+SET is_cover = 1,
+    updated_at = datetime('now')
+WHERE plugin_images.id = (
+    SELECT min(id)
+    FROM plugin_images images
+    WHERE images.plugin_id = ?
+) AND NOT EXISTS (
+    SELECT 1
+    FROM plugin_images covers
+    WHERE covers.plugin_id = plugin_images.plugin_id AND covers.is_cover = 1
+)
+`
+
+func (q *Queries) SetDefaultPluginCover(ctx context.Context, pluginID int64) error {
+	_, err := q.db.ExecContext(ctx, setDefaultPluginCover, pluginID)
+	return err
+}
+
+const setPluginCover = `-- name: SetPluginCover :one
+UPDATE plugin_images
+SET is_cover = 1,
+    updated_at = datetime('now')
+WHERE plugin_images.id = ? AND plugin_id = ?
+RETURNING id
+`
+
+type SetPluginCoverParams struct {
+	ID       int64
+	PluginID int64
+}
+
+func (q *Queries) SetPluginCover(ctx context.Context, arg SetPluginCoverParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, setPluginCover, arg.ID, arg.PluginID)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
@@ -77,6 +77,51 @@ func (q *Queries) DeletePluginLocale(ctx context.Context, arg DeletePluginLocale
 	return i, err
 }
 
+const getAllPluginLocales = `-- name: GetAllPluginLocales :many
+SELECT plugins.name, plugins.slug, plugin_locales.plugin_id, plugin_locales.lang_code, plugin_locales.lang_name, plugin_locales.content_json, plugin_locales.created_at, plugin_locales.updated_at
+FROM plugin_locales
+JOIN plugins ON plugins.id = plugin_locales.plugin_id
+ORDER BY plugins.name, plugin_locales.lang_code
+`
+
+type GetAllPluginLocalesRow struct {
+	Name         string
+	Slug         string
+	PluginLocale PluginLocale
+}
+
+func (q *Queries) GetAllPluginLocales(ctx context.Context) ([]GetAllPluginLocalesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAllPluginLocales)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAllPluginLocalesRow
+	for rows.Next() {
+		var i GetAllPluginLocalesRow
+		if err := rows.Scan(
+			&i.Name,
+			&i.Slug,
+			&i.PluginLocale.PluginID,
+			&i.PluginLocale.LangCode,
+			&i.PluginLocale.LangName,
+			&i.PluginLocale.ContentJson,
+			&i.PluginLocale.CreatedAt,
+			&i.PluginLocale.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getPluginLocale = `-- name: GetPluginLocale :one
 SELECT plugin_id, lang_code, lang_name, content_json, created_at, updated_at
 FROM plugin_locales
@@ -146,6 +191,35 @@ func (q *Queries) GetPluginLocales(ctx context.Context, slug string) ([]PluginLo
 	return items, nil
 }
 
+const setPluginLocale = `-- name: SetPluginLocale :exec
+INSERT INTO plugin_locales(
+    plugin_id, lang_code,
+    lang_name, content_json,
+    created_at, updated_at
+)
+VALUES (?, ?, ?, ?, datetime('now'), datetime('now'))
+ON CONFLICT (plugin_id, lang_code) DO UPDATE
+SET content_json = excluded.content_json,
+    updated_at = datetime('now')
+`
+
+type SetPluginLocaleParams struct {
+	PluginID    int64
+	LangCode    string
+	LangName    string
+	ContentJson string
+}
+
+func (q *Queries) SetPluginLocale(ctx context.Context, arg SetPluginLocaleParams) error {
+	_, err := q.db.ExecContext(ctx, setPluginLocale,
+		arg.PluginID,
+		arg.LangCode,
+		arg.LangName,
+		arg.ContentJson,
+	)
+	return err
+}
+
 const updatePluginLocale = `-- name: UpdatePluginLocale :one
 UPDATE plugin_locales
 SET content_json = ?,
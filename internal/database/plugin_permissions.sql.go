@@ -0,0 +1,176 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: plugin_permissions.sql
+
+package database
+
+import (
+	"context"
+)
+
+const addPluginPermission = `-- name: AddPluginPermission :one
+INSERT INTO plugin_permissions(plugin_id, permission, description, created_at, updated_at)
+VALUES (?, ?, ?, datetime('now'), datetime('now'))
+ON CONFLICT(plugin_id, permission) DO NOTHING
+RETURNING id, plugin_id, permission, description, created_at, updated_at
+`
+
+type AddPluginPermissionParams struct {
+	PluginID    int64
+	Permission  string
+	Description string
+}
+
+func (q *Queries) AddPluginPermission(ctx context.Context, arg AddPluginPermissionParams) (PluginPermission, error) {
+	row := q.db.QueryRowContext(ctx, addPluginPermission, arg.PluginID, arg.Permission, arg.Description)
+	var i PluginPermission
+	err := row.Scan(
+		&i.ID,
+		&i.PluginID,
+		&i.Permission,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deletePluginPermission = `-- name: DeletePluginPermission :one
+DELETE
+FROM plugin_permissions
+WHERE plugin_permissions.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+RETURNING id, plugin_id, permission, description, created_at, updated_at
+`
+
+type DeletePluginPermissionParams struct {
+	ID   int64
+	Slug string
+}
+
+func (q *Queries) DeletePluginPermission(ctx context.Context, arg DeletePluginPermissionParams) (PluginPermission, error) {
+	row := q.db.QueryRowContext(ctx, deletePluginPermission, arg.ID, arg.Slug)
+	var i PluginPermission
+	err := row.Scan(
+		&i.ID,
+		&i.PluginID,
+		&i.Permission,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getPluginPermission = `-- name: GetPluginPermission :one
+SELECT id, plugin_id, permission, description, created_at, updated_at
+FROM plugin_permissions
+WHERE plugin_permissions.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+`
+
+type GetPluginPermissionParams struct {
+	ID   int64
+	Slug string
+}
+
+func (q *Queries) GetPluginPermission(ctx context.Context, arg GetPluginPermissionParams) (PluginPermission, error) {
+	row := q.db.QueryRowContext(ctx, getPluginPermission, arg.ID, arg.Slug)
+	var i PluginPermission
+	err := row.Scan(
+		&i.ID,
+		&i.PluginID,
+		&i.Permission,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getPluginPermissions = `-- name: GetPluginPermissions :many
+SELECT id, plugin_id, permission, description, created_at, updated_at
+FROM plugin_permissions
+WHERE plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+ORDER BY permission
+`
+
+func (q *Queries) GetPluginPermissions(ctx context.Context, slug string) ([]PluginPermission, error) {
+	rows, err := q.db.QueryContext(ctx, getPluginPermissions, slug)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PluginPermission
+	for rows.Next() {
+		var i PluginPermission
+		if err := rows.Scan(
+			&i.ID,
+			&i.PluginID,
+			&i.Permission,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updatePluginPermission = `-- name: UpdatePluginPermission :one
+UPDATE plugin_permissions
+SET permission = ?,
+    description = ?,
+    updated_at = datetime('now')
+WHERE plugin_permissions.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+RETURNING id, plugin_id, permission, description, created_at, updated_at
+`
+
+type UpdatePluginPermissionParams struct {
+	Permission  string
+	Description string
+	ID          int64
+	Slug        string
+}
+
+func (q *Queries) UpdatePluginPermission(ctx context.Context, arg UpdatePluginPermissionParams) (PluginPermission, error) {
+	row := q.db.QueryRowContext(ctx, updatePluginPermission,
+		arg.Permission,
+		arg.Description,
+		arg.ID,
+		arg.Slug,
+	)
+	var i PluginPermission
+	err := row.Scan(
+		&i.ID,
+		&i.PluginID,
+		&i.Permission,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
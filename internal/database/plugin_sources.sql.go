@@ -0,0 +1,229 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: plugin_sources.sql
+
+package database
+
+import (
+	"context"
+)
+
+const addSourceBlob = `-- name: AddSourceBlob :exec
+INSERT INTO source_blobs(sha256, content, size, created_at)
+VALUES (?, ?, ?, datetime('now'))
+ON CONFLICT (sha256) DO NOTHING
+`
+
+type AddSourceBlobParams struct {
+	Sha256  string
+	Content []byte
+	Size    int64
+}
+
+func (q *Queries) AddSourceBlob(ctx context.Context, arg AddSourceBlobParams) error {
+	_, err := q.db.ExecContext(ctx, addSourceBlob, arg.Sha256, arg.Content, arg.Size)
+	return err
+}
+
+const deletePluginSource = `-- name: DeletePluginSource :one
+DELETE
+FROM plugin_sources
+WHERE plugin_sources.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+RETURNING id, plugin_id, changelog_id, file_name, sha256, created_at, updated_at
+`
+
+type DeletePluginSourceParams struct {
+	ID   int64
+	Slug string
+}
+
+func (q *Queries) DeletePluginSource(ctx context.Context, arg DeletePluginSourceParams) (PluginSource, error) {
+	row := q.db.QueryRowContext(ctx, deletePluginSource, arg.ID, arg.Slug)
+	var i PluginSource
+	err := row.Scan(
+		&i.ID,
+		&i.PluginID,
+		&i.ChangelogID,
+		&i.FileName,
+		&i.Sha256,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteUnusedSourceBlobs = `-- name: DeleteUnusedSourceBlobs :exec
+DELETE
+FROM source_blobs
+WHERE sha256 NOT IN (
+    SELECT sha256
+    FROM plugin_sources
+)
+`
+
+func (q *Queries) DeleteUnusedSourceBlobs(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteUnusedSourceBlobs)
+	return err
+}
+
+const getPluginSource = `-- name: GetPluginSource :one
+SELECT plugin_sources.id, plugin_sources.plugin_id, plugin_sources.changelog_id, plugin_sources.file_name, plugin_sources.sha256, plugin_sources.created_at, plugin_sources.updated_at, plugin_changelogs.version, source_blobs.content
+FROM plugin_sources
+JOIN plugin_changelogs ON plugin_changelogs.id = plugin_sources.changelog_id
+JOIN source_blobs ON source_blobs.sha256 = plugin_sources.sha256
+WHERE plugin_sources.id = ? AND plugin_sources.plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+`
+
+type GetPluginSourceParams struct {
+	ID   int64
+	Slug string
+}
+
+type GetPluginSourceRow struct {
+	ID          int64
+	PluginID    int64
+	ChangelogID int64
+	FileName    string
+	Sha256      string
+	CreatedAt   string
+	UpdatedAt   string
+	Version     string
+	Content     []byte
+}
+
+func (q *Queries) GetPluginSource(ctx context.Context, arg GetPluginSourceParams) (GetPluginSourceRow, error) {
+	row := q.db.QueryRowContext(ctx, getPluginSource, arg.ID, arg.Slug)
+	var i GetPluginSourceRow
+	err := row.Scan(
+		&i.ID,
+		&i.PluginID,
+		&i.ChangelogID,
+		&i.FileName,
+		&i.Sha256,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.Content,
+	)
+	return i, err
+}
+
+const getPluginSourceFileName = `-- name: GetPluginSourceFileName :one
+SELECT CAST(COALESCE((
+    SELECT file_name
+    FROM plugin_sources
+    WHERE plugin_id = ?
+    ORDER BY updated_at DESC, id DESC
+    LIMIT 1
+), '') AS TEXT) AS file_name
+`
+
+func (q *Queries) GetPluginSourceFileName(ctx context.Context, pluginID int64) (string, error) {
+	row := q.db.QueryRowContext(ctx, getPluginSourceFileName, pluginID)
+	var file_name string
+	err := row.Scan(&file_name)
+	return file_name, err
+}
+
+const getPluginSources = `-- name: GetPluginSources :many
+SELECT plugin_sources.id, plugin_sources.plugin_id, plugin_sources.changelog_id, plugin_sources.file_name, plugin_sources.sha256, plugin_sources.created_at, plugin_sources.updated_at, plugin_changelogs.version, source_blobs.size
+FROM plugin_sources
+JOIN plugin_changelogs ON plugin_changelogs.id = plugin_sources.changelog_id
+JOIN source_blobs ON source_blobs.sha256 = plugin_sources.sha256
+WHERE plugin_sources.plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+`
+
+type GetPluginSourcesRow struct {
+	ID          int64
+	PluginID    int64
+	ChangelogID int64
+	FileName    string
+	Sha256      string
+	CreatedAt   string
+	UpdatedAt   string
+	Version     string
+	Size        int64
+}
+
+func (q *Queries) GetPluginSources(ctx context.Context, slug string) ([]GetPluginSourcesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPluginSources, slug)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPluginSourcesRow
+	for rows.Next() {
+		var i GetPluginSourcesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.PluginID,
+			&i.ChangelogID,
+			&i.FileName,
+			&i.Sha256,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Version,
+			&i.Size,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setPluginSource = `-- name: SetPluginSource :one
+INSERT INTO plugin_sources(plugin_id, changelog_id, file_name, sha256, created_at, updated_at)
+VALUES (?, ?, ?, ?, datetime('now'), datetime('now'))
+ON CONFLICT (changelog_id) DO UPDATE
+SET file_name = excluded.file_name,
+    sha256 = excluded.sha256,
+    updated_at = datetime('now')
+RETURNING id, plugin_id, changelog_id, file_name, sha256, created_at, updated_at
+`
+
+type SetPluginSourceParams struct {
+	PluginID    int64
+	ChangelogID int64
+	FileName    string
+	Sha256      string
+}
+
+func (q *Queries) SetPluginSource(ctx context.Context, arg SetPluginSourceParams) (PluginSource, error) {
+	row := q.db.QueryRowContext(ctx, setPluginSource,
+		arg.PluginID,
+		arg.ChangelogID,
+		arg.FileName,
+		arg.Sha256,
+	)
+	var i PluginSource
+	err := row.Scan(
+		&i.ID,
+		&i.PluginID,
+		&i.ChangelogID,
+		&i.FileName,
+		&i.Sha256,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
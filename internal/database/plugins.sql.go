@@ -72,6 +72,46 @@ func (q *Queries) DeletePlugin(ctx context.Context, slug string) (Plugin, error)
 	return i, err
 }
 
+const getOriginPlugins = `-- name: GetOriginPlugins :many
+SELECT id, name, slug, description, url, origin_id, is_updated_on_server, created_at, updated_at
+FROM plugins
+WHERE origin_id = ?
+ORDER BY name
+`
+
+func (q *Queries) GetOriginPlugins(ctx context.Context, originID int64) ([]Plugin, error) {
+	rows, err := q.db.QueryContext(ctx, getOriginPlugins, originID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Plugin
+	for rows.Next() {
+		var i Plugin
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Description,
+			&i.Url,
+			&i.OriginID,
+			&i.IsUpdatedOnServer,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getPlugin = `-- name: GetPlugin :one
 SELECT id, name, slug, description, url, origin_id, is_updated_on_server, created_at, updated_at
 FROM plugins
@@ -187,6 +227,18 @@ func (q *Queries) GetPlugins(ctx context.Context) ([]Plugin, error) {
 	return items, nil
 }
 
+const setPluginOutdated = `-- name: SetPluginOutdated :exec
+UPDATE plugins
+SET is_updated_on_server = 0,
+    updated_at = datetime('now')
+WHERE id = ?
+`
+
+func (q *Queries) SetPluginOutdated(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, setPluginOutdated, id)
+	return err
+}
+
 const updatePlugin = `-- name: UpdatePlugin :one
 UPDATE plugins
 SET description = ?,
@@ -228,3 +280,46 @@ func (q *Queries) UpdatePlugin(ctx context.Context, arg UpdatePluginParams) (Plu
 	)
 	return i, err
 }
+
+const upsertPlugin = `-- name: UpsertPlugin :one
+INSERT INTO plugins(name, slug, description, url, origin_id, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, datetime('now'), datetime('now'))
+ON CONFLICT(slug) DO UPDATE
+SET name = excluded.name,
+    description = excluded.description,
+    url = excluded.url,
+    updated_at = datetime('now')
+WHERE plugins.origin_id = excluded.origin_id
+RETURNING id, name, slug, description, url, origin_id, is_updated_on_server, created_at, updated_at
+`
+
+type UpsertPluginParams struct {
+	Name        string
+	Slug        string
+	Description string
+	Url         string
+	OriginID    int64
+}
+
+func (q *Queries) UpsertPlugin(ctx context.Context, arg UpsertPluginParams) (Plugin, error) {
+	row := q.db.QueryRowContext(ctx, upsertPlugin,
+		arg.Name,
+		arg.Slug,
+		arg.Description,
+		arg.Url,
+		arg.OriginID,
+	)
+	var i Plugin
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.Description,
+		&i.Url,
+		&i.OriginID,
+		&i.IsUpdatedOnServer,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
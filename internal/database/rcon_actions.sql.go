@@ -0,0 +1,208 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: rcon_actions.sql
+
+package database
+
+import (
+	"context"
+)
+
+const addRconAction = `-- name: AddRconAction :one
+INSERT INTO rcon_actions(server_id, plugin_id, action, command, actor, status, created_at)
+VALUES (?, ?, ?, ?, ?, 'running', datetime('now'))
+RETURNING id, server_id, plugin_id, "action", command, actor, status, output, created_at, finished_at
+`
+
+type AddRconActionParams struct {
+	ServerID int64
+	PluginID int64
+	Action   string
+	Command  string
+	Actor    string
+}
+
+func (q *Queries) AddRconAction(ctx context.Context, arg AddRconActionParams) (RconAction, error) {
+	row := q.db.QueryRowContext(ctx, addRconAction,
+		arg.ServerID,
+		arg.PluginID,
+		arg.Action,
+		arg.Command,
+		arg.Actor,
+	)
+	var i RconAction
+	err := row.Scan(
+		&i.ID,
+		&i.ServerID,
+		&i.PluginID,
+		&i.Action,
+		&i.Command,
+		&i.Actor,
+		&i.Status,
+		&i.Output,
+		&i.CreatedAt,
+		&i.FinishedAt,
+	)
+	return i, err
+}
+
+const appendRconActionOutput = `-- name: AppendRconActionOutput :exec
+UPDATE rcon_actions
+SET output = output || ?
+WHERE id = ?
+`
+
+type AppendRconActionOutputParams struct {
+	Output string
+	ID     int64
+}
+
+func (q *Queries) AppendRconActionOutput(ctx context.Context, arg AppendRconActionOutputParams) error {
+	_, err := q.db.ExecContext(ctx, appendRconActionOutput, arg.Output, arg.ID)
+	return err
+}
+
+const failUnfinishedRconActions = `-- name: FailUnfinishedRconActions :exec
+UPDATE rcon_actions
+SET status = 'failed',
+    output = output || ?,
+    finished_at = datetime('now')
+WHERE status = 'running'
+`
+
+func (q *Queries) FailUnfinishedRconActions(ctx context.Context, output string) error {
+	_, err := q.db.ExecContext(ctx, failUnfinishedRconActions, output)
+	return err
+}
+
+const finishRconAction = `-- name: FinishRconAction :exec
+UPDATE rcon_actions
+SET status = ?,
+    finished_at = datetime('now')
+WHERE id = ?
+`
+
+type FinishRconActionParams struct {
+	Status string
+	ID     int64
+}
+
+func (q *Queries) FinishRconAction(ctx context.Context, arg FinishRconActionParams) error {
+	_, err := q.db.ExecContext(ctx, finishRconAction, arg.Status, arg.ID)
+	return err
+}
+
+const getPluginRconActions = `-- name: GetPluginRconActions :many
+SELECT rcon_actions.id, rcon_actions.server_id, rcon_actions.plugin_id, rcon_actions."action", rcon_actions.command, rcon_actions.actor, rcon_actions.status, rcon_actions.output, rcon_actions.created_at, rcon_actions.finished_at, servers.name AS server_name, plugins.slug AS plugin_slug
+FROM rcon_actions
+JOIN servers ON servers.id = rcon_actions.server_id
+JOIN plugins ON plugins.id = rcon_actions.plugin_id
+WHERE plugins.slug = ?
+ORDER BY rcon_actions.created_at DESC, rcon_actions.id DESC
+LIMIT ?
+`
+
+type GetPluginRconActionsParams struct {
+	Slug  string
+	Limit int64
+}
+
+type GetPluginRconActionsRow struct {
+	ID         int64
+	ServerID   int64
+	PluginID   int64
+	Action     string
+	Command    string
+	Actor      string
+	Status     string
+	Output     string
+	CreatedAt  string
+	FinishedAt string
+	ServerName string
+	PluginSlug string
+}
+
+func (q *Queries) GetPluginRconActions(ctx context.Context, arg GetPluginRconActionsParams) ([]GetPluginRconActionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPluginRconActions, arg.Slug, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPluginRconActionsRow
+	for rows.Next() {
+		var i GetPluginRconActionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ServerID,
+			&i.PluginID,
+			&i.Action,
+			&i.Command,
+			&i.Actor,
+			&i.Status,
+			&i.Output,
+			&i.CreatedAt,
+			&i.FinishedAt,
+			&i.ServerName,
+			&i.PluginSlug,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRconAction = `-- name: GetRconAction :one
+SELECT rcon_actions.id, rcon_actions.server_id, rcon_actions.plugin_id, rcon_actions."action", rcon_actions.command, rcon_actions.actor, rcon_actions.status, rcon_actions.output, rcon_actions.created_at, rcon_actions.finished_at, servers.name AS server_name, plugins.slug AS plugin_slug
+FROM rcon_actions
+JOIN servers ON servers.id = rcon_actions.server_id
+JOIN plugins ON plugins.id = rcon_actions.plugin_id
+WHERE rcon_actions.id = ? AND plugins.slug = ?
+`
+
+type GetRconActionParams struct {
+	ID   int64
+	Slug string
+}
+
+type GetRconActionRow struct {
+	ID         int64
+	ServerID   int64
+	PluginID   int64
+	Action     string
+	Command    string
+	Actor      string
+	Status     string
+	Output     string
+	CreatedAt  string
+	FinishedAt string
+	ServerName string
+	PluginSlug string
+}
+
+func (q *Queries) GetRconAction(ctx context.Context, arg GetRconActionParams) (GetRconActionRow, error) {
+	row := q.db.QueryRowContext(ctx, getRconAction, arg.ID, arg.Slug)
+	var i GetRconActionRow
+	err := row.Scan(
+		&i.ID,
+		&i.ServerID,
+		&i.PluginID,
+		&i.Action,
+		&i.Command,
+		&i.Actor,
+		&i.Status,
+		&i.Output,
+		&i.CreatedAt,
+		&i.FinishedAt,
+		&i.ServerName,
+		&i.PluginSlug,
+	)
+	return i, err
+}
@@ -0,0 +1,153 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: server_snapshots.sql
+
+package database
+
+import (
+	"context"
+)
+
+const addServerSnapshot = `-- name: AddServerSnapshot :one
+INSERT INTO server_snapshots(server_id, created_at)
+VALUES (?, datetime('now'))
+RETURNING id, server_id, created_at
+`
+
+func (q *Queries) AddServerSnapshot(ctx context.Context, serverID int64) (ServerSnapshot, error) {
+	row := q.db.QueryRowContext(ctx, addServerSnapshot, serverID)
+	var i ServerSnapshot
+	err := row.Scan(&i.ID, &i.ServerID, &i.CreatedAt)
+	return i, err
+}
+
+const addServerSnapshotPlugin = `-- name: AddServerSnapshotPlugin :exec
+INSERT INTO server_snapshot_plugins(snapshot_id, title, version, author, hook_time, file_name, error)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+
+type AddServerSnapshotPluginParams struct {
+	SnapshotID int64
+	Title      string
+	Version    string
+	Author     string
+	HookTime   float64
+	FileName   string
+	Error      string
+}
+
+func (q *Queries) AddServerSnapshotPlugin(ctx context.Context, arg AddServerSnapshotPluginParams) error {
+	_, err := q.db.ExecContext(ctx, addServerSnapshotPlugin,
+		arg.SnapshotID,
+		arg.Title,
+		arg.Version,
+		arg.Author,
+		arg.HookTime,
+		arg.FileName,
+		arg.Error,
+	)
+	return err
+}
+
+const getLatestServerSnapshot = `-- name: GetLatestServerSnapshot :one
+SELECT id, server_id, created_at
+FROM server_snapshots
+WHERE server_id = ?
+ORDER BY created_at DESC, id DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestServerSnapshot(ctx context.Context, serverID int64) (ServerSnapshot, error) {
+	row := q.db.QueryRowContext(ctx, getLatestServerSnapshot, serverID)
+	var i ServerSnapshot
+	err := row.Scan(&i.ID, &i.ServerID, &i.CreatedAt)
+	return i, err
+}
+
+const getPluginFileNames = `-- name: GetPluginFileNames :many
+SELECT plugins.id, plugins.name, plugins.slug,
+    CAST(COALESCE((
+        SELECT file_name
+        FROM plugin_sources
+        WHERE plugin_sources.plugin_id = plugins.id
+        ORDER BY updated_at DESC, id DESC
+        LIMIT 1
+    ), '') AS TEXT) AS file_name
+FROM plugins
+ORDER BY plugins.name
+`
+
+type GetPluginFileNamesRow struct {
+	ID       int64
+	Name     string
+	Slug     string
+	FileName string
+}
+
+func (q *Queries) GetPluginFileNames(ctx context.Context) ([]GetPluginFileNamesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPluginFileNames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPluginFileNamesRow
+	for rows.Next() {
+		var i GetPluginFileNamesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.FileName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getServerSnapshotPlugins = `-- name: GetServerSnapshotPlugins :many
+SELECT id, snapshot_id, title, version, author, hook_time, file_name, error
+FROM server_snapshot_plugins
+WHERE snapshot_id = ?
+ORDER BY title
+`
+
+func (q *Queries) GetServerSnapshotPlugins(ctx context.Context, snapshotID int64) ([]ServerSnapshotPlugin, error) {
+	rows, err := q.db.QueryContext(ctx, getServerSnapshotPlugins, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ServerSnapshotPlugin
+	for rows.Next() {
+		var i ServerSnapshotPlugin
+		if err := rows.Scan(
+			&i.ID,
+			&i.SnapshotID,
+			&i.Title,
+			&i.Version,
+			&i.Author,
+			&i.HookTime,
+			&i.FileName,
+			&i.Error,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
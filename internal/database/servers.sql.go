@@ -0,0 +1,499 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: servers.sql
+
+package database
+
+import (
+	"context"
+)
+
+const addServer = `-- name: AddServer :one
+INSERT INTO servers(name, slug, framework, root_path, framework_path, notes, rcon_host, rcon_port, rcon_password, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))
+RETURNING id, name, slug, framework, root_path, framework_path, notes, created_at, updated_at, rcon_host, rcon_port, rcon_password
+`
+
+type AddServerParams struct {
+	Name          string
+	Slug          string
+	Framework     string
+	RootPath      string
+	FrameworkPath string
+	Notes         string
+	RconHost      string
+	RconPort      int64
+	RconPassword  string
+}
+
+func (q *Queries) AddServer(ctx context.Context, arg AddServerParams) (Server, error) {
+	row := q.db.QueryRowContext(ctx, addServer,
+		arg.Name,
+		arg.Slug,
+		arg.Framework,
+		arg.RootPath,
+		arg.FrameworkPath,
+		arg.Notes,
+		arg.RconHost,
+		arg.RconPort,
+		arg.RconPassword,
+	)
+	var i Server
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.Framework,
+		&i.RootPath,
+		&i.FrameworkPath,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RconHost,
+		&i.RconPort,
+		&i.RconPassword,
+	)
+	return i, err
+}
+
+const countServers = `-- name: CountServers :one
+SELECT count(*)
+FROM servers
+`
+
+func (q *Queries) CountServers(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countServers)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteServer = `-- name: DeleteServer :one
+DELETE
+FROM servers
+WHERE slug = ?
+RETURNING id, name, slug, framework, root_path, framework_path, notes, created_at, updated_at, rcon_host, rcon_port, rcon_password
+`
+
+func (q *Queries) DeleteServer(ctx context.Context, slug string) (Server, error) {
+	row := q.db.QueryRowContext(ctx, deleteServer, slug)
+	var i Server
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.Framework,
+		&i.RootPath,
+		&i.FrameworkPath,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RconHost,
+		&i.RconPort,
+		&i.RconPassword,
+	)
+	return i, err
+}
+
+const deleteServerPlugin = `-- name: DeleteServerPlugin :one
+DELETE
+FROM server_plugins
+WHERE server_id = ? AND plugin_id = ?
+RETURNING id, server_id, plugin_id, version, installed_at, created_at, updated_at
+`
+
+type DeleteServerPluginParams struct {
+	ServerID int64
+	PluginID int64
+}
+
+func (q *Queries) DeleteServerPlugin(ctx context.Context, arg DeleteServerPluginParams) (ServerPlugin, error) {
+	row := q.db.QueryRowContext(ctx, deleteServerPlugin, arg.ServerID, arg.PluginID)
+	var i ServerPlugin
+	err := row.Scan(
+		&i.ID,
+		&i.ServerID,
+		&i.PluginID,
+		&i.Version,
+		&i.InstalledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getChangelogVersions = `-- name: GetChangelogVersions :many
+SELECT plugin_id, version
+FROM plugin_changelogs
+`
+
+type GetChangelogVersionsRow struct {
+	PluginID int64
+	Version  string
+}
+
+func (q *Queries) GetChangelogVersions(ctx context.Context) ([]GetChangelogVersionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getChangelogVersions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetChangelogVersionsRow
+	for rows.Next() {
+		var i GetChangelogVersionsRow
+		if err := rows.Scan(&i.PluginID, &i.Version); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getInstalledVersions = `-- name: GetInstalledVersions :many
+SELECT plugin_id, version
+FROM server_plugins
+`
+
+type GetInstalledVersionsRow struct {
+	PluginID int64
+	Version  string
+}
+
+func (q *Queries) GetInstalledVersions(ctx context.Context) ([]GetInstalledVersionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getInstalledVersions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetInstalledVersionsRow
+	for rows.Next() {
+		var i GetInstalledVersionsRow
+		if err := rows.Scan(&i.PluginID, &i.Version); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPluginServers = `-- name: GetPluginServers :many
+SELECT servers.name, servers.slug, servers.rcon_host,
+    CAST(COALESCE(server_plugins.version, '') AS TEXT) AS version,
+    CAST(COALESCE(server_plugins.installed_at, '') AS TEXT) AS installed_at
+FROM servers
+LEFT JOIN server_plugins ON server_plugins.server_id = servers.id AND server_plugins.plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE plugins.slug = ?
+)
+ORDER BY servers.name
+`
+
+type GetPluginServersRow struct {
+	Name        string
+	Slug        string
+	RconHost    string
+	Version     string
+	InstalledAt string
+}
+
+func (q *Queries) GetPluginServers(ctx context.Context, slug string) ([]GetPluginServersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPluginServers, slug)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPluginServersRow
+	for rows.Next() {
+		var i GetPluginServersRow
+		if err := rows.Scan(
+			&i.Name,
+			&i.Slug,
+			&i.RconHost,
+			&i.Version,
+			&i.InstalledAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getServer = `-- name: GetServer :one
+SELECT id, name, slug, framework, root_path, framework_path, notes, created_at, updated_at, rcon_host, rcon_port, rcon_password
+FROM servers
+WHERE slug = ?
+`
+
+func (q *Queries) GetServer(ctx context.Context, slug string) (Server, error) {
+	row := q.db.QueryRowContext(ctx, getServer, slug)
+	var i Server
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.Framework,
+		&i.RootPath,
+		&i.FrameworkPath,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RconHost,
+		&i.RconPort,
+		&i.RconPassword,
+	)
+	return i, err
+}
+
+const getServerPlugin = `-- name: GetServerPlugin :one
+SELECT id, server_id, plugin_id, version, installed_at, created_at, updated_at
+FROM server_plugins
+WHERE server_id = ? AND plugin_id = ?
+`
+
+type GetServerPluginParams struct {
+	ServerID int64
+	PluginID int64
+}
+
+func (q *Queries) GetServerPlugin(ctx context.Context, arg GetServerPluginParams) (ServerPlugin, error) {
+	row := q.db.QueryRowContext(ctx, getServerPlugin, arg.ServerID, arg.PluginID)
+	var i ServerPlugin
+	err := row.Scan(
+		&i.ID,
+		&i.ServerID,
+		&i.PluginID,
+		&i.Version,
+		&i.InstalledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getServerPlugins = `-- name: GetServerPlugins :many
+SELECT server_plugins.id, server_plugins.server_id, server_plugins.plugin_id, server_plugins.version, server_plugins.installed_at, server_plugins.created_at, server_plugins.updated_at, plugins.name, plugins.slug
+FROM server_plugins
+JOIN plugins ON plugins.id = server_plugins.plugin_id
+WHERE server_plugins.server_id = ?
+ORDER BY plugins.name
+`
+
+type GetServerPluginsRow struct {
+	ID          int64
+	ServerID    int64
+	PluginID    int64
+	Version     string
+	InstalledAt string
+	CreatedAt   string
+	UpdatedAt   string
+	Name        string
+	Slug        string
+}
+
+func (q *Queries) GetServerPlugins(ctx context.Context, serverID int64) ([]GetServerPluginsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getServerPlugins, serverID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetServerPluginsRow
+	for rows.Next() {
+		var i GetServerPluginsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ServerID,
+			&i.PluginID,
+			&i.Version,
+			&i.InstalledAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Slug,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getServers = `-- name: GetServers :many
+SELECT servers.id, servers.name, servers.slug, servers.framework, servers.root_path, servers.framework_path, servers.notes, servers.created_at, servers.updated_at, servers.rcon_host, servers.rcon_port, servers.rcon_password, (
+    SELECT count(*)
+    FROM server_plugins
+    WHERE server_plugins.server_id = servers.id
+) AS plugin_count
+FROM servers
+ORDER BY name
+`
+
+type GetServersRow struct {
+	ID            int64
+	Name          string
+	Slug          string
+	Framework     string
+	RootPath      string
+	FrameworkPath string
+	Notes         string
+	CreatedAt     string
+	UpdatedAt     string
+	RconHost      string
+	RconPort      int64
+	RconPassword  string
+	PluginCount   int64
+}
+
+func (q *Queries) GetServers(ctx context.Context) ([]GetServersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getServers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetServersRow
+	for rows.Next() {
+		var i GetServersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Framework,
+			&i.RootPath,
+			&i.FrameworkPath,
+			&i.Notes,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.RconHost,
+			&i.RconPort,
+			&i.RconPassword,
+			&i.PluginCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setServerPlugin = `-- name: SetServerPlugin :one
+INSERT INTO server_plugins(server_id, plugin_id, version, installed_at, created_at, updated_at)
+VALUES (?, ?, ?, ?, datetime('now'), datetime('now'))
+ON CONFLICT (server_id, plugin_id) DO UPDATE
+SET version = excluded.version,
+    installed_at = excluded.installed_at,
+    updated_at = datetime('now')
+RETURNING id, server_id, plugin_id, version, installed_at, created_at, updated_at
+`
+
+type SetServerPluginParams struct {
+	ServerID    int64
+	PluginID    int64
+	Version     string
+	InstalledAt string
+}
+
+func (q *Queries) SetServerPlugin(ctx context.Context, arg SetServerPluginParams) (ServerPlugin, error) {
+	row := q.db.QueryRowContext(ctx, setServerPlugin,
+		arg.ServerID,
+		arg.PluginID,
+		arg.Version,
+		arg.InstalledAt,
+	)
+	var i ServerPlugin
+	err := row.Scan(
+		&i.ID,
+		&i.ServerID,
+		&i.PluginID,
+		&i.Version,
+		&i.InstalledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateServer = `-- name: UpdateServer :one
+UPDATE servers
+SET framework = ?,
+    root_path = ?,
+    framework_path = ?,
+    notes = ?,
+    rcon_host = ?,
+    rcon_port = ?,
+    rcon_password = ?,
+    updated_at = datetime('now')
+WHERE slug = ?
+RETURNING id, name, slug, framework, root_path, framework_path, notes, created_at, updated_at, rcon_host, rcon_port, rcon_password
+`
+
+type UpdateServerParams struct {
+	Framework     string
+	RootPath      string
+	FrameworkPath string
+	Notes         string
+	RconHost      string
+	RconPort      int64
+	RconPassword  string
+	Slug          string
+}
+
+func (q *Queries) UpdateServer(ctx context.Context, arg UpdateServerParams) (Server, error) {
+	row := q.db.QueryRowContext(ctx, updateServer,
+		arg.Framework,
+		arg.RootPath,
+		arg.FrameworkPath,
+		arg.Notes,
+		arg.RconHost,
+		arg.RconPort,
+		arg.RconPassword,
+		arg.Slug,
+	)
+	var i Server
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.Framework,
+		&i.RootPath,
+		&i.FrameworkPath,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RconHost,
+		&i.RconPort,
+		&i.RconPassword,
+	)
+	return i, err
+}
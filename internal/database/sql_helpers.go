@@ -6,21 +6,26 @@ import (
 	"strings"
 )
 
-const addPluginCommands = `INSERT INTO plugin_commands(plugin_id, command, description, created_at, updated_at)
+const addPluginCommands = `INSERT INTO plugin_commands(plugin_id, command, cmd_type, args, aliases, permission, description, position, created_at, updated_at)
 VALUES
 %s
-RETURNING id, plugin_id, command, description, created_at, updated_at
+RETURNING id, plugin_id, command, description, created_at, updated_at, cmd_type, args, aliases, permission, position
 `
 
 type AddPluginCommandsParams struct {
 	PluginID    int64
 	Command     string
+	CmdType     string
+	Args        string
+	Aliases     string
+	Permission  string
 	Description string
+	Position    int64
 }
 
 // Insert and return command list
 func (q *Queries) AddPluginCommands(ctx context.Context, args []AddPluginCommandsParams) (items []PluginCommand, err error) {
-	commandArgs := "(?, ?, ?, datetime('now'), datetime('now'))"
+	commandArgs := "(?, ?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))"
 	var valueStrings = []string{}
 	// create a vaulues slice for easier querying
 	var values = []any{} // since there are several data types
@@ -30,7 +35,12 @@ func (q *Queries) AddPluginCommands(ctx context.Context, args []AddPluginCommand
 		values = append(values,
 			arg.PluginID,
 			arg.Command,
+			arg.CmdType,
+			arg.Args,
+			arg.Aliases,
+			arg.Permission,
 			arg.Description,
+			arg.Position,
 		)
 	}
 	// create a VALUES string for query population
@@ -53,6 +63,11 @@ func (q *Queries) AddPluginCommands(ctx context.Context, args []AddPluginCommand
 			&item.Description,
 			&item.CreatedAt,
 			&item.UpdatedAt,
+			&item.CmdType,
+			&item.Args,
+			&item.Aliases,
+			&item.Permission,
+			&item.Position,
 		); err != nil {
 			return nil, err
 		}
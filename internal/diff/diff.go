@@ -0,0 +1,404 @@
+// Package diff compares texts line by line and formats the difference as
+// a unified diff.
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Kind of change of a line
+type Op int
+
+const (
+	Equal Op = iota
+	Delete
+	Insert
+)
+
+// Sign of the operation used in unified diffs
+func (op Op) String() string {
+	return [...]string{" ", "-", "+"}[op]
+}
+
+// Line of an edit script
+type Line struct {
+	Op   Op
+	Text string
+	// 1-based line numbers in the old and new texts, 0 if the line
+	// doesn't exist there
+	OldNumber int
+	NewNumber int
+}
+
+// Group of changed lines with surrounding context
+type Hunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+	Lines              []Line
+}
+
+// Split a text on lines ignoring a byte order mark and Windows line breaks
+func SplitLines(text string) []string {
+	text = strings.TrimPrefix(text, "\ufeff")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// Compute the shortest edit script turning lines a into lines b
+func Lines(a, b []string) []Line {
+	// common prefix and suffix don't need the diff algorithm
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	script := make([]Line, 0, len(a)+len(b))
+	for i := range prefix {
+		script = append(script, Line{Op: Equal, Text: a[i], OldNumber: i + 1, NewNumber: i + 1})
+	}
+	for _, line := range myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]) {
+		if line.OldNumber > 0 {
+			line.OldNumber += prefix
+		}
+		if line.NewNumber > 0 {
+			line.NewNumber += prefix
+		}
+		script = append(script, line)
+	}
+	for i := range suffix {
+		oldIndex, newIndex := len(a)-suffix+i, len(b)-suffix+i
+		script = append(script, Line{Op: Equal, Text: a[oldIndex], OldNumber: oldIndex + 1, NewNumber: newIndex + 1})
+	}
+
+	return script
+}
+
+// Myers' O(ND) algorithm in linear space, see "An O(ND) Difference
+// Algorithm and Its Variations": the middle snake of the shortest edit
+// script splits the texts into halves that are compared recursively.
+func myers(a, b []string) []Line {
+	d := differ{
+		a:      a,
+		b:      b,
+		script: make([]Line, 0, len(a)+len(b)),
+	}
+	// lines are compared by numbers, equal lines get the same one
+	ids := make(map[string]int)
+	d.aIDs, d.bIDs = lineIDs(a, ids), lineIDs(b, ids)
+	// diagonals -maxD-1..maxD+1 of the largest middle snake search
+	d.offset = (len(a)+len(b)+1)/2 + 1
+	d.forward = make([]int, 2*d.offset+1)
+	d.backward = make([]int, 2*d.offset+1)
+
+	d.compare(0, len(a), 0, len(b))
+	return d.script
+}
+
+// Number every line by its text
+func lineIDs(lines []string, ids map[string]int) []int {
+	numbers := make([]int, len(lines))
+	for i, line := range lines {
+		id, ok := ids[line]
+		if !ok {
+			id = len(ids)
+			ids[line] = id
+		}
+		numbers[i] = id
+	}
+
+	return numbers
+}
+
+// State of a linear space diff, the furthest reaching paths are
+// reused between recursive calls
+type differ struct {
+	a, b       []string
+	aIDs, bIDs []int
+	// furthest x on every diagonal k = x - y stored at k+offset,
+	// searching from the start and from the end of the texts
+	offset            int
+	forward, backward []int
+	script            []Line
+}
+
+// Append the edit script turning a[aLo:aHi] into b[bLo:bHi]
+func (d *differ) compare(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && d.aIDs[aLo] == d.bIDs[bLo] {
+		d.equal(aLo, bLo)
+		aLo++
+		bLo++
+	}
+	suffix := 0
+	for aLo < aHi-suffix && bLo < bHi-suffix && d.aIDs[aHi-1-suffix] == d.bIDs[bHi-1-suffix] {
+		suffix++
+	}
+	aHi, bHi = aHi-suffix, bHi-suffix
+
+	switch {
+	case aLo == aHi:
+		for y := bLo; y < bHi; y++ {
+			d.script = append(d.script, Line{Op: Insert, Text: d.b[y], NewNumber: y + 1})
+		}
+	case bLo == bHi:
+		for x := aLo; x < aHi; x++ {
+			d.script = append(d.script, Line{Op: Delete, Text: d.a[x], OldNumber: x + 1})
+		}
+	default:
+		// with both ends differing at least two edits are left,
+		// so both halves are smaller
+		x, y, u, v := d.middleSnake(aLo, aHi, bLo, bHi)
+		d.compare(aLo, x, bLo, y)
+		for ; x < u; x, y = x+1, y+1 {
+			d.equal(x, y)
+		}
+		d.compare(u, aHi, v, bHi)
+	}
+
+	for i := range suffix {
+		d.equal(aHi+i, bHi+i)
+	}
+}
+
+// Append equal lines a[x] and b[y]
+func (d *differ) equal(x, y int) {
+	d.script = append(d.script, Line{Op: Equal, Text: d.a[x], OldNumber: x + 1, NewNumber: y + 1})
+}
+
+// Find the snake from (x, y) to (u, v) in the middle of the shortest
+// edit script turning a[aLo:aHi] into b[bLo:bHi], searching from both
+// ends until the paths overlap
+func (d *differ) middleSnake(aLo, aHi, bLo, bHi int) (x, y, u, v int) {
+	n, m := aHi-aLo, bHi-bLo
+	// diagonal of the end point, backward diagonal k is forward delta-k
+	delta := n - m
+	odd := delta%2 != 0
+	forward := func(k int) *int { return &d.forward[k+d.offset] }
+	backward := func(k int) *int { return &d.backward[k+d.offset] }
+	*forward(1), *backward(1) = 0, 0
+
+	for step := 0; step <= (n+m+1)/2; step++ {
+		for k := -step; k <= step; k += 2 {
+			// step down inserting a line of b or right deleting a line of a
+			var x int
+			if k == -step || (k != step && *forward(k - 1) < *forward(k + 1)) {
+				x = *forward(k + 1)
+			} else {
+				x = *forward(k - 1) + 1
+			}
+			startX := x
+			for x < n && x-k < m && d.aIDs[aLo+x] == d.bIDs[bLo+x-k] {
+				x++
+			}
+			*forward(k) = x
+
+			if odd && delta-k >= -(step-1) && delta-k <= step-1 && x+*backward(delta - k) >= n {
+				return aLo + startX, bLo + startX - k, aLo + x, bLo + x - k
+			}
+		}
+
+		for k := -step; k <= step; k += 2 {
+			// same as forward with both texts read from the end
+			var x int
+			if k == -step || (k != step && *backward(k - 1) < *backward(k + 1)) {
+				x = *backward(k + 1)
+			} else {
+				x = *backward(k - 1) + 1
+			}
+			startX := x
+			for x < n && x-k < m && d.aIDs[aHi-1-x] == d.bIDs[bHi-1-(x-k)] {
+				x++
+			}
+			*backward(k) = x
+
+			if !odd && delta-k >= -step && delta-k <= step && x+*forward(delta - k) >= n {
+				return aHi - x, bHi - (x - k), aHi - startX, bHi - (startX - k)
+			}
+		}
+	}
+
+	// the paths always meet by the middle
+	panic("diff: no middle snake")
+}
+
+// Group changes of lines a and b into hunks with the given number of
+// context lines around them
+func Hunks(a, b []string, context int) (hunks []Hunk) {
+	script := Lines(a, b)
+
+	for i := 0; i < len(script); {
+		if script[i].Op == Equal {
+			i++
+			continue
+		}
+
+		// extend the hunk while the next change is close enough
+		start := max(i-context, 0)
+		end := i
+		for end < len(script) {
+			if script[end].Op != Equal {
+				end++
+				continue
+			}
+			nextChange := end
+			for nextChange < len(script) && script[nextChange].Op == Equal {
+				nextChange++
+			}
+			if nextChange == len(script) || nextChange-end > 2*context {
+				break
+			}
+			end = nextChange
+		}
+		end = min(end+context, len(script))
+
+		hunks = append(hunks, newHunk(script[start:end], script[:start]))
+		i = end
+	}
+
+	return hunks
+}
+
+// Create a hunk of lines counting line numbers of preceding lines
+func newHunk(lines, preceding []Line) Hunk {
+	hunk := Hunk{Lines: lines}
+	for _, line := range preceding {
+		if line.Op != Insert {
+			hunk.OldStart++
+		}
+		if line.Op != Delete {
+			hunk.NewStart++
+		}
+	}
+	for _, line := range lines {
+		if line.Op != Insert {
+			hunk.OldLines++
+		}
+		if line.Op != Delete {
+			hunk.NewLines++
+		}
+	}
+
+	// empty ranges start at the line before them, as diff(1) does
+	if hunk.OldLines > 0 {
+		hunk.OldStart++
+	}
+	if hunk.NewLines > 0 {
+		hunk.NewStart++
+	}
+
+	return hunk
+}
+
+// Header of the hunk like "@@ -1,3 +1,4 @@"
+func (h Hunk) Header() string {
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+}
+
+// Format the difference between lines a and b as a unified diff,
+// an empty string is returned for equal texts
+func Unified(oldName, newName string, a, b []string, context int) string {
+	hunks := Hunks(a, b, context)
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+	for _, hunk := range hunks {
+		sb.WriteString(hunk.String())
+	}
+
+	return sb.String()
+}
+
+// Hunk in the unified format: the header and lines prefixed with signs
+func (h Hunk) String() string {
+	var sb strings.Builder
+	sb.WriteString(h.Header())
+	sb.WriteByte('\n')
+	for _, line := range h.Lines {
+		sb.WriteString(line.Op.String())
+		sb.WriteString(line.Text)
+		sb.WriteByte('\n')
+	}
+
+	return sb.String()
+}
+
+// Matches a hunk header capturing starts and optional line counts
+var hunkHeaderPattern = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// Parse a single hunk in the unified format, line counts of the header
+// must match the lines
+func ParseHunk(text string) (hunk Hunk, err error) {
+	lines := SplitLines(text)
+	if len(lines) == 0 {
+		return hunk, errors.New("empty hunk")
+	}
+
+	match := hunkHeaderPattern.FindStringSubmatch(lines[0])
+	if match == nil {
+		return hunk, fmt.Errorf("invalid hunk header: %q", lines[0])
+	}
+	// counts are omitted for single lines
+	numbers := [4]int{0, 1, 0, 1}
+	for i, raw := range match[1:] {
+		if raw != "" {
+			numbers[i], _ = strconv.Atoi(raw)
+		}
+	}
+	hunk = Hunk{OldStart: numbers[0], OldLines: numbers[1], NewStart: numbers[2], NewLines: numbers[3]}
+
+	oldNumber, newNumber := max(hunk.OldStart, 1), max(hunk.NewStart, 1)
+	var oldCount, newCount int
+	for _, line := range lines[1:] {
+		// editors may strip the space of empty context lines
+		if line == "" {
+			line = " "
+		}
+
+		parsed := Line{Text: line[1:]}
+		switch line[0] {
+		case ' ':
+			parsed.Op = Equal
+		case '-':
+			parsed.Op = Delete
+		case '+':
+			parsed.Op = Insert
+		case '\\':
+			// "\ No newline at end of file"
+			continue
+		default:
+			return hunk, fmt.Errorf("invalid hunk line: %q", line)
+		}
+
+		if parsed.Op != Insert {
+			parsed.OldNumber = oldNumber
+			oldNumber++
+			oldCount++
+		}
+		if parsed.Op != Delete {
+			parsed.NewNumber = newNumber
+			newNumber++
+			newCount++
+		}
+		hunk.Lines = append(hunk.Lines, parsed)
+	}
+
+	if oldCount != hunk.OldLines || newCount != hunk.NewLines {
+		return hunk, fmt.Errorf("hunk %s has %d old and %d new lines", hunk.Header(), oldCount, newCount)
+	}
+
+	return hunk, nil
+}
@@ -0,0 +1,199 @@
+package diff
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name           string
+		old, new       string
+		context        int
+		expectedOutput string
+	}{
+		{name: "equal", old: "a\nb\n", new: "a\nb\n", context: 3, expectedOutput: ""},
+		{
+			name: "changed line", old: "a\nb\nc\n", new: "a\nB\nc\n", context: 3,
+			expectedOutput: "--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name: "added to empty", old: "", new: "a\nb\n", context: 3,
+			expectedOutput: "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name: "removed at end", old: "a\nb\nc\n", new: "a\nb\n", context: 1,
+			expectedOutput: "--- old\n+++ new\n@@ -2,2 +2,1 @@\n b\n-c\n",
+		},
+		{
+			name: "separate hunks", old: "1\n2\n3\n4\n5\n6\n7\n8\n", new: "0\n1\n2\n3\n4\n5\n6\n8\n", context: 1,
+			expectedOutput: "--- old\n+++ new\n@@ -1,1 +1,2 @@\n+0\n 1\n@@ -6,3 +7,2 @@\n 6\n-7\n 8\n",
+		},
+		{
+			name: "merged hunks", old: "1\n2\n3\n4\n", new: "0\n1\n2\n4\n", context: 1,
+			expectedOutput: "--- old\n+++ new\n@@ -1,4 +1,4 @@\n+0\n 1\n 2\n-3\n 4\n",
+		},
+		{
+			name: "line breaks and BOM ignored", old: "\ufeffa\r\nb\r\n", new: "a\nb\n", context: 3, expectedOutput: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := Unified("old", "new", SplitLines(test.old), SplitLines(test.new), test.context)
+			if output != test.expectedOutput {
+				t.Errorf("Unified() =\n%s\nwant\n%s", output, test.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestLines(t *testing.T) {
+	// the script must turn a into b with the minimal number of edits
+	a := strings.Split("a b c a b b a", " ")
+	b := strings.Split("c b a b a c", " ")
+
+	script := Lines(a, b)
+	var old, new []string
+	edits := 0
+	for _, line := range script {
+		if line.Op != Insert {
+			old = append(old, line.Text)
+		}
+		if line.Op != Delete {
+			new = append(new, line.Text)
+		}
+		if line.Op != Equal {
+			edits++
+		}
+	}
+
+	if !slices.Equal(old, a) || !slices.Equal(new, b) {
+		t.Errorf("Lines() script produces %v → %v, want %v → %v", old, new, a, b)
+	}
+	if edits != 5 {
+		t.Errorf("Lines() made %d edits, want 5", edits)
+	}
+}
+
+func TestLinesRandom(t *testing.T) {
+	// scripts of random texts against the edit distance of an LCS table
+	random := rand.New(rand.NewPCG(1, 2))
+	text := func() []string {
+		lines := make([]string, random.IntN(30))
+		for i := range lines {
+			lines[i] = string(rune('a' + random.IntN(4)))
+		}
+		return lines
+	}
+
+	for range 500 {
+		a, b := text(), text()
+		var old, new []string
+		edits := 0
+		for _, line := range Lines(a, b) {
+			if line.Op != Insert {
+				old = append(old, line.Text)
+				if a[line.OldNumber-1] != line.Text {
+					t.Fatalf("Lines(%v, %v) numbers old line %d wrong", a, b, line.OldNumber)
+				}
+			}
+			if line.Op != Delete {
+				new = append(new, line.Text)
+				if b[line.NewNumber-1] != line.Text {
+					t.Fatalf("Lines(%v, %v) numbers new line %d wrong", a, b, line.NewNumber)
+				}
+			}
+			if line.Op != Equal {
+				edits++
+			}
+		}
+		if !slices.Equal(old, a) || !slices.Equal(new, b) {
+			t.Fatalf("Lines() script produces %v → %v, want %v → %v", old, new, a, b)
+		}
+		if expected := len(a) + len(b) - 2*lcsLength(a, b); edits != expected {
+			t.Fatalf("Lines(%v, %v) made %d edits, want %d", a, b, edits, expected)
+		}
+	}
+}
+
+// Length of the longest common subsequence by dynamic programming
+func lcsLength(a, b []string) int {
+	table := make([][]int, len(a)+1)
+	for i := range table {
+		table[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				table[i][j] = table[i+1][j+1] + 1
+			} else {
+				table[i][j] = max(table[i+1][j], table[i][j+1])
+			}
+		}
+	}
+
+	return table[0][0]
+}
+
+// Unrelated plugin sources of about 10k lines each
+func BenchmarkLinesUnrelated(b *testing.B) {
+	source := func(name string) []string {
+		lines := make([]string, 10000)
+		for i := range lines {
+			lines[i] = fmt.Sprintf("        private void %s%d() => Puts(\"%d\");", name, i, i)
+		}
+		return lines
+	}
+	old, new := source("Kits"), source("Vanish")
+
+	b.ReportAllocs()
+	for b.Loop() {
+		Lines(old, new)
+	}
+}
+
+func TestParseHunk(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		isValid bool
+	}{
+		{name: "changed line", input: "@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n", isValid: true},
+		{name: "omitted counts", input: "@@ -2 +2 @@\n-b\n+B\n", isValid: true},
+		{name: "insertion", input: "@@ -0,0 +1,1 @@\n+a\n", isValid: true},
+		{name: "stripped empty context", input: "@@ -1,3 +1,3 @@\n\n-b\n+B\n c\n", isValid: true},
+		{name: "wrong count", input: "@@ -1,2 +1,2 @@\n-b\n+B\n", isValid: false},
+		{name: "no header", input: "-b\n+B\n", isValid: false},
+		{name: "unknown sign", input: "@@ -1 +1 @@\n*b\n", isValid: false},
+		{name: "empty", input: "", isValid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hunk, err := ParseHunk(test.input)
+			if (err == nil) != test.isValid {
+				t.Fatalf("ParseHunk() error = %v, want valid %v", err, test.isValid)
+			}
+			if err != nil {
+				return
+			}
+			// formatting the parsed hunk restores the input with explicit counts
+			reparsed, err := ParseHunk(hunk.String())
+			if err != nil || reparsed.String() != hunk.String() {
+				t.Errorf("ParseHunk(hunk.String()) = %q, %v, want %q", reparsed.String(), err, hunk.String())
+			}
+		})
+	}
+
+	// hunks made by Hunks() are parsed back unchanged
+	for _, hunk := range Hunks(SplitLines("1\n2\n3\n4\n5\n"), SplitLines("1\n3\n4\nfour\n5\n6\n"), 1) {
+		parsed, err := ParseHunk(hunk.String())
+		if err != nil || !slices.Equal(parsed.Lines, hunk.Lines) {
+			t.Errorf("ParseHunk() = %+v, %v, want %+v", parsed.Lines, err, hunk.Lines)
+		}
+	}
+}
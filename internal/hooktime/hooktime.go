@@ -0,0 +1,112 @@
+// Package hooktime turns the cumulative hook time servers report for
+// plugins into per-interval figures and finds plugins that got slower
+// after an update.
+package hooktime
+
+import "time"
+
+// Total hook time of a plugin reported by a server at some point
+type Sample struct {
+	At      time.Time
+	Version string
+	// hook time since the plugin was loaded
+	Total time.Duration
+}
+
+// Hook time spent between two samples
+type Interval struct {
+	Start, End time.Time
+	// plugin version at the end of the interval
+	Version  string
+	HookTime time.Duration
+}
+
+// Hook time spent per minute of the interval
+func (i Interval) PerMinute() time.Duration {
+	length := i.End.Sub(i.Start)
+	if length <= 0 {
+		return 0
+	}
+
+	return time.Duration(float64(i.HookTime) * float64(time.Minute) / float64(length))
+}
+
+// Derive hook time spent between consecutive samples ordered by time.
+// Totals start over when the plugin is reloaded, so the hook time of
+// an interval with a reload is the total at its end.
+func Deltas(samples []Sample) []Interval {
+	var intervals []Interval
+	for i := 1; i < len(samples); i++ {
+		prev, cur := samples[i-1], samples[i]
+		if !cur.At.After(prev.At) {
+			continue
+		}
+
+		hookTime := cur.Total - prev.Total
+		if cur.Total < prev.Total || cur.Version != prev.Version {
+			hookTime = cur.Total
+		}
+		intervals = append(intervals, Interval{
+			Start:    prev.At,
+			End:      cur.At,
+			Version:  cur.Version,
+			HookTime: hookTime,
+		})
+	}
+
+	return intervals
+}
+
+// Hook time growth after a version change
+type Regression struct {
+	// end of the first interval of the new version
+	At       time.Time
+	From, To string
+	// average hook time per minute of the versions
+	Before, After time.Duration
+}
+
+// Find version changes after which the average hook time per minute
+// grew at least by the factor and by minIncrease
+func Regressions(intervals []Interval, factor float64, minIncrease time.Duration) []Regression {
+	// consecutive intervals of the same version
+	type run struct {
+		version          string
+		start            time.Time
+		hookTime, length time.Duration
+	}
+	var runs []run
+	for _, interval := range intervals {
+		if last := len(runs) - 1; last >= 0 && runs[last].version == interval.Version {
+			runs[last].hookTime += interval.HookTime
+			runs[last].length += interval.End.Sub(interval.Start)
+			continue
+		}
+		runs = append(runs, run{
+			version:  interval.Version,
+			start:    interval.End,
+			hookTime: interval.HookTime,
+			length:   interval.End.Sub(interval.Start),
+		})
+	}
+
+	perMinute := func(r run) time.Duration {
+		return time.Duration(float64(r.hookTime) * float64(time.Minute) / float64(r.length))
+	}
+	var regressions []Regression
+	for i := 1; i < len(runs); i++ {
+		before, after := perMinute(runs[i-1]), perMinute(runs[i])
+		if after-before < minIncrease || float64(after) < float64(before)*factor {
+			continue
+		}
+		regressions = append(regressions, Regression{
+			At:     runs[i].start,
+			From:   runs[i-1].version,
+			To:     runs[i].version,
+			Before: before,
+			After:  after,
+		})
+	}
+
+	return regressions
+}
@@ -0,0 +1,105 @@
+package hooktime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeltas(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+	samples := []Sample{
+		{At: at(0), Version: "1.0.0", Total: 2 * time.Second},
+		{At: at(10), Version: "1.0.0", Total: 3 * time.Second},
+		// server restarted
+		{At: at(20), Version: "1.0.0", Total: 500 * time.Millisecond},
+		// sampled twice at once
+		{At: at(20), Version: "1.0.0", Total: 500 * time.Millisecond},
+		// updated without the total dropping below the previous one
+		{At: at(30), Version: "1.1.0", Total: 4 * time.Second},
+	}
+	expected := []Interval{
+		{Start: at(0), End: at(10), Version: "1.0.0", HookTime: time.Second},
+		{Start: at(10), End: at(20), Version: "1.0.0", HookTime: 500 * time.Millisecond},
+		{Start: at(20), End: at(30), Version: "1.1.0", HookTime: 4 * time.Second},
+	}
+
+	intervals := Deltas(samples)
+	if len(intervals) != len(expected) {
+		t.Fatalf("Deltas() = %+v, want %d intervals", intervals, len(expected))
+	}
+	for i, interval := range intervals {
+		if interval != expected[i] {
+			t.Errorf("interval %d = %+v, want %+v", i, interval, expected[i])
+		}
+	}
+	if perMinute := intervals[0].PerMinute(); perMinute != 100*time.Millisecond {
+		t.Errorf("PerMinute() = %s, want 100ms", perMinute)
+	}
+}
+
+func TestRegressions(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	interval := func(from int, version string, hookTime time.Duration) Interval {
+		return Interval{
+			Start:    start.Add(time.Duration(from) * time.Minute),
+			End:      start.Add(time.Duration(from+10) * time.Minute),
+			Version:  version,
+			HookTime: hookTime,
+		}
+	}
+
+	tests := []struct {
+		name                string
+		intervals           []Interval
+		expectedRegressions []Regression
+	}{
+		{
+			name: "jump after update",
+			intervals: []Interval{
+				interval(0, "1.0.0", time.Second),
+				interval(10, "1.0.0", 3*time.Second),
+				interval(20, "1.1.0", 10*time.Second),
+			},
+			expectedRegressions: []Regression{{
+				At: start.Add(30 * time.Minute), From: "1.0.0", To: "1.1.0",
+				Before: 200 * time.Millisecond, After: time.Second,
+			}},
+		},
+		{
+			name: "growth below the minimum",
+			intervals: []Interval{
+				interval(0, "1.0.0", 100*time.Millisecond),
+				interval(10, "1.1.0", 500*time.Millisecond),
+			},
+		},
+		{
+			name: "same version",
+			intervals: []Interval{
+				interval(0, "1.0.0", time.Second),
+				interval(10, "1.0.0", 20*time.Second),
+			},
+		},
+		{
+			name: "faster after update",
+			intervals: []Interval{
+				interval(0, "1.0.0", 10*time.Second),
+				interval(10, "1.1.0", time.Second),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			regressions := Regressions(test.intervals, 2, 100*time.Millisecond)
+			if len(regressions) != len(test.expectedRegressions) {
+				t.Fatalf("Regressions() = %+v, want %+v", regressions, test.expectedRegressions)
+			}
+			for i, regression := range regressions {
+				if regression != test.expectedRegressions[i] {
+					t.Errorf("regression %d = %+v, want %+v", i, regression, test.expectedRegressions[i])
+				}
+			}
+		})
+	}
+}
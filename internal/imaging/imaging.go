@@ -0,0 +1,90 @@
+// Package imaging validates uploaded images and makes their thumbnails.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"slices"
+
+	"github.com/gabriel-vasile/mimetype"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// Largest accepted image in pixels, checked before decoding
+// to refuse images that would take too much memory
+const MaxPixels = 40_000_000
+
+// Image types accepted for uploading
+var mimeTypes = []string{"image/png", "image/jpeg", "image/gif", "image/webp"}
+
+// Properties of a validated image
+type Info struct {
+	MimeType      string
+	Width, Height int
+}
+
+// Detect the type of image data by its content and read its dimensions
+func Inspect(data []byte) (info Info, err error) {
+	info.MimeType = mimetype.Detect(data).String()
+	if !slices.Contains(mimeTypes, info.MimeType) {
+		return info, fmt.Errorf("unsupported image type: %s", info.MimeType)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return info, err
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return info, errors.New("empty image")
+	}
+	if config.Width*config.Height > MaxPixels {
+		return info, fmt.Errorf("image is too large: %dx%d", config.Width, config.Height)
+	}
+	info.Width, info.Height = config.Width, config.Height
+
+	return info, nil
+}
+
+// Scale down the image to fit a square with the given side. JPEG images
+// stay JPEG, others become PNG to keep transparency. Nothing is returned
+// for images fitting the square already.
+func Thumbnail(data []byte, info Info, side int) (thumbnail []byte, mimeType string, err error) {
+	if info.Width <= side && info.Height <= side {
+		return nil, "", nil
+	}
+
+	// the first frame is used for animated images
+	source, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+
+	width, height := side, side
+	if info.Width > info.Height {
+		height = max(info.Height*side/info.Width, 1)
+	} else {
+		width = max(info.Width*side/info.Height, 1)
+	}
+	scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(scaled, scaled.Bounds(), source, source.Bounds(), draw.Over, nil)
+
+	var buf bytes.Buffer
+	mimeType = "image/png"
+	if info.MimeType == "image/jpeg" {
+		mimeType = "image/jpeg"
+		err = jpeg.Encode(&buf, scaled, &jpeg.Options{Quality: 85})
+	} else {
+		err = png.Encode(&buf, scaled)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return buf.Bytes(), mimeType, nil
+}
@@ -0,0 +1,103 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// Encode a solid image of the given size
+func encode(t *testing.T, width, height int, asJPEG bool) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := range width {
+		for y := range height {
+			img.Set(x, y, color.RGBA{R: 200, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	var err error
+	if asJPEG {
+		err = jpeg.Encode(&buf, img, nil)
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestInspect(t *testing.T) {
+	tests := []struct {
+		name             string
+		data             []byte
+		expectedMimeType string
+		isValid          bool
+	}{
+		{name: "png", data: encode(t, 20, 10, false), expectedMimeType: "image/png", isValid: true},
+		{name: "jpeg", data: encode(t, 20, 10, true), expectedMimeType: "image/jpeg", isValid: true},
+		{name: "text", data: []byte("class Kits {}"), isValid: false},
+		{name: "svg", data: []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`), isValid: false},
+		{name: "truncated png", data: encode(t, 20, 10, false)[:20], isValid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			info, err := Inspect(test.data)
+			if (err == nil) != test.isValid {
+				t.Fatalf("Inspect() error = %v, want valid %v", err, test.isValid)
+			}
+			if err == nil && (info.MimeType != test.expectedMimeType || info.Width != 20 || info.Height != 10) {
+				t.Errorf("Inspect() = %+v, want %s 20x10", info, test.expectedMimeType)
+			}
+		})
+	}
+}
+
+func TestThumbnail(t *testing.T) {
+	tests := []struct {
+		name             string
+		data             []byte
+		expectedMimeType string
+		expectedWidth    int
+		expectedHeight   int
+	}{
+		{name: "wide png", data: encode(t, 200, 50, false), expectedMimeType: "image/png", expectedWidth: 100, expectedHeight: 25},
+		{name: "tall jpeg", data: encode(t, 50, 200, true), expectedMimeType: "image/jpeg", expectedWidth: 25, expectedHeight: 100},
+		{name: "small", data: encode(t, 80, 80, false)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			info, err := Inspect(test.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			thumbnail, mimeType, err := Thumbnail(test.data, info, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.expectedMimeType == "" {
+				if thumbnail != nil {
+					t.Errorf("Thumbnail() of a small image = %d bytes, want none", len(thumbnail))
+				}
+				return
+			}
+
+			thumbInfo, err := Inspect(thumbnail)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if mimeType != test.expectedMimeType || thumbInfo.MimeType != mimeType ||
+				thumbInfo.Width != test.expectedWidth || thumbInfo.Height != test.expectedHeight {
+				t.Errorf("Thumbnail() = %s %+v, want %s %dx%d", mimeType, thumbInfo, test.expectedMimeType, test.expectedWidth, test.expectedHeight)
+			}
+		})
+	}
+}
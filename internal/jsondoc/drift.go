@@ -0,0 +1,161 @@
+package jsondoc
+
+import "strings"
+
+// Kind of difference between a stored document and a new default one
+type ChangeKind int
+
+const (
+	Added ChangeKind = iota
+	Removed
+	TypeChanged
+)
+
+// Name of the change kind
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case TypeChanged:
+		return "type changed"
+	}
+
+	return "unknown"
+}
+
+// Key that differs between documents
+type Change struct {
+	Kind    ChangeKind
+	Path    []string
+	OldType string
+	NewType string
+}
+
+// Path of the key joined with arrows, like Oxide configs are documented
+func (c Change) Key() string {
+	return strings.Join(c.Path, " → ")
+}
+
+// Compare the structure of a stored document with a new default one.
+// Objects are compared key by key, other values only by their types,
+// and null is compatible with any type.
+func Diff(stored, defaults Value) []Change {
+	return diffValues(nil, stored, defaults)
+}
+
+// Compare two values at the path
+func diffValues(path []string, stored, defaults Value) []Change {
+	storedObject, storedIsObject := stored.(*Object)
+	defaultObject, defaultIsObject := defaults.(*Object)
+	if storedIsObject && defaultIsObject {
+		return diffObjects(path, storedObject, defaultObject)
+	}
+
+	if stored == nil || defaults == nil || TypeName(stored) == TypeName(defaults) {
+		return nil
+	}
+	return []Change{{
+		Kind:    TypeChanged,
+		Path:    path,
+		OldType: TypeName(stored),
+		NewType: TypeName(defaults),
+	}}
+}
+
+// Compare keys of two objects at the path, new keys go first
+// in the default order followed by removed keys in the stored order
+func diffObjects(path []string, stored, defaults *Object) (changes []Change) {
+	for _, key := range defaults.Keys {
+		keyPath := append(path[:len(path):len(path)], key)
+		defaultValue := defaults.Values[key]
+
+		storedValue, ok := stored.Get(key)
+		if !ok {
+			changes = append(changes, Change{
+				Kind:    Added,
+				Path:    keyPath,
+				NewType: TypeName(defaultValue),
+			})
+			continue
+		}
+		changes = append(changes, diffValues(keyPath, storedValue, defaultValue)...)
+	}
+
+	for _, key := range stored.Keys {
+		if _, ok := defaults.Get(key); ok {
+			continue
+		}
+		changes = append(changes, Change{
+			Kind:    Removed,
+			Path:    append(path[:len(path):len(path)], key),
+			OldType: TypeName(stored.Values[key]),
+		})
+	}
+
+	return changes
+}
+
+// Merge a new default document into a stored one keeping stored values
+// and key order. New keys are added after their preceding key in the
+// default document, values with a changed type are replaced with defaults
+// and keys missing from defaults are dropped if asked.
+// Neither document is modified.
+func Merge(stored, defaults Value, dropRemoved bool) Value {
+	storedObject, storedIsObject := stored.(*Object)
+	defaultObject, defaultIsObject := defaults.(*Object)
+	if storedIsObject && defaultIsObject {
+		return mergeObjects(storedObject, defaultObject, dropRemoved)
+	}
+
+	if stored == nil || defaults == nil || TypeName(stored) == TypeName(defaults) {
+		return Clone(stored)
+	}
+	return Clone(defaults)
+}
+
+// Merge two objects, see Merge
+func mergeObjects(stored, defaults *Object, dropRemoved bool) *Object {
+	merged := NewObject()
+	for _, key := range stored.Keys {
+		defaultValue, ok := defaults.Get(key)
+		if !ok {
+			if !dropRemoved {
+				merged.Set(key, Clone(stored.Values[key]))
+			}
+			continue
+		}
+		merged.Set(key, Merge(stored.Values[key], defaultValue, dropRemoved))
+	}
+
+	previous := ""
+	for _, key := range defaults.Keys {
+		if _, ok := stored.Get(key); !ok {
+			merged.InsertAfter(previous, key, Clone(defaults.Values[key]))
+		}
+		previous = key
+	}
+
+	return merged
+}
+
+// Deep copy of the value
+func Clone(value Value) Value {
+	switch v := value.(type) {
+	case []Value:
+		array := make([]Value, len(v))
+		for i, item := range v {
+			array[i] = Clone(item)
+		}
+		return array
+	case *Object:
+		object := NewObject()
+		for _, key := range v.Keys {
+			object.Set(key, Clone(v.Values[key]))
+		}
+		return object
+	}
+
+	return value
+}
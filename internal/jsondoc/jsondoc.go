@@ -0,0 +1,236 @@
+// Package jsondoc reads and writes JSON documents keeping the order of
+// object keys and the text of numbers, so edited plugin files stay close
+// to what plugins write themselves.
+package jsondoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Value of a document: nil, bool, json.Number, string, []Value or *Object
+type Value any
+
+// Object with keys in the document order
+type Object struct {
+	Keys   []string
+	Values map[string]Value
+}
+
+// Create an empty object
+func NewObject() *Object {
+	return &Object{Values: make(map[string]Value)}
+}
+
+// Get value of the key and whether the object has it
+func (o *Object) Get(key string) (Value, bool) {
+	value, ok := o.Values[key]
+	return value, ok
+}
+
+// Set value of the key, new keys are appended
+func (o *Object) Set(key string, value Value) {
+	if _, ok := o.Values[key]; !ok {
+		o.Keys = append(o.Keys, key)
+	}
+	o.Values[key] = value
+}
+
+// Insert a new key after another one, or first if after is empty
+// or missing
+func (o *Object) InsertAfter(after, key string, value Value) {
+	if _, ok := o.Values[key]; ok {
+		o.Values[key] = value
+		return
+	}
+
+	position := 0
+	for i, existing := range o.Keys {
+		if existing == after {
+			position = i + 1
+			break
+		}
+	}
+	o.Keys = append(o.Keys[:position], append([]string{key}, o.Keys[position:]...)...)
+	o.Values[key] = value
+}
+
+// Remove the key
+func (o *Object) Delete(key string) {
+	if _, ok := o.Values[key]; !ok {
+		return
+	}
+
+	delete(o.Values, key)
+	for i, existing := range o.Keys {
+		if existing == key {
+			o.Keys = append(o.Keys[:i], o.Keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// Parse a JSON document
+func Parse(data []byte) (Value, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	value, err := parseValue(decoder)
+	if err != nil {
+		return nil, err
+	}
+	// nothing but whitespace may follow the document
+	if _, err = decoder.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("unexpected data after the document")
+	}
+
+	return value, nil
+}
+
+// Read the next value from the decoder
+func parseValue(decoder *json.Decoder) (Value, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	delim, ok := token.(json.Delim)
+	if !ok {
+		// nil, bool, json.Number, or string
+		return token, nil
+	}
+
+	switch delim {
+	case '{':
+		object := NewObject()
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := parseValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			// the decoder returns object keys as strings
+			object.Set(keyToken.(string), value)
+		}
+		_, err = decoder.Token()
+		return object, err
+	case '[':
+		array := []Value{}
+		for decoder.More() {
+			value, err := parseValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		_, err = decoder.Token()
+		return array, err
+	}
+
+	return nil, fmt.Errorf("unexpected delimiter: %s", delim)
+}
+
+// Name of the value type as in JSON
+func TypeName(value Value) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []Value:
+		return "array"
+	case *Object:
+		return "object"
+	}
+
+	return fmt.Sprintf("%T", value)
+}
+
+// Format the value the way Oxide writes plugin files: indented with
+// two spaces and with non-ASCII characters kept as they are
+func Marshal(value Value) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := write(&buf, value, 0); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Write the value at the indentation level
+func write(buf *bytes.Buffer, value Value, level int) error {
+	indent := strings.Repeat("  ", level+1)
+	closingIndent := strings.Repeat("  ", level)
+
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		fmt.Fprint(buf, v)
+	case json.Number:
+		buf.WriteString(v.String())
+	case string:
+		writeString(buf, v)
+	case []Value:
+		if len(v) == 0 {
+			buf.WriteString("[]")
+			return nil
+		}
+		buf.WriteString("[\n")
+		for i, item := range v {
+			buf.WriteString(indent)
+			if err := write(buf, item, level+1); err != nil {
+				return err
+			}
+			if i < len(v)-1 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(closingIndent + "]")
+	case *Object:
+		if len(v.Keys) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+		buf.WriteString("{\n")
+		for i, key := range v.Keys {
+			buf.WriteString(indent)
+			writeString(buf, key)
+			buf.WriteString(": ")
+			if err := write(buf, v.Values[key], level+1); err != nil {
+				return err
+			}
+			if i < len(v.Keys)-1 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(closingIndent + "}")
+	default:
+		return fmt.Errorf("unsupported value type: %T", value)
+	}
+
+	return nil
+}
+
+// Write a quoted string without escaping HTML characters
+func writeString(buf *bytes.Buffer, s string) {
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	// encoding a string can't fail
+	_ = encoder.Encode(s)
+	// the encoder ends values with a newline
+	buf.Truncate(buf.Len() - 1)
+}
@@ -0,0 +1,121 @@
+package jsondoc
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMarshal(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		expectedOutput string
+	}{
+		{name: "scalar", input: "1.50", expectedOutput: "1.50"},
+		{name: "empty containers", input: `{"a": {}, "b": []}`, expectedOutput: "{\n  \"a\": {},\n  \"b\": []\n}"},
+		{
+			name:           "order and numbers kept",
+			input:          `{"Zeta": 1.0, "Alpha": [true, null, "<b>Ü</b>"]}`,
+			expectedOutput: "{\n  \"Zeta\": 1.0,\n  \"Alpha\": [\n    true,\n    null,\n    \"<b>Ü</b>\"\n  ]\n}",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, err := Parse([]byte(test.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			output, err := Marshal(value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(output) != test.expectedOutput {
+				t.Errorf("Marshal() =\n%s\nwant\n%s", output, test.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, input := range []string{"", "{", `{"a": 1} {}`, `{"a" 1}`, "[1,]"} {
+		if _, err := Parse([]byte(input)); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", input)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	stored := mustParse(t, `{"Limit": 5, "Old": true, "Nested": {"Name": "x", "Color": null}, "Kits": ["a"]}`)
+	defaults := mustParse(t, `{"Limit": "5", "Nested": {"Name": "y", "Color": "red", "Size": 2}, "Kits": [], "New": {}}`)
+
+	var changes []string
+	for _, change := range Diff(stored, defaults) {
+		changes = append(changes, change.Kind.String()+" "+change.Key()+" "+change.OldType+"/"+change.NewType)
+	}
+	expectedChanges := []string{
+		"type changed Limit number/string",
+		"added Nested → Size /number",
+		"added New /object",
+		"removed Old boolean/",
+	}
+	if !slices.Equal(changes, expectedChanges) {
+		t.Errorf("Diff() = %q, want %q", changes, expectedChanges)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	stored := mustParse(t, `{"B": 1, "Old": 2, "Nested": {"Name": "ours"}, "Type": "1"}`)
+	defaults := mustParse(t, `{"A": 0, "B": 0, "C": 0, "Nested": {"Name": "theirs", "Size": 3}, "Type": 1}`)
+	storedBefore := marshal(t, stored)
+
+	tests := []struct {
+		name           string
+		dropRemoved    bool
+		expectedOutput string
+	}{
+		{
+			name:           "removed kept",
+			expectedOutput: `{"A": 0, "B": 1, "C": 0, "Old": 2, "Nested": {"Name": "ours", "Size": 3}, "Type": 1}`,
+		},
+		{
+			name:           "removed dropped",
+			dropRemoved:    true,
+			expectedOutput: `{"A": 0, "B": 1, "C": 0, "Nested": {"Name": "ours", "Size": 3}, "Type": 1}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := marshal(t, Merge(stored, defaults, test.dropRemoved))
+			expectedOutput := marshal(t, mustParse(t, test.expectedOutput))
+			if output != expectedOutput {
+				t.Errorf("Merge() =\n%s\nwant\n%s", output, expectedOutput)
+			}
+		})
+	}
+
+	if marshal(t, stored) != storedBefore {
+		t.Error("Merge() modified the stored document")
+	}
+}
+
+// Parse the document or fail the test
+func mustParse(t *testing.T, input string) Value {
+	t.Helper()
+	value, err := Parse([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return value
+}
+
+// Marshal the value or fail the test
+func marshal(t *testing.T, value Value) string {
+	t.Helper()
+	output, err := Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(output)
+}
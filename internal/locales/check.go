@@ -0,0 +1,118 @@
+package locales
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// Problem found in a translated message
+type Issue struct {
+	Key     string
+	Message string
+}
+
+// Text of the issue with its message key
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s", i.Key, i.Message)
+}
+
+var (
+	// {0}, {name} and formatted {0:N2} or {0,5} placeholders,
+	// {{ and }} are escaped braces
+	placeholderPattern = regexp.MustCompile(`\{\{|\}\}|\{(\w+)(?:[,:][^{}]*)?\}`)
+	// rich-text tags like <color=#ff0000>, </size> or <b>
+	tagPattern = regexp.MustCompile(`<(/?)([a-z]+)(?:=[^<>]*)?>`)
+)
+
+// Unity rich-text tags and whether they must be closed
+var richTextTags = map[string]bool{
+	"b": true, "i": true, "u": true, "s": true,
+	"color": true, "size": true, "material": true,
+	"quad": false,
+}
+
+// Placeholders of a message, every occurrence is listed
+func Placeholders(text string) (names []string) {
+	for _, match := range placeholderPattern.FindAllStringSubmatch(text, -1) {
+		if match[1] != "" {
+			names = append(names, match[1])
+		}
+	}
+
+	return names
+}
+
+// Compare placeholders of a translated message with the base one,
+// the order may differ as languages order words differently
+func CheckPlaceholders(base, text string) (problems []string) {
+	basePlaceholders := Placeholders(base)
+	placeholders := Placeholders(text)
+
+	for _, name := range uniqueSorted(basePlaceholders) {
+		if !slices.Contains(placeholders, name) {
+			problems = append(problems, fmt.Sprintf("placeholder {%s} is missing", name))
+		}
+	}
+	for _, name := range uniqueSorted(placeholders) {
+		if !slices.Contains(basePlaceholders, name) {
+			problems = append(problems, fmt.Sprintf("placeholder {%s} isn't in the base message", name))
+		}
+	}
+
+	return problems
+}
+
+// Check that rich-text tags of a message are closed in the right order
+func CheckTags(text string) (problems []string) {
+	var open []string
+	for _, match := range tagPattern.FindAllStringSubmatch(text, -1) {
+		isClosing, name := match[1] == "/", match[2]
+		mustClose, known := richTextTags[name]
+		if !known || !mustClose {
+			continue
+		}
+
+		if !isClosing {
+			open = append(open, name)
+			continue
+		}
+		switch {
+		case len(open) == 0:
+			problems = append(problems, fmt.Sprintf("tag </%s> isn't opened", name))
+		case open[len(open)-1] != name:
+			problems = append(problems, fmt.Sprintf("tag </%s> closes <%s>", name, open[len(open)-1]))
+			open = open[:len(open)-1]
+		default:
+			open = open[:len(open)-1]
+		}
+	}
+	for _, name := range slices.Backward(open) {
+		problems = append(problems, fmt.Sprintf("tag <%s> isn't closed", name))
+	}
+
+	return problems
+}
+
+// Check messages of a translation: rich-text tags of every message and
+// placeholders of messages the base has. Pass empty base messages to check
+// the base language itself.
+func Check(base, translation Messages) (issues []Issue) {
+	for _, key := range translation.Keys {
+		text := translation.Values[key]
+		problems := CheckTags(text)
+		if baseText, ok := base.Values[key]; ok {
+			problems = append(problems, CheckPlaceholders(baseText, text)...)
+		}
+		for _, problem := range problems {
+			issues = append(issues, Issue{Key: key, Message: problem})
+		}
+	}
+
+	return issues
+}
+
+// Sorted names without duplicates
+func uniqueSorted(names []string) []string {
+	return slices.Compact(slices.Sorted(slices.Values(names)))
+}
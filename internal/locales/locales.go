@@ -0,0 +1,114 @@
+// Package locales compares translations of Oxide lang files with
+// the base language.
+package locales
+
+import (
+	"fmt"
+	"strings"
+
+	"adminrust/internal/jsondoc"
+)
+
+// Messages of a lang file in the file order
+type Messages struct {
+	Keys   []string
+	Values map[string]string
+}
+
+// Parse lang file content: a flat object of messages by keys. The byte
+// order mark Windows editors add is ignored.
+func Parse(content string) (messages Messages, err error) {
+	value, err := jsondoc.Parse([]byte(strings.TrimPrefix(content, "\ufeff")))
+	if err != nil {
+		return messages, err
+	}
+	object, ok := value.(*jsondoc.Object)
+	if !ok {
+		return messages, fmt.Errorf("lang file must be an object of messages, not %s", jsondoc.TypeName(value))
+	}
+
+	messages.Values = make(map[string]string, len(object.Keys))
+	for _, key := range object.Keys {
+		text, ok := object.Values[key].(string)
+		if !ok {
+			return messages, fmt.Errorf("message %q must be a string, not %s", key, jsondoc.TypeName(object.Values[key]))
+		}
+		messages.Keys = append(messages.Keys, key)
+		messages.Values[key] = text
+	}
+
+	return messages, nil
+}
+
+// Normalize lang file content to the way Oxide writes it: without the byte
+// order mark and indented, keys keep their file order
+func Normalize(content string) (string, error) {
+	messages, err := Parse(content)
+	if err != nil {
+		return "", err
+	}
+
+	object := jsondoc.NewObject()
+	for _, key := range messages.Keys {
+		object.Set(key, messages.Values[key])
+	}
+	data, err := jsondoc.Marshal(object)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// How well a translation covers the base language
+type Coverage struct {
+	// base keys missing from the translation
+	Missing []string
+	// translation keys unknown to the base
+	Extra []string
+	// keys with values identical to the base
+	Untranslated []string
+	// number of base keys and of those translated
+	Total      int
+	Translated int
+}
+
+// Share of translated base keys rounded down, a base without keys
+// is covered completely
+func (c Coverage) Percent() int {
+	if c.Total == 0 {
+		return 100
+	}
+
+	return c.Translated * 100 / c.Total
+}
+
+// Whether every base key is translated and nothing is extra
+func (c Coverage) Complete() bool {
+	return c.Translated == c.Total && len(c.Extra) == 0
+}
+
+// Compare a translation with the base language, keys are listed
+// in the order of their files
+func Compare(base, translation Messages) (coverage Coverage) {
+	coverage.Total = len(base.Keys)
+	for _, key := range base.Keys {
+		text, ok := translation.Values[key]
+		switch {
+		case !ok:
+			coverage.Missing = append(coverage.Missing, key)
+		case text == base.Values[key]:
+			coverage.Untranslated = append(coverage.Untranslated, key)
+		default:
+			coverage.Translated++
+		}
+	}
+
+	for _, key := range translation.Keys {
+		if _, ok := base.Values[key]; !ok {
+			coverage.Extra = append(coverage.Extra, key)
+		}
+	}
+
+	return coverage
+}
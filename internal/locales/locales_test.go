@@ -0,0 +1,155 @@
+package locales
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	messages, err := Parse(`{"B": "b", "A": "a"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(messages.Keys, []string{"B", "A"}) || messages.Values["A"] != "a" {
+		t.Errorf("Parse() = %+v", messages)
+	}
+
+	if messages, err = Parse("\ufeff{\"A\": \"a\"}"); err != nil || messages.Values["A"] != "a" {
+		t.Errorf("Parse() with BOM = %+v, %v", messages, err)
+	}
+
+	tests := []struct {
+		content       string
+		expectedError string
+	}{
+		{content: `[]`, expectedError: "lang file must be an object of messages, not array"},
+		{content: `"text"`, expectedError: "lang file must be an object of messages, not string"},
+		{content: `{"A": 1}`, expectedError: `message "A" must be a string, not number`},
+		{content: `{"A": {"B": "b"}}`, expectedError: `message "A" must be a string, not object`},
+		{content: `{"A": null}`, expectedError: `message "A" must be a string, not null`},
+		{content: `{`},
+	}
+	for _, test := range tests {
+		_, err = Parse(test.content)
+		if err == nil || test.expectedError != "" && err.Error() != test.expectedError {
+			t.Errorf("Parse(%s) error = %v, want %q", test.content, err, test.expectedError)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	content, err := Normalize("\ufeff{\"B\":\"Привет <b>{0}</b>\",\n\n\"A\" : \"a\"}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "{\n  \"B\": \"Привет <b>{0}</b>\",\n  \"A\": \"a\"\n}"
+	if content != expected {
+		t.Errorf("Normalize() = %q, want %q", content, expected)
+	}
+
+	if content, err = Normalize(`{}`); err != nil || content != `{}` {
+		t.Errorf("Normalize({}) = %q, %v", content, err)
+	}
+	if _, err = Normalize(`{"A": ["a"]}`); err == nil {
+		t.Error("Normalize() of a nested value succeeded, want error")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	base, err := Parse(`{"NoPermission": "No permission", "Cooldown": "Wait {0}s", "Title": "Kits", "Help": "Use /kit"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	translation, err := Parse(`{"Title": "Kits", "NoPermission": "Нет прав", "Help": "Используйте /kit", "Old": "Старое"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	coverage := Compare(base, translation)
+	if !slices.Equal(coverage.Missing, []string{"Cooldown"}) {
+		t.Errorf("Missing = %q, want [Cooldown]", coverage.Missing)
+	}
+	if !slices.Equal(coverage.Extra, []string{"Old"}) {
+		t.Errorf("Extra = %q, want [Old]", coverage.Extra)
+	}
+	if !slices.Equal(coverage.Untranslated, []string{"Title"}) {
+		t.Errorf("Untranslated = %q, want [Title]", coverage.Untranslated)
+	}
+	if coverage.Percent() != 50 || coverage.Complete() {
+		t.Errorf("Percent() = %d, Complete() = %t, want 50, false", coverage.Percent(), coverage.Complete())
+	}
+
+	if coverage = Compare(base, base); coverage.Percent() != 0 {
+		t.Errorf("base compared with itself Percent() = %d, want 0", coverage.Percent())
+	}
+	if coverage = Compare(Messages{}, translation); coverage.Percent() != 100 || len(coverage.Extra) != 4 {
+		t.Errorf("empty base coverage = %+v", coverage)
+	}
+}
+
+func TestCheckPlaceholders(t *testing.T) {
+	tests := []struct {
+		name             string
+		base, text       string
+		expectedProblems []string
+	}{
+		{name: "reordered", base: "{0} gave {1} to {player}", text: "{player} получил {1} от {0}"},
+		{name: "formatted", base: "Wait {0:N1}s", text: "Подождите {0}с"},
+		{name: "escaped braces", base: "Use {{kit}} {0}", text: "Используйте {0}"},
+		{name: "missing", base: "Wait {0}s", text: "Подождите", expectedProblems: []string{"placeholder {0} is missing"}},
+		{
+			name: "renamed", base: "Hello {name}", text: "Привет {player}",
+			expectedProblems: []string{"placeholder {name} is missing", "placeholder {player} isn't in the base message"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if problems := CheckPlaceholders(test.base, test.text); !slices.Equal(problems, test.expectedProblems) {
+				t.Errorf("CheckPlaceholders() = %q, want %q", problems, test.expectedProblems)
+			}
+		})
+	}
+}
+
+func TestCheckTags(t *testing.T) {
+	tests := []struct {
+		name             string
+		text             string
+		expectedProblems []string
+	}{
+		{name: "balanced", text: "<color=#ff0000><size=14>Kits</size></color> <b>ok</b>"},
+		{name: "self-closing and unknown tags", text: `<quad material=1 size=20> <player> <align="left">`},
+		{name: "unclosed", text: "<color=red>Kits <b>now", expectedProblems: []string{"tag <b> isn't closed", "tag <color> isn't closed"}},
+		{name: "not opened", text: "Kits</size>", expectedProblems: []string{"tag </size> isn't opened"}},
+		{name: "crossed", text: "<b><i>Kits</b></i>", expectedProblems: []string{"tag </b> closes <i>", "tag </i> closes <b>"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if problems := CheckTags(test.text); !slices.Equal(problems, test.expectedProblems) {
+				t.Errorf("CheckTags() = %q, want %q", problems, test.expectedProblems)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	base, err := Parse(`{"Cooldown": "Wait <color=red>{0}</color>s", "Title": "Kits"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	translation, err := Parse(`{"Title": "<b>Наборы", "Cooldown": "Подождите <color=red>{0}</color>с", "Old": "{1}"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var issues []string
+	for _, issue := range Check(base, translation) {
+		issues = append(issues, issue.String())
+	}
+	expectedIssues := []string{"Title: tag <b> isn't closed"}
+	if !slices.Equal(issues, expectedIssues) {
+		t.Errorf("Check() = %q, want %q", issues, expectedIssues)
+	}
+}
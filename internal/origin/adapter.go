@@ -0,0 +1,117 @@
+package origin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"adminrust/internal/database"
+)
+
+// Returned when there is no adapter able to talk to an origin
+var ErrNoAdapter = errors.New("no adapter available for origin")
+
+// A plugin entry as it is published by an origin
+type Listing struct {
+	Name        string
+	Description string
+	Url         string
+	Version     string
+	UpdatedAt   string
+}
+
+// The latest plugin release as it is published by an origin
+type Release struct {
+	Version   string
+	Changelog string
+	// release date in any format the origin uses
+	Date string
+}
+
+// Adapter retrieves plugin listings from a specific kind of origin
+type Adapter interface {
+	// FetchPlugins walks through every page of the origin plugin list
+	// and returns all found listings in the order they were published
+	FetchPlugins(ctx context.Context, origin database.PluginOrigin) ([]Listing, error)
+
+	// FetchRelease visits a plugin page and returns its latest release
+	FetchRelease(ctx context.Context, pluginURL string) (Release, error)
+}
+
+// Default HTTP client for adapters that don't have their own
+var defaultClient = &http.Client{Timeout: 30 * time.Second}
+
+// Size limit for a single fetched page
+const maxPageSize = 10 << 20
+
+// Available adapters by the name stored in plugin_origins.adapter.
+//
+// New marketplaces are supported by adding an adapter here.
+var adapters = map[string]func() Adapter{
+	"umod":      func() Adapter { return &UModAdapter{} },
+	"codefling": func() Adapter { return &ScraperAdapter{Rules: CodeflingRules} },
+}
+
+// Return sorted names of the available adapters
+func AdapterNames() (names []string) {
+	for name := range adapters {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// Check if there is an adapter with the given name
+func IsKnownAdapter(name string) bool {
+	_, exists := adapters[name]
+	return exists
+}
+
+// Name of the adapter syncing the origin, empty if there is none.
+// Origins with an API but no adapter selected are uMod-compatible ones.
+func AdapterName(origin database.PluginOrigin) string {
+	if origin.Adapter == "" && origin.HasApi == 1 {
+		return "umod"
+	}
+
+	return origin.Adapter
+}
+
+// Pick the adapter selected for the origin or return ErrNoAdapter
+func AdapterFor(origin database.PluginOrigin) (Adapter, error) {
+	newAdapter, exists := adapters[AdapterName(origin)]
+	if !exists {
+		return nil, ErrNoAdapter
+	}
+
+	return newAdapter(), nil
+}
+
+// Send GET request and return the response body
+func fetch(ctx context.Context, client *http.Client, rawURL, accept string) (body []byte, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+
+	if client == nil {
+		client = defaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status requesting %s: %s", rawURL, resp.Status)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxPageSize))
+}
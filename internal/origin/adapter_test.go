@@ -0,0 +1,33 @@
+package origin
+
+import (
+	"errors"
+	"testing"
+
+	"adminrust/internal/database"
+)
+
+func TestAdapterFor(t *testing.T) {
+	tests := []struct {
+		name          string
+		origin        database.PluginOrigin
+		expectedName  string
+		expectedError error
+	}{
+		{name: "selected adapter", origin: database.PluginOrigin{Adapter: "codefling"}, expectedName: "codefling"},
+		// origins added with the API flag before adapters were selectable
+		{name: "api origin", origin: database.PluginOrigin{HasApi: 1}, expectedName: "umod"},
+		{name: "no adapter", origin: database.PluginOrigin{}, expectedError: ErrNoAdapter},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if name := AdapterName(test.origin); name != test.expectedName {
+				t.Errorf("AdapterName() = %q, want %q", name, test.expectedName)
+			}
+			if _, err := AdapterFor(test.origin); !errors.Is(err, test.expectedError) {
+				t.Errorf("AdapterFor() error = %v, want %v", err, test.expectedError)
+			}
+		})
+	}
+}
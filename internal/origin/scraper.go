@@ -0,0 +1,299 @@
+package origin
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"adminrust/internal/database"
+)
+
+// CSS class names used to locate parts of a plugin listing in HTML page.
+//
+// Empty class means the listing part isn't published by the origin.
+type ScrapeRules struct {
+	// element wrapping a single plugin listing
+	Item string
+	// element containing a link to the plugin page, link text is a name
+	Title       string
+	Description string
+	Version     string
+	// element with <time datetime="..."> or plain text date
+	Updated string
+
+	// parts of a plugin page describing the latest release
+	ReleaseVersion string
+	ReleaseNotes   string
+	ReleaseDate    string
+}
+
+// Rules for Codefling-like marketplaces built on Invision Community
+var CodeflingRules = ScrapeRules{
+	Item:        "ipsDataItem",
+	Title:       "ipsDataItem_title",
+	Description: "ipsDataItem_meta",
+	Version:     "cFileVersion",
+	Updated:     "cFileUpdated",
+
+	ReleaseVersion: "cFileInfoVersion",
+	ReleaseNotes:   "cFileChangelog",
+	ReleaseDate:    "cFileInfoUpdated",
+}
+
+// Dotted version like 1.2.3 or 2.0.0-beta somewhere in a text
+var versionPattern = regexp.MustCompile(`\d+(?:\.\d+)+(?:-[0-9A-Za-z.]+)?`)
+
+// Adapter for origins without API that publish plugins as HTML pages
+type ScraperAdapter struct {
+	// HTTP client used for requests, a default one is used if nil
+	Client *http.Client
+	Rules  ScrapeRules
+}
+
+// Fetch the first listing page and follow rel="next" links until the last one
+func (a *ScraperAdapter) FetchPlugins(ctx context.Context, origin database.PluginOrigin) (listings []Listing, err error) {
+	pageURL, err := url.Parse(origin.Url + origin.PathToPluginList)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plugin list URL: %w", err)
+	}
+
+	// protect from pagination that points back to a visited page
+	visited := map[string]bool{}
+	for len(visited) < maxListingPages {
+		visited[pageURL.String()] = true
+
+		body, err := fetch(ctx, a.Client, pageURL.String(), "text/html")
+		if err != nil {
+			return nil, err
+		}
+		doc, err := html.Parse(bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %w", pageURL, err)
+		}
+
+		listings = append(listings, a.parseListings(doc, pageURL)...)
+
+		nextURL := nextPageURL(doc, pageURL)
+		if nextURL == nil || visited[nextURL.String()] {
+			return listings, nil
+		}
+		pageURL = nextURL
+	}
+
+	return nil, fmt.Errorf("plugin list exceeds %d pages", maxListingPages)
+}
+
+// Parse the plugin page and extract the latest release details
+func (a *ScraperAdapter) FetchRelease(ctx context.Context, pluginURL string) (release Release, err error) {
+	body, err := fetch(ctx, a.Client, pluginURL, "text/html")
+	if err != nil {
+		return release, err
+	}
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return release, fmt.Errorf("error parsing %s: %w", pluginURL, err)
+	}
+
+	if node := findFirst(doc, byClass(a.Rules.ReleaseVersion)); node != nil {
+		release.Version = versionPattern.FindString(textOf(node))
+	}
+	if release.Version == "" {
+		return release, fmt.Errorf("no release version found at %s", pluginURL)
+	}
+	if node := findFirst(doc, byClass(a.Rules.ReleaseNotes)); node != nil {
+		release.Changelog = linesOf(node)
+	}
+	if node := findFirst(doc, byClass(a.Rules.ReleaseDate)); node != nil {
+		release.Date = dateOf(node)
+	}
+
+	return release, nil
+}
+
+// Extract plugin listings from a parsed listing page
+func (a *ScraperAdapter) parseListings(doc *html.Node, pageURL *url.URL) (listings []Listing) {
+	for _, item := range findAll(doc, byClass(a.Rules.Item)) {
+		title := findFirst(item, byClass(a.Rules.Title))
+		if title == nil {
+			continue
+		}
+		link := findFirst(title, byTag("a"))
+		if link == nil {
+			continue
+		}
+		pluginURL, err := pageURL.Parse(attr(link, "href"))
+		if err != nil {
+			continue
+		}
+
+		listing := Listing{
+			Name: textOf(link),
+			Url:  pluginURL.String(),
+		}
+		if node := findFirst(item, byClass(a.Rules.Description)); node != nil {
+			listing.Description = textOf(node)
+		}
+		if node := findFirst(item, byClass(a.Rules.Version)); node != nil {
+			listing.Version = versionPattern.FindString(textOf(node))
+		}
+		if node := findFirst(item, byClass(a.Rules.Updated)); node != nil {
+			listing.UpdatedAt = dateOf(node)
+		}
+
+		if listing.Name != "" {
+			listings = append(listings, listing)
+		}
+	}
+
+	return listings
+}
+
+// Resolve a link marked with rel="next" or return nil on the last page
+func nextPageURL(doc *html.Node, pageURL *url.URL) *url.URL {
+	next := findFirst(doc, func(n *html.Node) bool {
+		if n.Type != html.ElementNode || (n.Data != "a" && n.Data != "link") {
+			return false
+		}
+		return strings.EqualFold(attr(n, "rel"), "next") && attr(n, "href") != ""
+	})
+	if next == nil {
+		return nil
+	}
+
+	nextURL, err := pageURL.Parse(attr(next, "href"))
+	if err != nil {
+		return nil
+	}
+
+	return nextURL
+}
+
+// Node matcher used to search through HTML tree
+type matcher func(n *html.Node) bool
+
+// Match elements having the CSS class
+func byClass(class string) matcher {
+	return func(n *html.Node) bool {
+		if class == "" || n.Type != html.ElementNode {
+			return false
+		}
+		for _, c := range strings.Fields(attr(n, "class")) {
+			if c == class {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Match elements with the tag name
+func byTag(tag string) matcher {
+	return func(n *html.Node) bool {
+		return n.Type == html.ElementNode && n.Data == tag
+	}
+}
+
+// Return the first matching node in depth-first order
+func findFirst(n *html.Node, match matcher) *html.Node {
+	if match(n) {
+		return n
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if found := findFirst(child, match); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// Return all matching nodes without searching inside the matched ones
+func findAll(n *html.Node, match matcher) (found []*html.Node) {
+	if match(n) {
+		return []*html.Node{n}
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		found = append(found, findAll(child, match)...)
+	}
+
+	return found
+}
+
+// Get attribute value of the node
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+
+	return ""
+}
+
+// Collect text of the node with collapsed whitespace
+func textOf(n *html.Node) string {
+	var sb strings.Builder
+	var collect func(*html.Node)
+	collect = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+			sb.WriteByte(' ')
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			collect(child)
+		}
+	}
+	collect(n)
+
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// Block elements starting a new line of text
+var blockTags = map[string]bool{
+	"br": true, "p": true, "div": true, "li": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+// Collect text of the node keeping lines separated by block elements
+func linesOf(n *html.Node) string {
+	var sb strings.Builder
+	var collect func(*html.Node)
+	collect = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+		}
+		if n.Type == html.ElementNode && blockTags[n.Data] {
+			sb.WriteByte('\n')
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			collect(child)
+		}
+	}
+	collect(n)
+
+	var lines []string
+	for _, line := range strings.Split(sb.String(), "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Prefer a machine-readable <time datetime> value over a displayed date
+func dateOf(n *html.Node) string {
+	if timeNode := findFirst(n, byTag("time")); timeNode != nil {
+		if datetime := attr(timeNode, "datetime"); datetime != "" {
+			return datetime
+		}
+	}
+
+	return textOf(n)
+}
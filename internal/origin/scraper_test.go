@@ -0,0 +1,112 @@
+package origin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"adminrust/internal/database"
+)
+
+// Two listing pages linked with rel="next", the last one links back
+var scraperPages = map[string]string{
+	"/files/": `<html><body><ol>
+  <li class="ipsDataItem">
+    <h4 class="ipsDataItem_title"><a href="/files/file/1-kits/"> Kits </a></h4>
+    <div class="ipsDataItem_meta">Create and   give kits</div>
+    <span class="cFileVersion">Version 4.4.2</span>
+    <span class="cFileUpdated">Updated <time datetime="2025-03-01T10:00:00Z">March 1</time></span>
+  </li>
+  <li class="ipsDataItem ipsDataItem_unread">
+    <h4 class="ipsDataItem_title"><a href="https://example.com/files/file/2-zones/">Zone Manager</a></h4>
+    <span class="cFileVersion">v3.1.10-beta</span>
+  </li>
+  <li class="ipsDataItem"><span>broken item without title</span></li>
+</ol>
+<ul class="ipsPagination"><li class="ipsPagination_next"><a href="/files/page/2/" rel="next">Next</a></li></ul>
+</body></html>`,
+	"/files/page/2/": `<html><body><ol>
+  <li class="ipsDataItem">
+    <h4 class="ipsDataItem_title"><a href="/files/file/3-backpacks/">Backpacks</a></h4>
+    <span class="cFileUpdated">yesterday</span>
+  </li>
+</ol>
+<a href="/files/" rel="next">Back to start</a>
+</body></html>`,
+}
+
+func TestScraperFetchPlugins(t *testing.T) {
+	standIn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, exists := scraperPages[r.URL.Path]
+		if !exists {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(page))
+	}))
+	defer standIn.Close()
+
+	adapter := &ScraperAdapter{Client: standIn.Client(), Rules: CodeflingRules}
+	listings, err := adapter.FetchPlugins(context.Background(), database.PluginOrigin{
+		Url:              standIn.URL,
+		PathToPluginList: "/files/",
+	})
+	if err != nil {
+		t.Fatalf("FetchPlugins() error = %v", err)
+	}
+
+	expected := []Listing{
+		{
+			Name:        "Kits",
+			Description: "Create and give kits",
+			Url:         standIn.URL + "/files/file/1-kits/",
+			Version:     "4.4.2",
+			UpdatedAt:   "2025-03-01T10:00:00Z",
+		},
+		{
+			Name:    "Zone Manager",
+			Url:     "https://example.com/files/file/2-zones/",
+			Version: "3.1.10-beta",
+		},
+		{
+			Name:      "Backpacks",
+			Url:       standIn.URL + "/files/file/3-backpacks/",
+			UpdatedAt: "yesterday",
+		},
+	}
+	if len(listings) != len(expected) {
+		t.Fatalf("FetchPlugins() got %d listings, want %d: %+v", len(listings), len(expected), listings)
+	}
+	for i := range expected {
+		if listings[i] != expected[i] {
+			t.Errorf("listing %d = %+v, want %+v", i, listings[i], expected[i])
+		}
+	}
+}
+
+func TestScraperFetchRelease(t *testing.T) {
+	standIn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html><body>
+  <span class="cFileInfoVersion">Version 4.4.3</span>
+  <span class="cFileInfoUpdated"><time datetime="2025-04-02T08:00:00Z">April 2</time></span>
+  <div class="cFileChangelog"><ul><li>Fixed   cooldowns</li><li>Added <b>VIP</b> kits</li></ul></div>
+</body></html>`))
+	}))
+	defer standIn.Close()
+
+	adapter := &ScraperAdapter{Client: standIn.Client(), Rules: CodeflingRules}
+	release, err := adapter.FetchRelease(context.Background(), standIn.URL+"/files/file/1-kits/")
+	if err != nil {
+		t.Fatalf("FetchRelease() error = %v", err)
+	}
+
+	expected := Release{
+		Version:   "4.4.3",
+		Changelog: "Fixed cooldowns\nAdded VIP kits",
+		Date:      "2025-04-02T08:00:00Z",
+	}
+	if release != expected {
+		t.Errorf("FetchRelease() = %+v, want %+v", release, expected)
+	}
+}
@@ -0,0 +1,114 @@
+package origin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"adminrust/internal/database"
+)
+
+// Page limit to stop walking through a listing that never ends
+const maxListingPages = 500
+
+// Adapter for origins exposing a uMod-like paginated JSON API,
+// e.g. https://umod.org/plugins/search.json
+type UModAdapter struct {
+	// HTTP client used for requests, a default one is used if nil
+	Client *http.Client
+}
+
+// A single page of the plugin listing
+type umodPage struct {
+	CurrentPage int          `json:"current_page"`
+	LastPage    int          `json:"last_page"`
+	Data        []umodPlugin `json:"data"`
+}
+
+// A plugin entry of the listing page or a plugin page
+type umodPlugin struct {
+	Name                   string `json:"name"`
+	Title                  string `json:"title"`
+	Description            string `json:"description"`
+	Url                    string `json:"url"`
+	LatestReleaseVersion   string `json:"latest_release_version"`
+	LatestReleaseAt        string `json:"latest_release_at"`
+	LatestReleaseChangelog string `json:"latest_release_changelog"`
+}
+
+// Fetch all listing pages one by one until the last page is reached
+func (a *UModAdapter) FetchPlugins(ctx context.Context, origin database.PluginOrigin) (listings []Listing, err error) {
+	listURL, err := url.Parse(origin.Url + origin.PathToPluginList)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plugin list URL: %w", err)
+	}
+
+	for page := 1; page <= maxListingPages; page++ {
+		// keep the query set in origin path and only replace the page number
+		query := listURL.Query()
+		query.Set("page", strconv.Itoa(page))
+		listURL.RawQuery = query.Encode()
+
+		var listPage umodPage
+		if err = a.getJSON(ctx, listURL.String(), &listPage); err != nil {
+			return nil, err
+		}
+
+		for _, plugin := range listPage.Data {
+			// title is a human-readable name, while name is a class name
+			name := plugin.Title
+			if name == "" {
+				name = plugin.Name
+			}
+			listings = append(listings, Listing{
+				Name:        name,
+				Description: plugin.Description,
+				Url:         plugin.Url,
+				Version:     plugin.LatestReleaseVersion,
+				UpdatedAt:   plugin.LatestReleaseAt,
+			})
+		}
+
+		if len(listPage.Data) == 0 || listPage.CurrentPage >= listPage.LastPage {
+			return listings, nil
+		}
+	}
+
+	return nil, fmt.Errorf("plugin list exceeds %d pages", maxListingPages)
+}
+
+// Fetch JSON representation of the plugin page, e.g.
+// https://umod.org/plugins/kits.json
+func (a *UModAdapter) FetchRelease(ctx context.Context, pluginURL string) (release Release, err error) {
+	var plugin umodPlugin
+	if err = a.getJSON(ctx, strings.TrimSuffix(pluginURL, "/")+".json", &plugin); err != nil {
+		return release, err
+	}
+	if plugin.LatestReleaseVersion == "" {
+		return release, fmt.Errorf("no release version published at %s", pluginURL)
+	}
+
+	return Release{
+		Version:   plugin.LatestReleaseVersion,
+		Changelog: plugin.LatestReleaseChangelog,
+		Date:      plugin.LatestReleaseAt,
+	}, nil
+}
+
+// Send GET request and decode JSON response into target
+func (a *UModAdapter) getJSON(ctx context.Context, rawURL string, target any) error {
+	body, err := fetch(ctx, a.Client, rawURL, "application/json")
+	if err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(body, target); err != nil {
+		return fmt.Errorf("error decoding %s: %w", rawURL, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package origin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"adminrust/internal/database"
+)
+
+// Serve a paginated uMod-like listing with the given pages
+func newUModStandIn(t *testing.T, pages [][]umodPlugin) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/plugins/search.json" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("sort") != "title" {
+			t.Errorf("origin query was lost: %s", r.URL.RawQuery)
+		}
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		if err != nil || page < 1 || page > len(pages) {
+			http.Error(w, "bad page", http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(umodPage{
+			CurrentPage: page,
+			LastPage:    len(pages),
+			Data:        pages[page-1],
+		})
+	}))
+}
+
+func TestUModFetchPlugins(t *testing.T) {
+	standIn := newUModStandIn(t, [][]umodPlugin{
+		{
+			{Name: "Kits", Title: "Kits", Description: "Create kits", Url: "https://umod.org/plugins/kits", LatestReleaseVersion: "4.4.2"},
+			{Name: "BetterChat", Title: "Better Chat", Description: "Chat groups", Url: "https://umod.org/plugins/better-chat"},
+		},
+		{
+			{Name: "NoTitle", Description: "Falls back to name", Url: "https://umod.org/plugins/no-title"},
+		},
+	})
+	defer standIn.Close()
+
+	adapter := &UModAdapter{Client: standIn.Client()}
+	listings, err := adapter.FetchPlugins(context.Background(), database.PluginOrigin{
+		Url:              standIn.URL,
+		PathToPluginList: "/plugins/search.json?sort=title",
+		HasApi:           1,
+	})
+	if err != nil {
+		t.Fatalf("FetchPlugins() error = %v", err)
+	}
+
+	expectedNames := []string{"Kits", "Better Chat", "NoTitle"}
+	if len(listings) != len(expectedNames) {
+		t.Fatalf("FetchPlugins() got %d listings, want %d", len(listings), len(expectedNames))
+	}
+	for i, name := range expectedNames {
+		if listings[i].Name != name {
+			t.Errorf("listing %d name = %v, want %v", i, listings[i].Name, name)
+		}
+	}
+	if listings[0].Version != "4.4.2" {
+		t.Errorf("listing version = %v, want %v", listings[0].Version, "4.4.2")
+	}
+}
+
+func TestUModFetchPluginsError(t *testing.T) {
+	standIn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer standIn.Close()
+
+	adapter := &UModAdapter{Client: standIn.Client()}
+	_, err := adapter.FetchPlugins(context.Background(), database.PluginOrigin{
+		Url:              standIn.URL,
+		PathToPluginList: "/plugins/search.json",
+		HasApi:           1,
+	})
+	if err == nil {
+		t.Error("FetchPlugins() expected error on unavailable origin")
+	}
+}
@@ -0,0 +1,86 @@
+package oxide
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"time"
+)
+
+// Supported archive formats
+const (
+	Zip   = "zip"
+	TarGz = "tar.gz"
+)
+
+// File to put into an archive
+type File struct {
+	Path     string
+	Data     []byte
+	Modified time.Time
+}
+
+// MIME type of the archive format
+func ContentType(format string) string {
+	if format == TarGz {
+		return "application/gzip"
+	}
+
+	return "application/zip"
+}
+
+// Write files into a zip or tar.gz archive
+func WriteArchive(w io.Writer, format string, files []File) error {
+	if format == TarGz {
+		return writeTarGz(w, files)
+	}
+
+	return writeZip(w, files)
+}
+
+// Write files into a zip archive
+func writeZip(w io.Writer, files []File) error {
+	archive := zip.NewWriter(w)
+	for _, file := range files {
+		entry, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     file.Path,
+			Method:   zip.Deflate,
+			Modified: file.Modified,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err = entry.Write(file.Data); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+// Write files into a gzip-compressed tar archive
+func writeTarGz(w io.Writer, files []File) error {
+	compressed := gzip.NewWriter(w)
+	archive := tar.NewWriter(compressed)
+	for _, file := range files {
+		err := archive.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     file.Path,
+			Mode:     0o644,
+			Size:     int64(len(file.Data)),
+			ModTime:  file.Modified,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err = archive.Write(file.Data); err != nil {
+			return err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	return compressed.Close()
+}
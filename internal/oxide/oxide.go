@@ -0,0 +1,43 @@
+// Package oxide maps plugin data to the Oxide directory layout
+// of a game server and packs it into archives for deploying.
+package oxide
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Directories of an Oxide installation
+const (
+	RootDir    = "oxide"
+	PluginsDir = RootDir + "/plugins"
+	ConfigDir  = RootDir + "/config"
+	LangDir    = RootDir + "/lang"
+)
+
+// Characters Oxide doesn't allow in plugin class names
+var notIdentifier = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// Name Oxide knows the plugin by: the source file name without extension
+// if known or the plugin name, without spaces and symbols either way.
+// Uploaded file names may come with Windows paths.
+func PluginName(sourceFileName, name string) string {
+	base := path.Base(strings.ReplaceAll(sourceFileName, `\`, "/"))
+	base = notIdentifier.ReplaceAllString(strings.TrimSuffix(base, ".cs"), "")
+	if sourceFileName != "" && base != "" {
+		return base
+	}
+
+	return notIdentifier.ReplaceAllString(name, "")
+}
+
+// Path of the plugin config file
+func ConfigPath(pluginName string) string {
+	return path.Join(ConfigDir, pluginName+".json")
+}
+
+// Path of the plugin locale file for the language code
+func LangPath(langCode, pluginName string) string {
+	return path.Join(LangDir, langCode, pluginName+".json")
+}
@@ -0,0 +1,145 @@
+package oxide
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestPluginName(t *testing.T) {
+	tests := []struct {
+		name, sourceFileName, pluginName string
+		expectedName                     string
+	}{
+		{name: "source file", sourceFileName: "BetterChat.cs", pluginName: "Better Chat", expectedName: "BetterChat"},
+		{name: "source path", sourceFileName: "plugins/Kits.cs", pluginName: "kits", expectedName: "Kits"},
+		{name: "windows path", sourceFileName: `C:\plugins\Kits.cs`, pluginName: "kits", expectedName: "Kits"},
+		{name: "copied file", sourceFileName: "Kits (1).cs", pluginName: "kits", expectedName: "Kits1"},
+		{name: "no usable file name", sourceFileName: "../.cs", pluginName: "Kits", expectedName: "Kits"},
+		{name: "from name", pluginName: "Better Chat: Mute", expectedName: "BetterChatMute"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if name := PluginName(test.sourceFileName, test.pluginName); name != test.expectedName {
+				t.Errorf("PluginName() = %q, want %q", name, test.expectedName)
+			}
+		})
+	}
+}
+
+func TestWriteArchive(t *testing.T) {
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	files := []File{
+		{Path: ConfigPath("Kits"), Data: []byte("{}"), Modified: modified},
+		{Path: LangPath("en", "Kits"), Data: []byte(`{"A": "b"}`), Modified: modified},
+	}
+
+	for _, format := range []string{Zip, TarGz} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteArchive(&buf, format, files); err != nil {
+				t.Fatal(err)
+			}
+
+			read := readArchive(t, format, buf.Bytes())
+			if len(read) != len(files) {
+				t.Fatalf("archive has %d files, want %d", len(read), len(files))
+			}
+			for _, file := range files {
+				if data, ok := read[file.Path]; !ok || data != string(file.Data) {
+					t.Errorf("archive file %s = %q, want %q", file.Path, data, file.Data)
+				}
+			}
+		})
+	}
+}
+
+// Read archive files by their paths
+func readArchive(t *testing.T, format string, data []byte) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+
+	if format == Zip {
+		archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, file := range archive.File {
+			entry, err := file.Open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			content, err := io.ReadAll(entry)
+			entry.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			files[file.Name] = string(content)
+		}
+		return files
+	}
+
+	compressed, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive := tar.NewReader(compressed)
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(archive)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[header.Name] = string(content)
+	}
+	return files
+}
+
+func TestParsePluginList(t *testing.T) {
+	output := `Listing 4 plugins:
+  01 "Better Chat" (5.2.14) by LaserHydra (0.12s / 1.53 MB) - BetterChat.cs
+  02 "Kits" (4.4.2) by k1lly0u (1,50s) - Kits.cs
+  03 "Rust.io (Map)" (1.0.0) by Someone (0.00s / 0 B) - RustIO.cs
+  04 Vanish - Failed to compile: Vanish.cs(12,5): error CS1002
+  Vanish.cs(13,1): error CS1513
+`
+	plugins, err := ParsePluginList(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []ListedPlugin{
+		{Title: "Better Chat", Version: "5.2.14", Author: "LaserHydra", HookTime: 120 * time.Millisecond, FileName: "BetterChat.cs"},
+		{Title: "Kits", Version: "4.4.2", Author: "k1lly0u", HookTime: 1500 * time.Millisecond, FileName: "Kits.cs"},
+		{Title: "Rust.io (Map)", Version: "1.0.0", Author: "Someone", FileName: "RustIO.cs"},
+		{Title: "Vanish", FileName: "Vanish.cs", Error: "Failed to compile: Vanish.cs(12,5): error CS1002\nVanish.cs(13,1): error CS1513"},
+	}
+	if len(plugins) != len(expected) {
+		t.Fatalf("ParsePluginList() = %+v, want %d plugins", plugins, len(expected))
+	}
+	for i, plugin := range plugins {
+		if plugin != expected[i] {
+			t.Errorf("plugin %d = %+v, want %+v", i, plugin, expected[i])
+		}
+	}
+	if name := plugins[0].Name(); name != "BetterChat" {
+		t.Errorf("Name() = %q, want BetterChat", name)
+	}
+
+	for _, output := range []string{"", "Unknown command: oxide.plugins", "Listing 1 plugins:\n  01 Kits"} {
+		if _, err := ParsePluginList(output); err == nil {
+			t.Errorf("ParsePluginList(%q) succeeded, want error", output)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package oxide
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Console command listing plugins of a server
+const PluginsCommand = "oxide.plugins"
+
+// Returned for output that isn't a plugin list, e.g. of another framework
+var ErrNotPluginList = errors.New("not an oxide.plugins list")
+
+var (
+	// Listing 2 plugins:
+	listHeader = regexp.MustCompile(`^Listing \d+ plugins?:?$`)
+	// 01 "Better Chat" (5.2.14) by LaserHydra (0.12s / 1.53 MB) - BetterChat.cs
+	//
+	// Older Oxide builds don't report hook memory.
+	loadedLine = regexp.MustCompile(`^\d+ "(.*)" \((.*)\) by (.*) \(([\d.,]+)s(?: / [^)]*)?\) - (.+)$`)
+	// 03 Kits - Failed to compile: Kits.cs(12,5): error CS1002
+	failedLine = regexp.MustCompile(`^\d+ (\S+) - (.+)$`)
+)
+
+// Plugin of the oxide.plugins output
+type ListedPlugin struct {
+	Title, Version, Author string
+	// total time spent in hooks since the plugin was loaded
+	HookTime time.Duration
+	// source file name like Kits.cs
+	FileName string
+	// why the plugin isn't loaded, loaded plugins have none
+	Error string
+}
+
+// Name Oxide knows the listed plugin by
+func (p ListedPlugin) Name() string {
+	return PluginName(p.FileName, p.Title)
+}
+
+// Parse the oxide.plugins output into loaded plugins and ones that
+// failed to load
+func ParsePluginList(output string) ([]ListedPlugin, error) {
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	var plugins []ListedPlugin
+	hasHeader := false
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if !hasHeader {
+			if !listHeader.MatchString(line) {
+				return nil, ErrNotPluginList
+			}
+			hasHeader = true
+			continue
+		}
+
+		if match := loadedLine.FindStringSubmatch(line); match != nil {
+			// hook time is formatted with the culture of the server
+			seconds, err := strconv.ParseFloat(strings.ReplaceAll(match[4], ",", "."), 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: hook time %q: %w", lineNum, match[4], err)
+			}
+			plugins = append(plugins, ListedPlugin{
+				Title:    match[1],
+				Version:  match[2],
+				Author:   match[3],
+				HookTime: time.Duration(math.Round(seconds * float64(time.Second))),
+				FileName: match[5],
+			})
+			continue
+		}
+		if match := failedLine.FindStringSubmatch(line); match != nil {
+			plugins = append(plugins, ListedPlugin{
+				Title:    match[1],
+				FileName: match[1] + ".cs",
+				Error:    match[2],
+			})
+			continue
+		}
+		// compiler errors may span several lines
+		if last := len(plugins) - 1; last >= 0 && plugins[last].Error != "" {
+			plugins[last].Error += "\n" + line
+			continue
+		}
+
+		return nil, fmt.Errorf("line %d: unexpected plugin entry %q", lineNum, line)
+	}
+	if !hasHeader {
+		return nil, ErrNotPluginList
+	}
+
+	return plugins, scanner.Err()
+}
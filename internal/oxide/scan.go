@@ -0,0 +1,176 @@
+package oxide
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+
+	"adminrust/internal/analyzer"
+	"adminrust/internal/locales"
+)
+
+// Largest file read from an Oxide directory
+const MaxFileSize = 10 << 20
+
+// Plugin files found in an Oxide directory
+type Plugin struct {
+	// name Oxide knows the plugin by, the same as file names
+	Name       string
+	SourceFile string
+	Info       analyzer.Info
+	Config     string
+	// locale contents by language codes
+	Locales map[string]string
+}
+
+// Plugins found in an Oxide directory and files that couldn't be read
+type Installation struct {
+	Plugins  []Plugin
+	Problems []string
+}
+
+// Read plugin sources, configs and locales of an Oxide directory. The file
+// system may be the Oxide directory itself, contain it at the top level
+// or inside a single directory, as archives often do.
+func Scan(fsys fs.FS) (installation Installation, err error) {
+	root, err := findRoot(fsys)
+	if err != nil {
+		return installation, err
+	}
+
+	plugins := make(map[string]*Plugin)
+	plugin := func(name string) *Plugin {
+		if plugins[name] == nil {
+			plugins[name] = &Plugin{Name: name, Locales: make(map[string]string)}
+		}
+		return plugins[name]
+	}
+	problem := func(filePath string, err error) {
+		installation.Problems = append(installation.Problems, fmt.Sprintf("%s: %v", filePath, err))
+	}
+
+	sources, err := fs.Glob(fsys, path.Join(root, "plugins", "*.cs"))
+	if err != nil {
+		return installation, err
+	}
+	for _, filePath := range sources {
+		data, err := readFile(fsys, filePath)
+		if err != nil {
+			problem(filePath, err)
+			continue
+		}
+		p := plugin(strings.TrimSuffix(path.Base(filePath), ".cs"))
+		p.SourceFile = path.Base(filePath)
+		p.Info = analyzer.ReadInfo(data)
+	}
+
+	configs, err := fs.Glob(fsys, path.Join(root, "config", "*.json"))
+	if err != nil {
+		return installation, err
+	}
+	for _, filePath := range configs {
+		data, err := readJSON(fsys, filePath)
+		if err != nil {
+			problem(filePath, err)
+			continue
+		}
+		plugin(strings.TrimSuffix(path.Base(filePath), ".json")).Config = data
+	}
+
+	langFiles, err := fs.Glob(fsys, path.Join(root, "lang", "*", "*.json"))
+	if err != nil {
+		return installation, err
+	}
+	for _, filePath := range langFiles {
+		data, err := readFile(fsys, filePath)
+		if err != nil {
+			problem(filePath, err)
+			continue
+		}
+		// lang files are stored the way the locale forms save them
+		content, err := locales.Normalize(string(data))
+		if err != nil {
+			problem(filePath, err)
+			continue
+		}
+		langCode := path.Base(path.Dir(filePath))
+		plugin(strings.TrimSuffix(path.Base(filePath), ".json")).Locales[langCode] = content
+	}
+
+	for _, p := range plugins {
+		installation.Plugins = append(installation.Plugins, *p)
+	}
+	slices.SortFunc(installation.Plugins, func(a, b Plugin) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+
+	return installation, nil
+}
+
+// Find the Oxide directory: the root itself, its oxide directory,
+// or the oxide directory inside the only top-level directory
+func findRoot(fsys fs.FS) (string, error) {
+	isOxide := func(dir string) bool {
+		for _, sub := range []string{"plugins", "config", "lang"} {
+			if info, err := fs.Stat(fsys, path.Join(dir, sub)); err == nil && info.IsDir() {
+				return true
+			}
+		}
+		return false
+	}
+
+	if isOxide(RootDir) {
+		return RootDir, nil
+	}
+	if isOxide(".") {
+		return ".", nil
+	}
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		top := entries[0].Name()
+		if isOxide(path.Join(top, RootDir)) {
+			return path.Join(top, RootDir), nil
+		}
+		if isOxide(top) {
+			return top, nil
+		}
+	}
+
+	return "", errors.New("no oxide directory with plugins, config or lang found")
+}
+
+// Read a file refusing large ones
+func readFile(fsys fs.FS, filePath string) ([]byte, error) {
+	info, err := fs.Stat(fsys, filePath)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > MaxFileSize {
+		return nil, fmt.Errorf("file exceeds %d bytes", MaxFileSize)
+	}
+
+	return fs.ReadFile(fsys, filePath)
+}
+
+// Read a JSON file without the byte order mark Windows editors add
+func readJSON(fsys fs.FS, filePath string) (string, error) {
+	data, err := readFile(fsys, filePath)
+	if err != nil {
+		return "", err
+	}
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	if !json.Valid(data) {
+		return "", errors.New("invalid JSON")
+	}
+
+	return string(data), nil
+}
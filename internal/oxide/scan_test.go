@@ -0,0 +1,53 @@
+package oxide
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestScan(t *testing.T) {
+	files := fstest.MapFS{
+		"server/oxide/plugins/Kits.cs":           {Data: []byte(`[Info("Kits", "k1lly0u", "4.4.2")] class Kits {}`)},
+		"server/oxide/plugins/readme.txt":        {Data: []byte("not a plugin")},
+		"server/oxide/config/Kits.json":          {Data: []byte("\ufeff{\"Limit\": 5}")},
+		"server/oxide/config/Broken.json":        {Data: []byte("{")},
+		"server/oxide/lang/en/Kits.json":         {Data: []byte("\ufeff{\"NoPermission\":\"No!\"}")},
+		"server/oxide/lang/de/Kits.json":         {Data: []byte(`{"Messages": {"NoPermission": "Nein!"}}`)},
+		"server/oxide/lang/ru/BetterChat.json":   {Data: []byte(`{}`)},
+		"server/oxide/data/Kits/Players.json":    {Data: []byte(`{}`)},
+		"server/oxide/logs/oxide_2024-05-01.txt": {Data: []byte("log")},
+	}
+
+	installation, err := Scan(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(installation.Plugins) != 2 {
+		t.Fatalf("Scan() found %d plugins, want 2: %+v", len(installation.Plugins), installation.Plugins)
+	}
+	betterChat, kits := installation.Plugins[0], installation.Plugins[1]
+	if betterChat.Name != "BetterChat" || betterChat.SourceFile != "" || betterChat.Locales["ru"] != "{}" {
+		t.Errorf("BetterChat = %+v", betterChat)
+	}
+	if kits.SourceFile != "Kits.cs" || kits.Info.Version != "4.4.2" {
+		t.Errorf("Kits source = %q, info = %+v", kits.SourceFile, kits.Info)
+	}
+	if kits.Config != `{"Limit": 5}` {
+		t.Errorf("Kits config = %q, want it without BOM", kits.Config)
+	}
+	if len(kits.Locales) != 1 || kits.Locales["en"] != "{\n  \"NoPermission\": \"No!\"\n}" {
+		t.Errorf("Kits locales = %q, want normalized English only", kits.Locales)
+	}
+	if len(installation.Problems) != 2 {
+		t.Errorf("Scan() problems = %q, want the broken config and the nested lang file", installation.Problems)
+	}
+
+	// the root may be the oxide directory itself
+	if _, err = Scan(fstest.MapFS{"config/Kits.json": {Data: []byte("{}")}}); err != nil {
+		t.Errorf("Scan() of oxide directory: %v", err)
+	}
+	if _, err = Scan(fstest.MapFS{"Kits.json": {Data: []byte("{}")}}); err == nil {
+		t.Error("Scan() without oxide directory succeeded, want error")
+	}
+}
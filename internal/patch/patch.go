@@ -0,0 +1,210 @@
+// Package patch applies diff hunks to texts that changed since the hunks
+// were made, relocating them by their context lines.
+package patch
+
+import (
+	"slices"
+	"strings"
+
+	"adminrust/internal/diff"
+)
+
+// Number of context lines that may be ignored at each side of a hunk
+// to find it, as patch(1) does by default
+const maxFuzz = 2
+
+// Outcome of applying a hunk
+type Status int
+
+const (
+	// found where the hunk expects it
+	Applied Status = iota
+	// found at another position or with ignored context lines
+	Moved
+	// not found, the text isn't changed by the hunk
+	Conflict
+)
+
+func (s Status) String() string {
+	return [...]string{"applied", "moved", "conflict"}[s]
+}
+
+// Report of applying a hunk
+type Report struct {
+	Status Status
+	// lines between the position in the hunk header and the found one
+	Offset int
+	// context lines ignored at each side to find the hunk
+	Fuzz int
+	// 1-based range of the replaced lines in the merged text,
+	// End is less than Start if the hunk only deletes lines
+	Start, End int
+}
+
+// Merged text and reports in the order of given hunks
+type Result struct {
+	Lines   []string
+	Reports []Report
+}
+
+// Number of hunks with the status
+func (r Result) Count(status Status) (count int) {
+	for _, report := range r.Reports {
+		if report.Status == status {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Part of the text replaced by a hunk
+type replacement struct {
+	start, end int
+	lines      []string
+	hunk       int
+}
+
+// Apply hunks to lines. Hunks are searched nearest to their positions
+// shifted by the offset of the previous hunk, they may not overlap.
+func Apply(lines []string, hunks []diff.Hunk) Result {
+	result := Result{Reports: make([]Report, len(hunks))}
+
+	// hunks are applied from the top of the text
+	order := make([]int, len(hunks))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return hunks[a].OldStart - hunks[b].OldStart
+	})
+
+	var replacements []replacement
+	searchFrom, offset := 0, 0
+	for _, i := range order {
+		found, report := locate(lines, hunks[i], searchFrom, offset)
+		result.Reports[i] = report
+		if report.Status == Conflict {
+			continue
+		}
+
+		found.hunk = i
+		replacements = append(replacements, found)
+		searchFrom = found.end
+		offset = report.Offset
+	}
+
+	// replace found parts keeping track of merged line numbers
+	previousEnd := 0
+	for _, r := range replacements {
+		result.Lines = append(result.Lines, lines[previousEnd:r.start]...)
+		result.Reports[r.hunk].Start = len(result.Lines) + 1
+		result.Lines = append(result.Lines, r.lines...)
+		result.Reports[r.hunk].End = len(result.Lines)
+		previousEnd = r.end
+	}
+	result.Lines = append(result.Lines, lines[previousEnd:]...)
+
+	return result
+}
+
+// Find the old lines of the hunk not before the line searchFrom trying
+// fewer context lines if they aren't found
+func locate(lines []string, hunk diff.Hunk, searchFrom, offset int) (replacement, Report) {
+	var old, new []string
+	for _, line := range hunk.Lines {
+		if line.Op != diff.Insert {
+			old = append(old, line.Text)
+		}
+		if line.Op != diff.Delete {
+			new = append(new, line.Text)
+		}
+	}
+	leading, trailing := contextLines(hunk.Lines)
+
+	// an empty old range starts after the line in the header
+	expected := hunk.OldStart - 1
+	if hunk.OldLines == 0 {
+		expected = hunk.OldStart
+	}
+	expected += offset
+
+	for fuzz := 0; fuzz <= maxFuzz; fuzz++ {
+		skipStart, skipEnd := min(fuzz, leading), min(fuzz, trailing)
+		if fuzz > 0 && skipStart+skipEnd == 0 {
+			// no context to ignore
+			break
+		}
+
+		pattern := old[skipStart : len(old)-skipEnd]
+		if fuzz > 0 && len(pattern) == 0 {
+			// an empty pattern would match anywhere
+			break
+		}
+		position, ok := find(lines, pattern, expected+skipStart, searchFrom)
+		if !ok {
+			continue
+		}
+
+		report := Report{Status: Applied, Offset: position - skipStart - expected + offset, Fuzz: fuzz}
+		if report.Offset != 0 || fuzz > 0 {
+			report.Status = Moved
+		}
+		return replacement{
+			start: position,
+			end:   position + len(pattern),
+			lines: new[skipStart : len(new)-skipEnd],
+		}, report
+	}
+
+	return replacement{}, Report{Status: Conflict}
+}
+
+// Count context lines at the start and the end of hunk lines
+func contextLines(lines []diff.Line) (leading, trailing int) {
+	for leading < len(lines) && lines[leading].Op == diff.Equal {
+		leading++
+	}
+	if leading == len(lines) {
+		return leading, 0
+	}
+	for trailing < len(lines) && lines[len(lines)-1-trailing].Op == diff.Equal {
+		trailing++
+	}
+
+	return leading, trailing
+}
+
+// Find the pattern nearest to the expected position, searching outwards
+// and not before the line searchFrom
+func find(lines, pattern []string, expected, searchFrom int) (position int, ok bool) {
+	last := len(lines) - len(pattern)
+	if last < searchFrom {
+		return 0, false
+	}
+	expected = min(max(expected, searchFrom), last)
+
+	for distance := 0; expected-distance >= searchFrom || expected+distance <= last; distance++ {
+		for _, candidate := range []int{expected + distance, expected - distance} {
+			if candidate >= searchFrom && candidate <= last && matches(lines[candidate:], pattern) {
+				return candidate, true
+			}
+			if distance == 0 {
+				break
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// Check if lines start with the pattern ignoring trailing whitespace
+func matches(lines, pattern []string) bool {
+	for i, line := range pattern {
+		if strings.TrimRight(lines[i], " \t") != strings.TrimRight(line, " \t") {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,97 @@
+package patch
+
+import (
+	"slices"
+	"testing"
+
+	"adminrust/internal/diff"
+)
+
+func TestApply(t *testing.T) {
+	original := diff.SplitLines("using Oxide;\n\nclass Kits\n{\n    int limit = 1;\n    int cooldown = 60;\n\n    void Init()\n    {\n        Puts(\"loaded\");\n    }\n}\n")
+	patched := diff.SplitLines("using Oxide;\n\nclass Kits\n{\n    int limit = 5;\n    int cooldown = 60;\n\n    void Init()\n    {\n        Puts(\"loaded with patches\");\n    }\n}\n")
+	hunks := diff.Hunks(original, patched, 3)
+	if len(hunks) != 1 {
+		// both changes are close enough to form a single hunk with 3 context lines
+		t.Fatalf("Hunks() = %d hunks, want 1", len(hunks))
+	}
+	separateHunks := diff.Hunks(original, patched, 1)
+
+	tests := []struct {
+		name             string
+		updated          string
+		hunks            []diff.Hunk
+		expectedStatuses []Status
+		expectedOutput   string
+	}{
+		{
+			name:             "same version",
+			updated:          "using Oxide;\n\nclass Kits\n{\n    int limit = 1;\n    int cooldown = 60;\n\n    void Init()\n    {\n        Puts(\"loaded\");\n    }\n}\n",
+			hunks:            separateHunks,
+			expectedStatuses: []Status{Applied, Applied},
+			expectedOutput:   "using Oxide;\n\nclass Kits\n{\n    int limit = 5;\n    int cooldown = 60;\n\n    void Init()\n    {\n        Puts(\"loaded with patches\");\n    }\n}\n",
+		},
+		{
+			name:             "lines added above",
+			updated:          "using System;\nusing Oxide;\n\nclass Kits\n{\n    int limit = 1;\n    int cooldown = 60;\n\n    void Init()\n    {\n        Puts(\"loaded\");\n    }\n}\n",
+			hunks:            separateHunks,
+			expectedStatuses: []Status{Moved, Moved},
+			expectedOutput:   "using System;\nusing Oxide;\n\nclass Kits\n{\n    int limit = 5;\n    int cooldown = 60;\n\n    void Init()\n    {\n        Puts(\"loaded with patches\");\n    }\n}\n",
+		},
+		{
+			name:             "changed context",
+			updated:          "using Oxide;\n\nclass Kits\n{\n    int limit = 1;\n    int cooldown = 30;\n\n    void Init()\n    {\n        Puts(\"loaded\");\n    }\n}\n",
+			hunks:            hunks,
+			expectedStatuses: []Status{Conflict},
+			expectedOutput:   "using Oxide;\n\nclass Kits\n{\n    int limit = 1;\n    int cooldown = 30;\n\n    void Init()\n    {\n        Puts(\"loaded\");\n    }\n}\n",
+		},
+		{
+			name:             "changed outer context",
+			updated:          "using Oxide;\n\nclass Kits\n{ // settings\n    int limit = 1;\n    int cooldown = 60;\n\n    void Init()\n    {\n        Puts(\"loaded\");\n    }\n}\n",
+			hunks:            separateHunks,
+			expectedStatuses: []Status{Moved, Applied},
+			expectedOutput:   "using Oxide;\n\nclass Kits\n{ // settings\n    int limit = 5;\n    int cooldown = 60;\n\n    void Init()\n    {\n        Puts(\"loaded with patches\");\n    }\n}\n",
+		},
+		{
+			name:             "changed line removed",
+			updated:          "using Oxide;\n\nclass Kits\n{\n    int limit = 1;\n    int cooldown = 60;\n\n    void Init()\n    {\n    }\n}\n",
+			hunks:            separateHunks,
+			expectedStatuses: []Status{Applied, Conflict},
+			expectedOutput:   "using Oxide;\n\nclass Kits\n{\n    int limit = 5;\n    int cooldown = 60;\n\n    void Init()\n    {\n    }\n}\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := Apply(diff.SplitLines(test.updated), test.hunks)
+
+			statuses := make([]Status, len(result.Reports))
+			for i, report := range result.Reports {
+				statuses[i] = report.Status
+			}
+			if !slices.Equal(statuses, test.expectedStatuses) {
+				t.Errorf("Apply() statuses = %v, want %v", statuses, test.expectedStatuses)
+			}
+			if output := diff.SplitLines(test.expectedOutput); !slices.Equal(result.Lines, output) {
+				t.Errorf("Apply() lines =\n%q\nwant\n%q", result.Lines, output)
+			}
+		})
+	}
+}
+
+func TestApplyReportsMergedRange(t *testing.T) {
+	hunk, err := diff.ParseHunk("@@ -2,3 +2,4 @@\n b\n-c\n+C\n+C2\n d\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// two lines added above move the hunk by 2
+	result := Apply(diff.SplitLines("x\ny\na\nb\nc\nd\ne\n"), []diff.Hunk{hunk})
+	report := result.Reports[0]
+	if report.Status != Moved || report.Offset != 2 || report.Start != 4 || report.End != 7 {
+		t.Errorf("Apply() report = %+v, want moved by 2 to lines 4-7", report)
+	}
+	if merged := result.Lines[report.Start-1 : report.End]; !slices.Equal(merged, []string{"b", "C", "C2", "d"}) {
+		t.Errorf("merged lines of the hunk = %q", merged)
+	}
+}
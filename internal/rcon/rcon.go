@@ -0,0 +1,352 @@
+// Package rcon talks to Rust servers over WebRCON: JSON frames sent
+// through a WebSocket opened at ws://host:port/password.
+package rcon
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Time a command waits for its response unless the client sets another one
+const DefaultTimeout = 10 * time.Second
+
+// Default WebRCON port, the game port plus one by convention
+const DefaultPort = 28016
+
+const (
+	// name servers log commands of this client with
+	clientName = "AdmInRust"
+	// attempts to open a connection before giving up
+	dialAttempts = 3
+	// pause before the next attempt, growing with every attempt
+	redialDelay = 500 * time.Millisecond
+	// largest frame read from the server, console output may be long
+	maxFrameSize = 8 << 20
+	// frames buffered for a subscriber, newer ones are dropped while it's full
+	subscriberBuffer = 64
+)
+
+var (
+	// Returned when the server refuses the password during the handshake
+	ErrUnauthorized = errors.New("rcon: password rejected")
+	// Returned when no response arrives in time
+	ErrTimeout = errors.New("rcon: no response in time")
+	// Returned when the connection closes before the response arrives.
+	// Rust servers close connections with a wrong password this way too.
+	ErrDisconnected = errors.New("rcon: connection closed")
+	// Returned by commands executed after closing the client
+	ErrClosed = errors.New("rcon: client closed")
+)
+
+// WebRCON frame. Requests carry a command in Message and responses
+// repeat the Identifier of their request. Frames the server sends on its
+// own, like console output or chat, have identifiers of no request.
+type Message struct {
+	Identifier int    `json:"Identifier"`
+	Message    string `json:"Message"`
+	Name       string `json:"Name,omitempty"`
+	// Generic, Error, Warning, Chat or Report
+	Type       string `json:"Type,omitempty"`
+	Stacktrace string `json:"Stacktrace,omitempty"`
+}
+
+// Client of a single server. It connects on the first command, matches
+// responses to commands by identifiers and reconnects after the
+// connection drops. Commands may be executed concurrently.
+type Client struct {
+	// host:port of the WebRCON listener
+	Addr     string
+	Password string
+	// time a command waits for its response, DefaultTimeout if zero
+	Timeout time.Duration
+	// receives frames that aren't responses, called from the reading goroutine
+	OnMessage func(Message)
+	// dialer used for connecting, websocket.DefaultDialer if nil
+	Dialer *websocket.Dialer
+
+	mu      sync.Mutex
+	conn    *websocket.Conn
+	lastID  int
+	pending map[int]chan Message
+	closed  bool
+	// closed when the dial in progress finishes, nil while none is
+	dialing chan struct{}
+	// channels of Subscribe by their numbers
+	lastSub     int
+	subscribers map[int]chan Message
+
+	// a connection supports one writer at a time
+	writeMu sync.Mutex
+}
+
+// Create a client of the server listening for WebRCON at host:port
+func New(host string, port int, password string) *Client {
+	return &Client{
+		Addr:     net.JoinHostPort(host, strconv.Itoa(port)),
+		Password: password,
+	}
+}
+
+// Execute a console command and wait for its response
+func (c *Client) Execute(ctx context.Context, command string) (Message, error) {
+	ctx, cancel := context.WithTimeout(ctx, cmp.Or(c.Timeout, DefaultTimeout))
+	defer cancel()
+
+	// an idle connection may be dropped by the server unnoticed,
+	// so a failed request is sent once more over a new one
+	for attempt := 0; ; attempt++ {
+		conn, err := c.connection(ctx)
+		if err != nil {
+			return Message{}, err
+		}
+
+		id, reply := c.register()
+		err = c.write(ctx, conn, Message{Identifier: id, Message: command, Name: clientName})
+		if err == nil {
+			return c.wait(ctx, id, reply)
+		}
+		c.unregister(id)
+		c.drop(conn)
+		if attempt > 0 {
+			return Message{}, fmt.Errorf("rcon: sending %q: %w", command, err)
+		}
+	}
+}
+
+// Receive frames that aren't responses, like console output, until
+// the returned function is called. Frames are dropped while the channel
+// is full.
+func (c *Client) Subscribe() (frames <-chan Message, unsubscribe func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.subscribers == nil {
+		c.subscribers = make(map[int]chan Message)
+	}
+	c.lastSub++
+	id := c.lastSub
+	channel := make(chan Message, subscriberBuffer)
+	c.subscribers[id] = channel
+
+	return channel, func() {
+		c.mu.Lock()
+		delete(c.subscribers, id)
+		c.mu.Unlock()
+	}
+}
+
+// Close the connection, pending commands fail with ErrDisconnected
+func (c *Client) Close() error {
+	c.mu.Lock()
+	c.closed = true
+	conn := c.conn
+	c.mu.Unlock()
+
+	if conn == nil {
+		return nil
+	}
+	c.drop(conn)
+	return nil
+}
+
+// Return the open connection or open a new one. Dialing happens without
+// holding the lock, so a slow or unreachable server doesn't block Close
+// and Subscribe; concurrent commands wait for the same dial.
+func (c *Client) connection(ctx context.Context) (*websocket.Conn, error) {
+	for {
+		c.mu.Lock()
+		if c.closed {
+			c.mu.Unlock()
+			return nil, ErrClosed
+		}
+		if conn := c.conn; conn != nil {
+			c.mu.Unlock()
+			return conn, nil
+		}
+		dialing := c.dialing
+		if dialing == nil {
+			c.dialing = make(chan struct{})
+			c.mu.Unlock()
+			return c.dial(ctx)
+		}
+		c.mu.Unlock()
+
+		// another command is connecting, its connection is used once
+		// open and a new dial is made if it fails
+		select {
+		case <-dialing:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("rcon: connecting to %s: %w", c.Addr, ctx.Err())
+		}
+	}
+}
+
+// Open a new connection, called by the command that started dialing
+func (c *Client) dial(ctx context.Context) (conn *websocket.Conn, err error) {
+	defer func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		close(c.dialing)
+		c.dialing = nil
+		if err != nil {
+			return
+		}
+		// the client may be closed while dialing
+		if c.closed || c.conn != nil {
+			conn.Close()
+			conn, err = nil, ErrClosed
+			return
+		}
+		c.conn = conn
+		go c.read(conn)
+	}()
+
+	dialer := c.Dialer
+	if dialer == nil {
+		dialer = websocket.DefaultDialer
+	}
+	// the password is the path of the WebSocket URL
+	serverURL := url.URL{Scheme: "ws", Host: c.Addr, Path: "/" + c.Password}
+
+	for attempt := 1; attempt <= dialAttempts; attempt++ {
+		var resp *http.Response
+		conn, resp, err = dialer.DialContext(ctx, serverURL.String(), nil)
+		if err == nil {
+			conn.SetReadLimit(maxFrameSize)
+			return conn, nil
+		}
+		if resp != nil && (resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden) {
+			return nil, ErrUnauthorized
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("rcon: connecting to %s: %w", c.Addr, err)
+		case <-time.After(redialDelay * time.Duration(attempt)):
+		}
+	}
+
+	return nil, fmt.Errorf("rcon: connecting to %s: %w", c.Addr, err)
+}
+
+// Reserve an identifier for a command and a channel for its response
+func (c *Client) register() (id int, reply chan Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// identifiers are positive 32-bit integers on the server side
+	if c.lastID >= math.MaxInt32 {
+		c.lastID = 0
+	}
+	c.lastID++
+	if c.pending == nil {
+		c.pending = make(map[int]chan Message)
+	}
+	reply = make(chan Message, 1)
+	c.pending[c.lastID] = reply
+
+	return c.lastID, reply
+}
+
+// Forget the command that won't be waited for
+func (c *Client) unregister(id int) {
+	c.mu.Lock()
+	delete(c.pending, id)
+	c.mu.Unlock()
+}
+
+// Send a frame with the command deadline
+func (c *Client) write(ctx context.Context, conn *websocket.Conn, request Message) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetWriteDeadline(deadline); err != nil {
+		return err
+	}
+
+	return conn.WriteJSON(request)
+}
+
+// Wait for the response of the command
+func (c *Client) wait(ctx context.Context, id int, reply chan Message) (Message, error) {
+	defer c.unregister(id)
+
+	select {
+	case response, ok := <-reply:
+		if !ok {
+			return Message{}, ErrDisconnected
+		}
+		return response, nil
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return Message{}, ErrTimeout
+		}
+		return Message{}, ctx.Err()
+	}
+}
+
+// Read frames until the connection closes, passing responses to their
+// commands and other frames to OnMessage
+func (c *Client) read(conn *websocket.Conn) {
+	defer c.drop(conn)
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var message Message
+		// frames that aren't WebRCON ones are skipped
+		if err = json.Unmarshal(data, &message); err != nil {
+			continue
+		}
+
+		c.mu.Lock()
+		reply, ok := c.pending[message.Identifier]
+		delete(c.pending, message.Identifier)
+		if !ok {
+			for _, subscriber := range c.subscribers {
+				select {
+				case subscriber <- message:
+				default:
+				}
+			}
+		}
+		c.mu.Unlock()
+
+		switch {
+		case ok:
+			reply <- message
+		case c.OnMessage != nil:
+			c.OnMessage(message)
+		}
+	}
+}
+
+// Close the connection and fail commands waiting for responses over it
+func (c *Client) drop(conn *websocket.Conn) {
+	c.mu.Lock()
+	if c.conn == conn {
+		c.conn = nil
+		for id, reply := range c.pending {
+			close(reply)
+			delete(c.pending, id)
+		}
+	}
+	c.mu.Unlock()
+
+	conn.Close()
+}
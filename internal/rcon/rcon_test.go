@@ -0,0 +1,262 @@
+package rcon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Stand-in for a Rust server answering WebRCON commands:
+//
+// • "slow" is answered after other commands
+//
+// • "silent" isn't answered at all
+//
+// • "drop" closes the connection
+//
+// Every other command is answered with its echo after a console broadcast.
+type standIn struct {
+	*httptest.Server
+	password string
+	// number of accepted connections
+	connections atomic.Int32
+}
+
+func newStandIn(t *testing.T, password string) *standIn {
+	t.Helper()
+
+	s := &standIn{password: password}
+	upgrader := websocket.Upgrader{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.TrimPrefix(r.URL.Path, "/") != s.password {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		s.connections.Add(1)
+
+		var writeMu sync.Mutex
+		send := func(message Message) {
+			writeMu.Lock()
+			defer writeMu.Unlock()
+			_ = conn.WriteJSON(message)
+		}
+		for {
+			var request Message
+			if err := conn.ReadJSON(&request); err != nil {
+				return
+			}
+			switch request.Message {
+			case "silent":
+			case "drop":
+				return
+			case "slow":
+				go func() {
+					time.Sleep(100 * time.Millisecond)
+					send(Message{Identifier: request.Identifier, Message: "slow done", Type: "Generic"})
+				}()
+			default:
+				send(Message{Identifier: -1, Message: "[Console] " + request.Message, Type: "Generic"})
+				send(Message{Identifier: request.Identifier, Message: "ran " + request.Message, Type: "Generic"})
+			}
+		}
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// Client of the stand-in with short timeouts
+func (s *standIn) client(password string) *Client {
+	return &Client{
+		Addr:     strings.TrimPrefix(s.URL, "http://"),
+		Password: password,
+		Timeout:  time.Second,
+	}
+}
+
+func TestExecute(t *testing.T) {
+	server := newStandIn(t, "secret")
+	client := server.client("secret")
+	defer client.Close()
+
+	var broadcasts atomic.Int32
+	client.OnMessage = func(message Message) {
+		if message.Identifier == -1 {
+			broadcasts.Add(1)
+		}
+	}
+	frames, unsubscribe := client.Subscribe()
+
+	response, err := client.Execute(context.Background(), "oxide.reload Kits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Message != "ran oxide.reload Kits" || response.Type != "Generic" {
+		t.Errorf("Execute() = %+v", response)
+	}
+	// the broadcast comes before the response
+	select {
+	case frame := <-frames:
+		if frame.Message != "[Console] oxide.reload Kits" {
+			t.Errorf("subscribed frame = %+v, want the console broadcast", frame)
+		}
+	default:
+		t.Error("no frame received by the subscriber")
+	}
+	unsubscribe()
+
+	// responses reach their commands whatever order they come in
+	var wg sync.WaitGroup
+	results := make([]string, 5)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			command := fmt.Sprintf("say %d", i)
+			if i == 0 {
+				command = "slow"
+			}
+			response, err := client.Execute(context.Background(), command)
+			if err != nil {
+				results[i] = err.Error()
+				return
+			}
+			results[i] = response.Message
+		}()
+	}
+	wg.Wait()
+	for i, result := range results {
+		expected := fmt.Sprintf("ran say %d", i)
+		if i == 0 {
+			expected = "slow done"
+		}
+		if result != expected {
+			t.Errorf("command %d response = %q, want %q", i, result, expected)
+		}
+	}
+
+	if server.connections.Load() != 1 {
+		t.Errorf("connections = %d, want commands sent over one", server.connections.Load())
+	}
+	if broadcasts.Load() != 5 {
+		t.Errorf("broadcasts = %d, want 5", broadcasts.Load())
+	}
+}
+
+func TestExecuteFailures(t *testing.T) {
+	server := newStandIn(t, "secret")
+
+	t.Run("wrong password", func(t *testing.T) {
+		client := server.client("guess")
+		defer client.Close()
+		if _, err := client.Execute(context.Background(), "status"); !errors.Is(err, ErrUnauthorized) {
+			t.Errorf("Execute() error = %v, want ErrUnauthorized", err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		client := server.client("secret")
+		client.Timeout = 50 * time.Millisecond
+		defer client.Close()
+		if _, err := client.Execute(context.Background(), "silent"); !errors.Is(err, ErrTimeout) {
+			t.Errorf("Execute() error = %v, want ErrTimeout", err)
+		}
+	})
+
+	t.Run("reconnect", func(t *testing.T) {
+		client := server.client("secret")
+		defer client.Close()
+		connections := server.connections.Load()
+
+		if _, err := client.Execute(context.Background(), "drop"); !errors.Is(err, ErrDisconnected) {
+			t.Errorf("Execute() error = %v, want ErrDisconnected", err)
+		}
+		// the next command opens a new connection
+		response, err := client.Execute(context.Background(), "status")
+		if err != nil || response.Message != "ran status" {
+			t.Fatalf("Execute() after reconnect = %+v, %v", response, err)
+		}
+		if opened := server.connections.Load() - connections; opened != 2 {
+			t.Errorf("opened connections = %d, want 2", opened)
+		}
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		client := New("127.0.0.1", 1, "secret")
+		client.Timeout = 200 * time.Millisecond
+		if _, err := client.Execute(context.Background(), "status"); err == nil {
+			t.Error("Execute() of unreachable server succeeded, want error")
+		}
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		client := server.client("secret")
+		if _, err := client.Execute(context.Background(), "status"); err != nil {
+			t.Fatal(err)
+		}
+		client.Close()
+		if _, err := client.Execute(context.Background(), "status"); !errors.Is(err, ErrClosed) {
+			t.Errorf("Execute() error = %v, want ErrClosed", err)
+		}
+	})
+}
+
+func TestCloseWhileDialing(t *testing.T) {
+	server := newStandIn(t, "secret")
+	client := server.client("secret")
+
+	// the dial hangs until released
+	dialStarted, release := make(chan struct{}), make(chan struct{})
+	client.Dialer = &websocket.Dialer{NetDialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+		close(dialStarted)
+		<-release
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, network, addr)
+	}}
+	result := make(chan error, 1)
+	go func() {
+		_, err := client.Execute(context.Background(), "status")
+		result <- err
+	}()
+	<-dialStarted
+
+	// the client isn't locked by the dial
+	done := make(chan struct{})
+	go func() {
+		_, unsubscribe := client.Subscribe()
+		unsubscribe()
+		client.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Subscribe() and Close() blocked by the dial")
+	}
+
+	// the connection opened after closing isn't used
+	close(release)
+	if err := <-result; !errors.Is(err, ErrClosed) {
+		t.Errorf("Execute() error = %v, want ErrClosed", err)
+	}
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if client.conn != nil {
+		t.Error("connection opened after Close() is kept")
+	}
+}
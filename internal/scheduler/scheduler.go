@@ -0,0 +1,275 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"adminrust/internal/database"
+)
+
+// Job run statuses stored in job_runs
+const (
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// What started a job run
+const (
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+)
+
+var (
+	ErrUnknownJob     = errors.New("unknown job")
+	ErrAlreadyRunning = errors.New("job is already running")
+)
+
+// Work done by a job. The context is cancelled when the scheduler stops.
+type Task func(ctx context.Context) error
+
+// A named task with its default cron-like schedule, e.g. "@daily"
+// or "0 */6 * * *". The schedule stored in DB takes precedence.
+type Job struct {
+	Name     string
+	Schedule string
+	Task     Task
+}
+
+// A job registered in the scheduler
+type entry struct {
+	job     Job
+	jobID   int64
+	cronID  cron.EntryID
+	running bool
+}
+
+// Scheduler runs registered jobs on their schedules and records
+// every run to job_runs
+type Scheduler struct {
+	cron    *cron.Cron
+	queries *database.Queries
+
+	// cancelled on stop to interrupt running tasks
+	ctx    context.Context
+	cancel context.CancelFunc
+	// running tasks
+	wg sync.WaitGroup
+
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+// Create a scheduler that stores jobs and their runs using queries
+func New(queries *database.Queries) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		cron:    cron.New(),
+		queries: queries,
+		ctx:     ctx,
+		cancel:  cancel,
+		entries: map[string]*entry{},
+	}
+}
+
+// Check if the schedule is a valid cron expression or descriptor
+func ValidateSchedule(schedule string) error {
+	_, err := cron.ParseStandard(schedule)
+	return err
+}
+
+// Save the job to DB if it's new and schedule it
+func (s *Scheduler) Register(ctx context.Context, job Job) error {
+	// jobs with broken default schedules aren't stored
+	if err := ValidateSchedule(job.Schedule); err != nil {
+		return fmt.Errorf("invalid schedule of job %s: %w", job.Name, err)
+	}
+	stored, err := s.queries.EnsureJob(ctx, database.EnsureJobParams{
+		Name:     job.Name,
+		Schedule: job.Schedule,
+	})
+	if err != nil {
+		return err
+	}
+	job.Schedule = stored.Schedule
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobEntry := &entry{job: job, jobID: stored.ID}
+	jobEntry.cronID, err = s.cron.AddFunc(job.Schedule, func() {
+		if err := s.start(job.Name, TriggerSchedule); err != nil {
+			log.Printf("Job %s skipped: %s\n", job.Name, err)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("invalid schedule of job %s: %w", job.Name, err)
+	}
+	s.entries[job.Name] = jobEntry
+
+	return nil
+}
+
+// Start running jobs on their schedules. Runs that were left unfinished by
+// a previous process are marked as failed.
+func (s *Scheduler) Start() {
+	if err := s.queries.FailUnfinishedJobRuns(s.ctx, "interrupted by shutdown"); err != nil {
+		log.Printf("Error closing unfinished job runs: %s\n", err)
+	}
+	s.cron.Start()
+}
+
+// Stop scheduling new runs, interrupt running ones, and wait for them
+// to finish until the context is done
+func (s *Scheduler) Stop(ctx context.Context) error {
+	<-s.cron.Stop().Done()
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Start the job in background right away
+func (s *Scheduler) RunNow(name string) error {
+	return s.start(name, TriggerManual)
+}
+
+// Replace the job schedule in DB and in the running scheduler
+func (s *Scheduler) Reschedule(ctx context.Context, name, schedule string) error {
+	if err := ValidateSchedule(schedule); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobEntry, exists := s.entries[name]
+	if !exists {
+		return ErrUnknownJob
+	}
+
+	_, err := s.queries.UpdateJobSchedule(ctx, database.UpdateJobScheduleParams{
+		Schedule: schedule,
+		Name:     name,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.cron.Remove(jobEntry.cronID)
+	jobEntry.job.Schedule = schedule
+	jobEntry.cronID, err = s.cron.AddFunc(schedule, func() {
+		if err := s.start(name, TriggerSchedule); err != nil {
+			log.Printf("Job %s skipped: %s\n", name, err)
+		}
+	})
+
+	return err
+}
+
+// Return the time of the next scheduled run or zero time if unknown
+func (s *Scheduler) NextRun(name string) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobEntry, exists := s.entries[name]
+	if !exists {
+		return time.Time{}
+	}
+
+	return s.cron.Entry(jobEntry.cronID).Next
+}
+
+// Check if the job is running at the moment
+func (s *Scheduler) IsRunning(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobEntry, exists := s.entries[name]
+	return exists && jobEntry.running
+}
+
+// Record a new run and execute the job in background unless it's
+// already running
+func (s *Scheduler) start(name, trigger string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobEntry, exists := s.entries[name]
+	if !exists {
+		return ErrUnknownJob
+	}
+	if jobEntry.running {
+		return ErrAlreadyRunning
+	}
+	if s.ctx.Err() != nil {
+		return s.ctx.Err()
+	}
+
+	run, err := s.queries.AddJobRun(s.ctx, database.AddJobRunParams{
+		JobID:   jobEntry.jobID,
+		Trigger: trigger,
+	})
+	if err != nil {
+		return err
+	}
+
+	jobEntry.running = true
+	s.wg.Add(1)
+	go s.execute(jobEntry, run.ID)
+
+	return nil
+}
+
+// Run the job task and record its result
+func (s *Scheduler) execute(jobEntry *entry, runID int64) {
+	defer s.wg.Done()
+
+	err := runTask(s.ctx, jobEntry.job.Task)
+
+	params := database.FinishJobRunParams{
+		Status: StatusSucceeded,
+		ID:     runID,
+	}
+	if err != nil {
+		log.Printf("Job %s failed: %s\n", jobEntry.job.Name, err)
+		params.Status = StatusFailed
+		params.Error = err.Error()
+	}
+	// the scheduler context may be cancelled already, but the result
+	// still has to be saved
+	if err = s.queries.FinishJobRun(context.Background(), params); err != nil {
+		log.Printf("Error saving run of job %s: %s\n", jobEntry.job.Name, err)
+	}
+
+	s.mu.Lock()
+	jobEntry.running = false
+	s.mu.Unlock()
+}
+
+// Run the task converting a panic into an error
+func runTask(ctx context.Context, task Task) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+
+	return task(ctx)
+}
@@ -0,0 +1,223 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"adminrust/internal/database"
+	"adminrust/internal/database/dbtest"
+)
+
+// Create queries of an in-memory database with all "Up" migrations applied
+func newTestQueries(t *testing.T) *database.Queries {
+	t.Helper()
+
+	return database.New(dbtest.Open(t))
+}
+
+// Stop the scheduler failing the test if it takes longer than a second
+func stopScheduler(t *testing.T, s *Scheduler) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := s.Stop(ctx); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	ctx := context.Background()
+	queries := newTestQueries(t)
+	noop := func(ctx context.Context) error { return nil }
+
+	s := New(queries)
+	if err := s.Register(ctx, Job{Name: "sync", Schedule: "@daily", Task: noop}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := s.Register(ctx, Job{Name: "broken", Schedule: "every day", Task: noop}); err == nil {
+		t.Error("Register() with invalid schedule error = nil")
+	}
+	s.Start()
+	defer stopScheduler(t, s)
+	if s.NextRun("sync").IsZero() {
+		t.Error("NextRun() of registered job is zero")
+	}
+	if err := s.Reschedule(ctx, "sync", "@hourly"); err != nil {
+		t.Fatalf("Reschedule() error = %v", err)
+	}
+	if err := s.Reschedule(ctx, "missing", "@hourly"); !errors.Is(err, ErrUnknownJob) {
+		t.Errorf("Reschedule() of unknown job error = %v, want %v", err, ErrUnknownJob)
+	}
+
+	// the schedule stored in DB takes precedence over the default one
+	restarted := New(queries)
+	if err := restarted.Register(ctx, Job{Name: "sync", Schedule: "@daily", Task: noop}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	restarted.Start()
+	defer stopScheduler(t, restarted)
+	jobs, err := queries.GetJobs(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 1 || jobs[0].Schedule != "@hourly" {
+		t.Errorf("stored jobs = %+v, want sync with @hourly", jobs)
+	}
+	if next := restarted.NextRun("sync"); next.IsZero() || next.After(time.Now().Add(time.Hour)) {
+		t.Errorf("NextRun() = %s, want within an hour", next)
+	}
+}
+
+func TestRunNow(t *testing.T) {
+	ctx := context.Background()
+	queries := newTestQueries(t)
+	s := New(queries)
+
+	release := make(chan struct{})
+	jobs := []Job{
+		{Name: "blocking", Schedule: "@daily", Task: func(ctx context.Context) error {
+			<-release
+			return nil
+		}},
+		{Name: "failing", Schedule: "@every 1h", Task: func(ctx context.Context) error {
+			return errors.New("origin is down")
+		}},
+		{Name: "panicking", Schedule: "@every 1h", Task: func(ctx context.Context) error {
+			panic("nil map")
+		}},
+	}
+	for _, job := range jobs {
+		if err := s.Register(ctx, job); err != nil {
+			t.Fatalf("Register(%s) error = %v", job.Name, err)
+		}
+	}
+	s.Start()
+
+	if err := s.RunNow("blocking"); err != nil {
+		t.Fatalf("RunNow() error = %v", err)
+	}
+	if !s.IsRunning("blocking") {
+		t.Error("IsRunning() of started job = false")
+	}
+	if err := s.RunNow("blocking"); !errors.Is(err, ErrAlreadyRunning) {
+		t.Errorf("RunNow() on running job error = %v, want %v", err, ErrAlreadyRunning)
+	}
+	if err := s.RunNow("missing"); !errors.Is(err, ErrUnknownJob) {
+		t.Errorf("RunNow() on unknown job error = %v, want %v", err, ErrUnknownJob)
+	}
+	for _, name := range []string{"failing", "panicking"} {
+		if err := s.RunNow(name); err != nil {
+			t.Fatalf("RunNow(%s) error = %v", name, err)
+		}
+	}
+	close(release)
+	stopScheduler(t, s)
+
+	runs, err := queries.GetRecentJobRuns(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedStatuses := map[string]string{"blocking": StatusSucceeded, "failing": StatusFailed, "panicking": StatusFailed}
+	if len(runs) != len(expectedStatuses) {
+		t.Fatalf("recorded runs = %+v, want %d", runs, len(expectedStatuses))
+	}
+	for _, run := range runs {
+		if run.Status != expectedStatuses[run.JobName] || run.Trigger != TriggerManual || run.FinishedAt == "" {
+			t.Errorf("run of %s = %+v, want finished manual run with status %s", run.JobName, run, expectedStatuses[run.JobName])
+		}
+		if run.JobName == "panicking" && !strings.Contains(run.Error, "nil map") {
+			t.Errorf("run of panicking job error = %q, want the panic", run.Error)
+		}
+	}
+}
+
+func TestStop(t *testing.T) {
+	ctx := context.Background()
+	queries := newTestQueries(t)
+	s := New(queries)
+
+	started := make(chan struct{})
+	err := s.Register(ctx, Job{Name: "sync", Schedule: "@daily", Task: func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Start()
+	if err = s.RunNow("sync"); err != nil {
+		t.Fatalf("RunNow() error = %v", err)
+	}
+	<-started
+
+	// running tasks are interrupted and waited for
+	stopScheduler(t, s)
+	if s.IsRunning("sync") {
+		t.Error("IsRunning() after Stop() = true")
+	}
+	runs, err := queries.GetRecentJobRuns(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 || runs[0].Status != StatusFailed || runs[0].Error != context.Canceled.Error() {
+		t.Errorf("interrupted run = %+v, want failed with %v", runs, context.Canceled)
+	}
+	if err = s.RunNow("sync"); !errors.Is(err, context.Canceled) {
+		t.Errorf("RunNow() after Stop() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestStopTimeout(t *testing.T) {
+	ctx := context.Background()
+	s := New(newTestQueries(t))
+
+	release := make(chan struct{})
+	defer close(release)
+	err := s.Register(ctx, Job{Name: "stubborn", Schedule: "@daily", Task: func(ctx context.Context) error {
+		// ignores the cancellation
+		<-release
+		return nil
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Start()
+	if err = s.RunNow("stubborn"); err != nil {
+		t.Fatalf("RunNow() error = %v", err)
+	}
+
+	stopCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err = s.Stop(stopCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Stop() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestValidateSchedule(t *testing.T) {
+	tests := []struct {
+		schedule string
+		isValid  bool
+	}{
+		{"@daily", true},
+		{"@every 30m", true},
+		{"0 */6 * * *", true},
+		{"*/5 * * * *", true},
+		{"every day", false},
+		{"61 * * * *", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.schedule, func(t *testing.T) {
+			err := ValidateSchedule(test.schedule)
+			if (err == nil) != test.isValid {
+				t.Errorf("ValidateSchedule(%q) error = %v, want valid %v", test.schedule, err, test.isValid)
+			}
+		})
+	}
+}
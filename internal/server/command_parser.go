@@ -0,0 +1,278 @@
+package server
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"adminrust/internal/database"
+)
+
+// Command types stored in plugin_commands.cmd_type
+const (
+	cmdTypeChat    = "chat"
+	cmdTypeConsole = "console"
+	cmdTypeBoth    = "both"
+)
+
+// Command types offered in forms
+var cmdTypes = []string{cmdTypeChat, cmdTypeConsole, cmdTypeBoth}
+
+var (
+	// list markers preceding a command, e.g. "- ", "* " or "1. "
+	listMarkerPattern = regexp.MustCompile(`^(?:[-*•]|\d+[.)])\s+`)
+	// permission mentioned in a description, e.g. "(permission: kits.admin)"
+	permissionPattern = regexp.MustCompile(`(?i)\(?\b(?:permission|perm)\s*:\s*([\w.-]+)\)?`)
+	// markdown table separator cell, e.g. "---" or ":---:"
+	tableSeparatorPattern = regexp.MustCompile(`^:?-+:?$`)
+)
+
+// Split input on rows and parse each row into a command keeping the order
+// the commands were entered in. Rows like "/kit <name> - description" and
+// rows of markdown tables are recognised.
+func parseCommands(rawCommands, descrSep, cmdSep string) (commands []database.AddPluginCommandsParams, err error) {
+	rows := strings.Split(strings.ReplaceAll(rawCommands, "\r\n", "\n"), cmdSep)
+
+	// columns of the current markdown table, nil outside of a table
+	var columns *tableColumns
+	for i, row := range rows {
+		row = strings.TrimSpace(row)
+
+		if !isTableRow(row) {
+			columns = nil
+			if command, ok := parseCommandRow(row, descrSep); ok {
+				commands = append(commands, command)
+			}
+			continue
+		}
+
+		cells := tableCells(row)
+		if isTableSeparator(cells) {
+			continue
+		}
+		// a header is the row followed by a separator
+		if i+1 < len(rows) {
+			next := strings.TrimSpace(rows[i+1])
+			if isTableRow(next) && isTableSeparator(tableCells(next)) {
+				columns = newTableColumns(cells)
+				continue
+			}
+		}
+		if columns == nil {
+			columns = defaultTableColumns(len(cells))
+		}
+		if command, ok := columns.parseRow(cells); ok {
+			commands = append(commands, command)
+		}
+	}
+
+	if len(commands) == 0 {
+		return nil, fmt.Errorf("no commands found using separators <%s> and <%s>", descrSep, cmdSep)
+	}
+	for i := range commands {
+		commands[i].Position = int64(i)
+	}
+
+	return commands, nil
+}
+
+// Parse a row like "/kit, /kits <name> - Claim a kit (permission: kits.use)"
+func parseCommandRow(row, descrSep string) (command database.AddPluginCommandsParams, ok bool) {
+	row = listMarkerPattern.ReplaceAllString(row, "")
+
+	// prefer a separator surrounded by spaces, so hyphenated commands
+	// like "kit.reset-all" stay whole
+	syntax, descr, found := strings.Cut(row, " "+descrSep+" ")
+	if !found {
+		syntax, descr, found = strings.Cut(row, descrSep)
+	}
+	// a chat command without description is still recognisable
+	if !found && !strings.HasPrefix(row, "/") {
+		return command, false
+	}
+
+	command.Command, command.Aliases, command.Args = parseCommandSyntax(syntax)
+	if command.Command == "" {
+		return command, false
+	}
+	command.Description, command.Permission = extractPermission(strings.TrimSpace(descr))
+	command.CmdType = guessCommandType(command.Command)
+
+	return command, true
+}
+
+// Split command syntax like "/kit, /kits <name> [amount]" on the command
+// name, its comma-separated aliases, and the argument syntax
+func parseCommandSyntax(syntax string) (name, aliases, args string) {
+	syntax = strings.ReplaceAll(syntax, "`", "")
+
+	var names []string
+	tokens := strings.Fields(syntax)
+	i := 0
+	for i < len(tokens) {
+		token := tokens[i]
+		for _, n := range strings.Split(token, ",") {
+			if n != "" {
+				names = append(names, n)
+			}
+		}
+		i++
+		// next token is an alias if names are separated by commas or "or"
+		if i < len(tokens) && (tokens[i] == "or" || tokens[i] == "|") {
+			i++
+			continue
+		}
+		if !strings.HasSuffix(token, ",") {
+			break
+		}
+	}
+	if len(names) == 0 {
+		return "", "", ""
+	}
+
+	return names[0], strings.Join(names[1:], ", "), strings.Join(tokens[i:], " ")
+}
+
+// Remove a permission mention from the description and return it separately
+func extractPermission(descr string) (cleanDescr, permission string) {
+	match := permissionPattern.FindStringSubmatchIndex(descr)
+	if match == nil {
+		return descr, ""
+	}
+
+	permission = descr[match[2]:match[3]]
+	cleanDescr = strings.TrimSpace(descr[:match[0]] + descr[match[1]:])
+	cleanDescr = strings.Join(strings.Fields(cleanDescr), " ")
+
+	return cleanDescr, permission
+}
+
+// Chat commands are typed with a leading slash
+func guessCommandType(name string) string {
+	if strings.HasPrefix(name, "/") {
+		return cmdTypeChat
+	}
+
+	return cmdTypeConsole
+}
+
+// Convert a type from a command list to one of the stored types
+func normalizeCommandType(rawType, name string) string {
+	rawType = strings.ToLower(rawType)
+	isChat := strings.Contains(rawType, cmdTypeChat)
+	isConsole := strings.Contains(rawType, cmdTypeConsole)
+	switch {
+	case rawType == cmdTypeBoth || (isChat && isConsole):
+		return cmdTypeBoth
+	case isChat:
+		return cmdTypeChat
+	case isConsole:
+		return cmdTypeConsole
+	}
+
+	return guessCommandType(name)
+}
+
+// Check if the row belongs to a markdown table
+func isTableRow(row string) bool {
+	return strings.HasPrefix(row, "|")
+}
+
+// Split a markdown table row on trimmed cells
+func tableCells(row string) (cells []string) {
+	row = strings.TrimSuffix(strings.TrimPrefix(row, "|"), "|")
+	for _, cell := range strings.Split(row, "|") {
+		cells = append(cells, strings.TrimSpace(cell))
+	}
+
+	return cells
+}
+
+// Check if the row is a markdown table separator like |---|:---:|
+func isTableSeparator(cells []string) bool {
+	for _, cell := range cells {
+		if !tableSeparatorPattern.MatchString(cell) {
+			return false
+		}
+	}
+
+	return len(cells) > 0
+}
+
+// Indexes of known markdown table columns, -1 if the table has no such column
+type tableColumns struct {
+	command, cmdType, aliases, permission, description int
+}
+
+// Find known columns by header names
+func newTableColumns(header []string) *tableColumns {
+	columns := &tableColumns{-1, -1, -1, -1, -1}
+	for i, name := range header {
+		name = strings.ToLower(name)
+		switch {
+		case strings.Contains(name, "alias"):
+			columns.aliases = i
+		case strings.Contains(name, "perm"):
+			columns.permission = i
+		case strings.Contains(name, "type"):
+			columns.cmdType = i
+		case strings.Contains(name, "descr"):
+			columns.description = i
+		case columns.command == -1 && (strings.Contains(name, "command") ||
+			strings.Contains(name, "syntax") || strings.Contains(name, "usage")):
+			columns.command = i
+		}
+	}
+	// tables without a recognised command column list commands first
+	if columns.command == -1 {
+		columns.command = 0
+	}
+
+	return columns
+}
+
+// Columns of a table without header: a command first and a description last
+func defaultTableColumns(cellCount int) *tableColumns {
+	columns := &tableColumns{0, -1, -1, -1, -1}
+	if cellCount > 1 {
+		columns.description = cellCount - 1
+	}
+
+	return columns
+}
+
+// Convert table row cells to a command
+func (c *tableColumns) parseRow(cells []string) (command database.AddPluginCommandsParams, ok bool) {
+	cell := func(i int) string {
+		if i < 0 || i >= len(cells) {
+			return ""
+		}
+		return cells[i]
+	}
+
+	var aliases string
+	command.Command, aliases, command.Args = parseCommandSyntax(cell(c.command))
+	if command.Command == "" {
+		return command, false
+	}
+
+	command.Aliases = strings.Join(nonEmpty(aliases, strings.ReplaceAll(cell(c.aliases), "`", "")), ", ")
+	command.Description, command.Permission = extractPermission(cell(c.description))
+	if permission := strings.Trim(cell(c.permission), "` "); permission != "" {
+		command.Permission = permission
+	}
+	command.CmdType = normalizeCommandType(cell(c.cmdType), command.Command)
+
+	return command, true
+}
+
+// Return non-empty strings of the given ones
+func nonEmpty(values ...string) (result []string) {
+	for _, value := range values {
+		if value != "" {
+			result = append(result, value)
+		}
+	}
+
+	return result
+}
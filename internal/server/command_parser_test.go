@@ -0,0 +1,100 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+
+	"adminrust/internal/database"
+)
+
+func TestParseCommands(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		descrSep       string
+		expectedOutput []database.AddPluginCommandsParams
+		isValid        bool
+	}{
+		{
+			name:     "chat and console rows",
+			input:    "/kit <name> - Claim a kit\nkit.reset - Reset kit data",
+			descrSep: "-",
+			expectedOutput: []database.AddPluginCommandsParams{
+				{Command: "/kit", CmdType: cmdTypeChat, Args: "<name>", Description: "Claim a kit", Position: 0},
+				{Command: "kit.reset", CmdType: cmdTypeConsole, Description: "Reset kit data", Position: 1},
+			},
+			isValid: true,
+		},
+		{
+			name:     "order and duplicates kept",
+			input:    "/tp <player> - Teleport to player\n/tp <x> <y> <z> - Teleport to position\n/home - Go home",
+			descrSep: "-",
+			expectedOutput: []database.AddPluginCommandsParams{
+				{Command: "/tp", CmdType: cmdTypeChat, Args: "<player>", Description: "Teleport to player", Position: 0},
+				{Command: "/tp", CmdType: cmdTypeChat, Args: "<x> <y> <z>", Description: "Teleport to position", Position: 1},
+				{Command: "/home", CmdType: cmdTypeChat, Description: "Go home", Position: 2},
+			},
+			isValid: true,
+		},
+		{
+			name:     "list markers, aliases, hyphens and permission",
+			input:    "- `/kit, /kits [name]` - Claim a kit (permission: kits.use)\n* /remover-tool or /rt - Toggle remover\n\nnot a command",
+			descrSep: "-",
+			expectedOutput: []database.AddPluginCommandsParams{
+				{Command: "/kit", CmdType: cmdTypeChat, Aliases: "/kits", Args: "[name]", Permission: "kits.use", Description: "Claim a kit", Position: 0},
+				{Command: "/remover-tool", CmdType: cmdTypeChat, Aliases: "/rt", Description: "Toggle remover", Position: 1},
+			},
+			isValid: true,
+		},
+		{
+			name:     "custom separator",
+			input:    "/kit <name> : Claim a kit",
+			descrSep: ":",
+			expectedOutput: []database.AddPluginCommandsParams{
+				{Command: "/kit", CmdType: cmdTypeChat, Args: "<name>", Description: "Claim a kit", Position: 0},
+			},
+			isValid: true,
+		},
+		{
+			name: "markdown table with header",
+			input: "| Command | Type | Permission | Description |\n" +
+				"|:--------|------|------------|-------------|\n" +
+				"| `/kit <name>` | Chat | kits.use | Claim a kit |\n" +
+				"| `kit.give <player> <name>` | Chat & Console | kits.admin | Give a kit |",
+			descrSep: "-",
+			expectedOutput: []database.AddPluginCommandsParams{
+				{Command: "/kit", CmdType: cmdTypeChat, Args: "<name>", Permission: "kits.use", Description: "Claim a kit", Position: 0},
+				{Command: "kit.give", CmdType: cmdTypeBoth, Args: "<player> <name>", Permission: "kits.admin", Description: "Give a kit", Position: 1},
+			},
+			isValid: true,
+		},
+		{
+			name:     "markdown table without header",
+			input:    "| /kit | Claim a kit |\n| kit.reset | Reset kits |",
+			descrSep: "-",
+			expectedOutput: []database.AddPluginCommandsParams{
+				{Command: "/kit", CmdType: cmdTypeChat, Description: "Claim a kit", Position: 0},
+				{Command: "kit.reset", CmdType: cmdTypeConsole, Description: "Reset kits", Position: 1},
+			},
+			isValid: true,
+		},
+		{
+			name:     "nothing to parse",
+			input:    "Some text about the plugin",
+			descrSep: "-",
+			isValid:  false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			commands, err := parseCommands(test.input, test.descrSep, "\n")
+			if (err == nil) != test.isValid {
+				t.Fatalf("parseCommands() error = %v, want valid %v", err, test.isValid)
+			}
+			if !reflect.DeepEqual(commands, test.expectedOutput) {
+				t.Errorf("parseCommands() commands = %+v, want %+v", commands, test.expectedOutput)
+			}
+		})
+	}
+}
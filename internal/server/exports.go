@@ -0,0 +1,187 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"adminrust/internal/database"
+	"adminrust/internal/jsondoc"
+	"adminrust/internal/oxide"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Export-related routes
+func (s *Server) registerExportRoutes(r *chi.Mux) {
+	r.Route("/export", func(r chi.Router) {
+		r.Get("/", s.exportForm)
+		r.Get("/download", s.exportOxideFiles)
+	})
+}
+
+// Stored JSON that isn't valid and can't be exported
+type invalidExportError struct {
+	path string
+	err  error
+}
+
+func (e *invalidExportError) Error() string {
+	return fmt.Sprintf("%s: %v", e.path, e.err)
+}
+
+// Render form for choosing plugins to export
+func (s *Server) exportForm(w http.ResponseWriter, r *http.Request) {
+	plugins, err := s.db.Queries().GetExportPlugins(r.Context())
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// plugins chosen beforehand, e.g. from the plugin page
+	selected := make(map[string]bool)
+	for _, slug := range r.URL.Query()["plugin"] {
+		selected[slug] = true
+	}
+	metaData := struct{ Selected map[string]bool }{selected}
+
+	renderPage(w, "export", "Export", plugins, metaData)
+}
+
+// Send configs and locales of the chosen plugins, or of every plugin
+// if none is chosen, as an archive of the Oxide directory tree
+func (s *Server) exportOxideFiles(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = oxide.Zip
+	}
+	if format != oxide.Zip && format != oxide.TarGz {
+		log.Println("unsupported archive format:", format)
+		badRequest(w)
+		return
+	}
+
+	plugins, err := s.db.Queries().GetExportPlugins(r.Context())
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+	selected := slices.Compact(slices.Sorted(slices.Values(r.URL.Query()["plugin"])))
+	if len(selected) > 0 {
+		plugins = slices.DeleteFunc(plugins, func(plugin database.GetExportPluginsRow) bool {
+			return !slices.Contains(selected, plugin.Slug)
+		})
+		// every chosen plugin must exist
+		if len(plugins) != len(selected) {
+			notFound(w, r)
+			return
+		}
+	}
+
+	files, err := s.oxideFiles(r.Context(), plugins)
+	var invalid *invalidExportError
+	if errors.As(err, &invalid) {
+		log.Println(err)
+		errorHandler(w, http.StatusUnprocessableEntity, fmt.Sprintf("Stored JSON for %s isn't valid", invalid.path))
+		return
+	}
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+	if len(files) == 0 {
+		errorHandler(w, http.StatusNotFound, "No configs or locales to export")
+		return
+	}
+
+	// the archive is built first to answer with an error page on failure
+	var buf bytes.Buffer
+	if err = oxide.WriteArchive(&buf, format, files); err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	fileName := "oxide." + format
+	if len(plugins) == 1 {
+		fileName = fmt.Sprintf("oxide-%s.%s", plugins[0].Slug, format)
+	}
+	w.Header().Set("Content-Type", oxide.ContentType(format))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	if _, err = w.Write(buf.Bytes()); err != nil {
+		log.Println(err)
+	}
+}
+
+// Collect config and locale files of the plugins formatted as Oxide writes them
+func (s *Server) oxideFiles(ctx context.Context, plugins []database.GetExportPluginsRow) (files []oxide.File, err error) {
+	names := make(map[int64]string, len(plugins))
+	for _, plugin := range plugins {
+		names[plugin.ID] = oxide.PluginName(plugin.SourceFileName, plugin.Name)
+	}
+
+	configs, err := s.db.Queries().GetExportConfigs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, config := range configs {
+		name, ok := names[config.PluginID]
+		if !ok {
+			continue
+		}
+		file, err := oxideFile(oxide.ConfigPath(name), config.ConfigJson, config.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	locales, err := s.db.Queries().GetExportLocales(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, locale := range locales {
+		name, ok := names[locale.PluginID]
+		if !ok {
+			continue
+		}
+		file, err := oxideFile(oxide.LangPath(locale.LangCode, name), locale.ContentJson, locale.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	slices.SortFunc(files, func(a, b oxide.File) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return files, nil
+}
+
+// Reformat stored JSON into an archive file
+func oxideFile(path, content, updatedAt string) (file oxide.File, err error) {
+	value, err := jsondoc.Parse([]byte(content))
+	if err != nil {
+		return file, &invalidExportError{path: path, err: err}
+	}
+	data, err := jsondoc.Marshal(value)
+	if err != nil {
+		return file, err
+	}
+
+	modified, err := time.Parse(time.DateTime, updatedAt)
+	if err != nil {
+		modified = time.Now()
+	}
+	return oxide.File{Path: path, Data: data, Modified: modified}, nil
+}
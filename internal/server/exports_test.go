@@ -0,0 +1,127 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"adminrust/internal/database"
+)
+
+func TestExportOxideFiles(t *testing.T) {
+	loadTestTemplates(t)
+	ctx := context.Background()
+	s := &Server{db: newTestDB(t)}
+	q := s.db.Queries()
+
+	pluginOrigin, err := q.AddOrigin(ctx, database.AddOriginParams{
+		Name: "uMod", Slug: "umod", Url: "https://umod.org", PathToPluginList: "/plugins",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Kits", "Better Chat", "Empty"} {
+		_, err = q.AddPlugin(ctx, database.AddPluginParams{
+			Name: name, Slug: slugify(name), Url: "https://umod.org/plugins/" + slugify(name), OriginID: pluginOrigin.ID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err = q.AddPluginConfig(ctx, database.AddPluginConfigParams{ConfigJson: `{"Limit":5,"Kits":["a"]}`, Slug: "kits"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = q.AddPluginConfig(ctx, database.AddPluginConfigParams{ConfigJson: `{"Format": "<b>{0}</b>"}`, Slug: "better-chat"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = q.AddPluginLocale(ctx, database.AddPluginLocaleParams{
+		LangCode: "en", LangName: "English", ContentJson: `{"NoPermission": "No!"}`, Slug: "kits",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		query         string
+		expectedCode  int
+		expectedFiles []string
+	}{
+		{
+			name:         "everything",
+			query:        "",
+			expectedCode: http.StatusOK,
+			expectedFiles: []string{
+				"oxide/config/BetterChat.json", "oxide/config/Kits.json", "oxide/lang/en/Kits.json",
+			},
+		},
+		{
+			name:          "one plugin",
+			query:         "?plugin=kits",
+			expectedCode:  http.StatusOK,
+			expectedFiles: []string{"oxide/config/Kits.json", "oxide/lang/en/Kits.json"},
+		},
+		{
+			name:          "selection",
+			query:         "?plugin=better-chat&plugin=empty",
+			expectedCode:  http.StatusOK,
+			expectedFiles: []string{"oxide/config/BetterChat.json"},
+		},
+		{name: "tar.gz", query: "?plugin=kits&format=tar.gz", expectedCode: http.StatusOK},
+		{name: "nothing to export", query: "?plugin=empty", expectedCode: http.StatusNotFound},
+		{name: "unknown plugin", query: "?plugin=kits&plugin=missing", expectedCode: http.StatusNotFound},
+		{name: "unknown format", query: "?format=rar", expectedCode: http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/export/download"+test.query, nil)
+			w := httptest.NewRecorder()
+			s.exportOxideFiles(w, r)
+			if w.Code != test.expectedCode {
+				t.Fatalf("exportOxideFiles() status = %d, want %d", w.Code, test.expectedCode)
+			}
+			if test.expectedFiles == nil {
+				return
+			}
+
+			archive, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var files []string
+			for _, file := range archive.File {
+				files = append(files, file.Name)
+			}
+			if !slices.Equal(files, test.expectedFiles) {
+				t.Errorf("archive files = %q, want %q", files, test.expectedFiles)
+			}
+		})
+	}
+
+	// JSON is written the way Oxide formats it
+	r := httptest.NewRequest("GET", "/export/download?plugin=better-chat", nil)
+	w := httptest.NewRecorder()
+	s.exportOxideFiles(w, r)
+	archive, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := archive.File[0].Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "{\n  \"Format\": \"<b>{0}</b>\"\n}"; string(content) != expected {
+		t.Errorf("config file = %q, want %q", content, expected)
+	}
+}
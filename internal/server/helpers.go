@@ -1,10 +1,14 @@
 package server
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"regexp"
 	"strings"
+	"time"
+
+	"adminrust/internal/version"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -52,6 +56,21 @@ func renderPage(w http.ResponseWriter, tmpltName, pageTitle string, pageContent,
 	}
 }
 
+// Time to read and answer large uploads, the server timeouts are
+// meant for ordinary requests
+const uploadTimeout = 5 * time.Minute
+
+// Extend the connection deadlines of the request for a large upload
+func allowSlowUpload(w http.ResponseWriter) {
+	rc := http.NewResponseController(w)
+	deadline := time.Now().Add(uploadTimeout)
+	for _, err := range []error{rc.SetReadDeadline(deadline), rc.SetWriteDeadline(deadline)} {
+		if err != nil && !errors.Is(err, http.ErrNotSupported) {
+			log.Println(err)
+		}
+	}
+}
+
 // Compile a regexp pattern and return a validator function that checks
 // if the input matches the required pattern
 func validateByPattern(pattern string) (validator func(string) bool) {
@@ -61,10 +80,18 @@ func validateByPattern(pattern string) (validator func(string) bool) {
 	}
 }
 
+// Versions are valid if they can be compared with the ones stored by
+// version checks
+func validateVersion(v string) bool {
+	_, err := version.Parse(v)
+	return err == nil
+}
+
 // Form field validators
 var (
 	validateName           = validateByPattern(`^[\w -]{3,50}$`)
 	validateOriginURL      = validateByPattern(`^https?://[a-zA-Z0-9-]+\.[a-z]{2,5}/?$`)
 	validatePluginURL      = validateByPattern(`^(https?://[a-zA-Z0-9-]+\.[a-z]{2,5}(/[a-zA-Z0-9%?=&_-]+)+)$`)
-	validatePluginsURLPath = validateByPattern(`^(https?://[a-zA-Z0-9-]+\.[a-z]{2,5}(/[a-zA-Z0-9%?=&_-]+)+|(/[a-zA-Z0-9%?=&_-]+)+)$`)
+	validatePermission     = validateByPattern(`^[\w-]+(\.[\w-]+)+$`)
+	validatePluginsURLPath = validateByPattern(`^(https?://[a-zA-Z0-9-]+\.[a-z]{2,5}(/[a-zA-Z0-9%?=&_.-]+)+|(/[a-zA-Z0-9%?=&_.-]+)+)$`)
 )
@@ -0,0 +1,209 @@
+package server
+
+import (
+	"cmp"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"adminrust/internal/database"
+	"adminrust/internal/hooktime"
+	"adminrust/internal/oxide"
+)
+
+const (
+	// period of samples charted
+	hookTimeHistory = 14 * 24 * time.Hour
+	// growth of hook time per minute after an update flagged as a regression
+	hookTimeRegressionFactor = 2
+	hookTimeRegressionMin    = 100 * time.Millisecond
+	// size of charts in SVG units
+	hookChartWidth  = 600
+	hookChartHeight = 120
+)
+
+// Hook time per minute of a plugin on a server over time
+type hookTimeChart struct {
+	// server or plugin the chart is of
+	Name, URL string
+	// hook time per minute of the last and the busiest intervals
+	Latest, Peak time.Duration
+	// SVG polyline of hook time per minute
+	Points        string
+	Width, Height int
+	// where the plugin version changed
+	VersionMarks []versionMark
+	Regressions  []hooktime.Regression
+}
+
+// Version change position on a chart
+type versionMark struct {
+	X       float64
+	Version string
+}
+
+// Chart hook time per minute between the samples. Charts need at least
+// two samples.
+func newHookTimeChart(name, url string, samples []hooktime.Sample) (chart hookTimeChart, ok bool) {
+	intervals := hooktime.Deltas(samples)
+	if len(intervals) == 0 {
+		return chart, false
+	}
+
+	chart = hookTimeChart{
+		Name:        name,
+		URL:         url,
+		Width:       hookChartWidth,
+		Height:      hookChartHeight,
+		Latest:      intervals[len(intervals)-1].PerMinute().Round(time.Millisecond),
+		Regressions: hooktime.Regressions(intervals, hookTimeRegressionFactor, hookTimeRegressionMin),
+	}
+	for _, interval := range intervals {
+		chart.Peak = max(chart.Peak, interval.PerMinute())
+	}
+
+	first, span := intervals[0].Start, intervals[len(intervals)-1].End.Sub(intervals[0].Start)
+	x := func(at time.Time) float64 {
+		return float64(at.Sub(first)) / float64(span) * hookChartWidth
+	}
+	points := make([]string, 0, len(intervals)+1)
+	points = append(points, fmt.Sprintf("0,%d", hookChartHeight))
+	version := samples[0].Version
+	for _, interval := range intervals {
+		y := float64(hookChartHeight)
+		if chart.Peak > 0 {
+			y -= float64(interval.PerMinute()) / float64(chart.Peak) * hookChartHeight
+		}
+		points = append(points, fmt.Sprintf("%.1f,%.1f", x(interval.End), y))
+
+		if interval.Version != version {
+			chart.VersionMarks = append(chart.VersionMarks, versionMark{X: x(interval.Start), Version: interval.Version})
+			version = interval.Version
+		}
+	}
+	chart.Points = strings.Join(points, " ")
+	chart.Peak = chart.Peak.Round(time.Millisecond)
+
+	return chart, true
+}
+
+// Sample of a stored row, samples are stored in UTC
+func hookTimeSample(sampledAt, version string, hookTime float64) hooktime.Sample {
+	at, _ := time.Parse(time.DateTime, sampledAt)
+	return hooktime.Sample{
+		At:      at,
+		Version: version,
+		Total:   time.Duration(hookTime * float64(time.Second)),
+	}
+}
+
+// Charts with regressions first, then the busiest ones
+func sortHookTimeCharts(charts []hookTimeChart) {
+	slices.SortStableFunc(charts, func(a, b hookTimeChart) int {
+		if hasA, hasB := len(a.Regressions) > 0, len(b.Regressions) > 0; hasA != hasB {
+			if hasA {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(b.Latest, a.Latest)
+	})
+}
+
+// Render hook time charts of the plugin on every server
+func (s *Server) getPluginHookTimes(w http.ResponseWriter, r *http.Request) {
+	plugin, err := s.db.Queries().GetPlugin(r.Context(), r.PathValue("pluginSlug"))
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+	// servers report plugins by their file names
+	sourceFileName, err := s.db.Queries().GetPluginSourceFileName(r.Context(), plugin.ID)
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+	rows, err := s.db.Queries().GetPluginHookTimes(r.Context(), database.GetPluginHookTimesParams{
+		FileName:  oxide.PluginName(sourceFileName, plugin.Name) + ".cs",
+		SampledAt: time.Now().UTC().Add(-hookTimeHistory).Format(time.DateTime),
+	})
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// rows come ordered by servers
+	var charts []hookTimeChart
+	for start := 0; start < len(rows); {
+		end := start
+		var samples []hooktime.Sample
+		for ; end < len(rows) && rows[end].ServerSlug == rows[start].ServerSlug; end++ {
+			samples = append(samples, hookTimeSample(rows[end].SampledAt, rows[end].Version, rows[end].HookTime))
+		}
+		if chart, ok := newHookTimeChart(rows[start].ServerName, "/servers/"+rows[start].ServerSlug, samples); ok {
+			charts = append(charts, chart)
+		}
+		start = end
+	}
+	sortHookTimeCharts(charts)
+
+	renderPage(w, "hook_time_charts", "", charts, nil)
+}
+
+// Render hook time charts of every plugin loaded on the server
+func (s *Server) getServerHookTimes(w http.ResponseWriter, r *http.Request) {
+	server, err := s.db.Queries().GetServer(r.Context(), r.PathValue("serverSlug"))
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+	rows, err := s.db.Queries().GetServerHookTimes(r.Context(), database.GetServerHookTimesParams{
+		ServerID:  server.ID,
+		SampledAt: time.Now().UTC().Add(-hookTimeHistory).Format(time.DateTime),
+	})
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+	// known plugins link to their pages
+	known, err := s.db.Queries().GetPluginFileNames(r.Context())
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+	slugs := make(map[string]string, len(known))
+	for _, plugin := range known {
+		slugs[strings.ToLower(oxide.PluginName(plugin.FileName, plugin.Name))] = plugin.Slug
+	}
+
+	// rows come ordered by file names
+	var charts []hookTimeChart
+	for start := 0; start < len(rows); {
+		name := strings.ToLower(oxide.PluginName(rows[start].FileName, rows[start].Title))
+		end := start
+		var samples []hooktime.Sample
+		for ; end < len(rows) && strings.EqualFold(rows[end].FileName, rows[start].FileName); end++ {
+			samples = append(samples, hookTimeSample(rows[end].SampledAt, rows[end].Version, rows[end].HookTime))
+		}
+		var url string
+		if slug, ok := slugs[name]; ok {
+			url = "/plugins/" + slug
+		}
+		if chart, ok := newHookTimeChart(rows[end-1].Title, url, samples); ok {
+			charts = append(charts, chart)
+		}
+		start = end
+	}
+	sortHookTimeCharts(charts)
+
+	renderPage(w, "hook_time_charts", "", charts, nil)
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"adminrust/internal/database"
+	"adminrust/internal/hooktime"
+)
+
+func TestNewHookTimeChart(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	samples := []hooktime.Sample{
+		{At: start, Version: "1.0.0", Total: time.Second},
+		{At: start.Add(10 * time.Minute), Version: "1.0.0", Total: 2 * time.Second},
+		{At: start.Add(20 * time.Minute), Version: "1.1.0", Total: 4 * time.Second},
+	}
+
+	chart, ok := newHookTimeChart("Main", "/servers/main", samples)
+	if !ok {
+		t.Fatal("newHookTimeChart() = false, want a chart")
+	}
+	if chart.Points != "0,120 300.0,90.0 600.0,0.0" {
+		t.Errorf("Points = %q", chart.Points)
+	}
+	if chart.Latest != 400*time.Millisecond || chart.Peak != 400*time.Millisecond {
+		t.Errorf("Latest = %s, Peak = %s, want 400ms", chart.Latest, chart.Peak)
+	}
+	if len(chart.VersionMarks) != 1 || chart.VersionMarks[0] != (versionMark{X: 300, Version: "1.1.0"}) {
+		t.Errorf("VersionMarks = %+v, want 1.1.0 in the middle", chart.VersionMarks)
+	}
+	if len(chart.Regressions) != 1 {
+		t.Errorf("Regressions = %+v, want the update doubling hook time", chart.Regressions)
+	}
+
+	if _, ok = newHookTimeChart("Main", "", samples[:1]); ok {
+		t.Error("newHookTimeChart() of one sample = true, want no chart")
+	}
+}
+
+func TestHookTimeCharts(t *testing.T) {
+	loadTestTemplates(t)
+	ctx := context.Background()
+	db := newTestDB(t)
+	s := &Server{db: db}
+	q := s.db.Queries()
+
+	pluginOrigin, err := q.AddOrigin(ctx, database.AddOriginParams{
+		Name: "uMod", Slug: "umod", Url: "https://umod.org", PathToPluginList: "/plugins",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = q.AddPlugin(ctx, database.AddPluginParams{
+		Name: "Kits", Slug: "kits", Url: "https://umod.org/plugins/kits", OriginID: pluginOrigin.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := q.AddServer(ctx, database.AddServerParams{Name: "Main", Slug: "main", Framework: "oxide"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// samples every 15 minutes with Kits taking ten times longer after the update
+	samples := []struct {
+		version  string
+		hookTime float64
+	}{{"4.4.1", 1}, {"4.4.1", 1.5}, {"4.4.1", 2}, {"4.4.2", 5}, {"4.4.2", 10}}
+	for i, sample := range samples {
+		age := fmt.Sprintf("%d minutes", 15*(i-len(samples)))
+		addTestHookTimeSample(t, db, database.AddHookTimeSampleParams{
+			ServerID: server.ID, Title: "Kits", FileName: "Kits.cs", Version: sample.version, HookTime: sample.hookTime,
+		}, age)
+		addTestHookTimeSample(t, db, database.AddHookTimeSampleParams{
+			ServerID: server.ID, Title: "Admin Radar", FileName: "AdminRadar.cs", Version: "5.3.3", HookTime: float64(i),
+		}, age)
+	}
+
+	r := httptest.NewRequest("GET", "/plugins/kits/hook-times", nil)
+	r.SetPathValue("pluginSlug", "kits")
+	w := httptest.NewRecorder()
+	s.getPluginHookTimes(w, r)
+	for _, part := range []string{`href="/servers/main"`, "Slower after update", "4.4.1 → 4.4.2", "<polyline"} {
+		if !strings.Contains(w.Body.String(), part) {
+			t.Errorf("plugin hook times miss %q:\n%s", part, w.Body.String())
+		}
+	}
+
+	r = httptest.NewRequest("GET", "/servers/main/hook-times", nil)
+	r.SetPathValue("serverSlug", "main")
+	w = httptest.NewRecorder()
+	s.getServerHookTimes(w, r)
+	body := w.Body.String()
+	// the regressed plugin comes first
+	kits, radar := strings.Index(body, `href="/plugins/kits"`), strings.Index(body, "Admin Radar")
+	if kits < 0 || radar < 0 || kits > radar {
+		t.Errorf("server hook times list Kits at %d and Admin Radar at %d:\n%s", kits, radar, body)
+	}
+}
+
+// Store a hook time sample taken the given time ago, e.g. "-15 minutes"
+func addTestHookTimeSample(t *testing.T, db *testDB, sample database.AddHookTimeSampleParams, age string) {
+	t.Helper()
+
+	ctx := context.Background()
+	if err := db.queries.AddHookTimeSample(ctx, sample); err != nil {
+		t.Fatal(err)
+	}
+	_, err := db.db.ExecContext(ctx, "UPDATE hook_time_samples SET sampled_at = datetime('now', ?) WHERE id = last_insert_rowid()", age)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSampleAllHookTimesPrunes(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	s := &Server{db: db}
+	q := s.db.Queries()
+
+	// samples taken the given number of days ago
+	samples := map[string][]int{
+		"main":    {20, 16, 1},
+		"retired": {30, 20},
+		// servers without samples don't hold the pruning up
+		"new": {},
+	}
+	kept := map[string]int{"main": 1, "retired": 0, "new": 0}
+	for slug, ages := range samples {
+		server, err := q.AddServer(ctx, database.AddServerParams{Name: slug, Slug: slug, Framework: "oxide"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, age := range ages {
+			addTestHookTimeSample(t, db, database.AddHookTimeSampleParams{
+				ServerID: server.ID, Title: "Kits", FileName: "Kits.cs", Version: "4.4.2", HookTime: 1,
+			}, fmt.Sprintf("-%d days", age))
+		}
+		// snapshots are reconciliation history and aren't pruned
+		snapshot, err := q.AddServerSnapshot(ctx, server.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = db.db.ExecContext(ctx, "UPDATE server_snapshots SET created_at = datetime('now', '-30 days') WHERE id = ?", snapshot.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := s.sampleAllHookTimes(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for slug := range samples {
+		var sampleCount, snapshotCount int
+		err := db.db.QueryRowContext(ctx, `SELECT
+			(SELECT COUNT(*) FROM hook_time_samples WHERE server_id = servers.id),
+			(SELECT COUNT(*) FROM server_snapshots WHERE server_id = servers.id)
+			FROM servers WHERE slug = ?`, slug).Scan(&sampleCount, &snapshotCount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sampleCount != kept[slug] || snapshotCount != 1 {
+			t.Errorf("%s samples = %d, snapshots = %d, want %d samples and the snapshot", slug, sampleCount, snapshotCount, kept[slug])
+		}
+	}
+}
@@ -0,0 +1,422 @@
+package server
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"adminrust/internal/database"
+	"adminrust/internal/locales"
+	"adminrust/internal/oxide"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Largest uploaded archive of an Oxide directory
+const maxImportUpload = 100 << 20
+
+// Uploaded archives wait for confirming in the temporary directory
+// and are removed after applying or after a day
+const (
+	importArchivePattern = "adminrust-import-*.zip"
+	importArchiveMaxAge  = 24 * time.Hour
+)
+
+// Name of an uploaded archive file created with importArchivePattern
+var validateImportArchive = validateByPattern(`^adminrust-import-\d+\.zip$`)
+
+// Import-related routes
+func (s *Server) registerImportRoutes(r *chi.Mux) {
+	r.Route("/import", func(r chi.Router) {
+		r.Get("/", s.importForm)
+		// dry run
+		r.Post("/", s.previewImport)
+		// saving
+		r.Post("/apply", s.applyImport)
+	})
+}
+
+// Where imported files are read from
+type importSource struct {
+	// name of the uploaded archive in the temporary directory
+	Archive string
+	// local directory on the machine running the server
+	Directory string
+}
+
+// State of an imported config or locale compared with the stored one
+const (
+	importNew       = "new"
+	importReplace   = "replace"
+	importUnchanged = "unchanged"
+	importSkipped   = "skipped"
+)
+
+// Imported locale of a plugin
+type importedLocale struct {
+	LangCode string
+	LangName string
+	Content  string
+	State    string
+}
+
+// Imported plugin matched to a stored one or to be created
+type importedPlugin struct {
+	oxide.Plugin
+	// zero for plugins to create
+	PluginID    int64
+	Slug        string
+	Title       string
+	ConfigState string
+	Locales     []importedLocale
+}
+
+// Changes the import makes
+type importPlan struct {
+	Plugins  []importedPlugin
+	Problems []string
+}
+
+// Number of plugins to create
+func (p importPlan) NewPlugins() (count int) {
+	for _, plugin := range p.Plugins {
+		if plugin.PluginID == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// Render form for importing an Oxide directory
+func (s *Server) importForm(w http.ResponseWriter, r *http.Request) {
+	origins, err := s.db.Queries().GetOrigins(r.Context())
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	renderPage(w, "import", "Import", nil, origins)
+}
+
+// Show what importing the uploaded archive or the local directory
+// would change without saving anything
+func (s *Server) previewImport(w http.ResponseWriter, r *http.Request) {
+	allowSlowUpload(w)
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportUpload)
+	if err := r.ParseMultipartForm(maxImportUpload); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		log.Println(err)
+		errorHandler(w, http.StatusRequestEntityTooLarge, "Upload is too large")
+		return
+	}
+
+	var source importSource
+	file, _, err := r.FormFile("archive")
+	switch {
+	case err == nil:
+		source.Archive, err = saveImportArchive(file)
+		file.Close()
+		if err != nil {
+			log.Println(err)
+			internalServerErr(w)
+			return
+		}
+	case errors.Is(err, http.ErrMissingFile) || errors.Is(err, http.ErrNotMultipart):
+		source.Directory = strings.TrimSpace(r.FormValue("directory"))
+	default:
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	fsys, closeSource, err := openImportSource(source)
+	if err != nil {
+		log.Println(err)
+		errorHandler(w, http.StatusUnprocessableEntity, "Import source can't be read")
+		return
+	}
+	defer closeSource()
+
+	plan, err := s.planImport(r.Context(), fsys)
+	if err != nil {
+		log.Println(err)
+		errorHandler(w, http.StatusUnprocessableEntity, "No Oxide directory found")
+		return
+	}
+	origins, err := s.db.Queries().GetOrigins(r.Context())
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	metaData := struct {
+		Source  importSource
+		Origins []database.PluginOrigin
+	}{source, origins}
+
+	renderPage(w, "import_preview", "Import Preview", plan, metaData)
+}
+
+// Import the previewed files
+func (s *Server) applyImport(w http.ResponseWriter, r *http.Request) {
+	source := importSource{
+		Archive:   r.FormValue("archive"),
+		Directory: r.FormValue("directory"),
+	}
+	fsys, closeSource, err := openImportSource(source)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+	defer closeSource()
+
+	plan, err := s.planImport(r.Context(), fsys)
+	if err != nil {
+		log.Println(err)
+		errorHandler(w, http.StatusUnprocessableEntity, "No Oxide directory found")
+		return
+	}
+
+	// an origin is required only for plugins to create
+	var pluginOrigin database.PluginOrigin
+	if plan.NewPlugins() > 0 {
+		originID, err := strconv.ParseInt(r.FormValue("originID"), 10, 64)
+		if err != nil {
+			log.Println(err)
+			badRequest(w)
+			return
+		}
+		pluginOrigin, err = s.db.Queries().GetOriginByID(r.Context(), originID)
+		if err != nil {
+			log.Println(err)
+			badRequest(w)
+			return
+		}
+	}
+
+	err = s.db.InTx(r.Context(), func(q *database.Queries) error {
+		for _, plugin := range plan.Plugins {
+			if plugin.PluginID == 0 {
+				// the webpage is unknown until the origin is synced
+				created, err := q.AddPlugin(r.Context(), database.AddPluginParams{
+					Name:        plugin.Title,
+					Slug:        plugin.Slug,
+					Description: plugin.Info.Description,
+					OriginID:    pluginOrigin.ID,
+				})
+				if err != nil {
+					return err
+				}
+				plugin.PluginID = created.ID
+			}
+
+			if plugin.ConfigState == importNew || plugin.ConfigState == importReplace {
+				err := q.SetPluginConfig(r.Context(), database.SetPluginConfigParams{
+					PluginID:   plugin.PluginID,
+					ConfigJson: plugin.Config,
+				})
+				if err != nil {
+					return err
+				}
+			}
+			for _, locale := range plugin.Locales {
+				if locale.State != importNew && locale.State != importReplace {
+					continue
+				}
+				err := q.SetPluginLocale(r.Context(), database.SetPluginLocaleParams{
+					PluginID:    plugin.PluginID,
+					LangCode:    locale.LangCode,
+					LangName:    locale.LangName,
+					ContentJson: locale.Content,
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// the uploaded archive isn't needed anymore
+	if source.Archive != "" {
+		if err = os.Remove(filepath.Join(os.TempDir(), source.Archive)); err != nil {
+			log.Println(err)
+		}
+	}
+
+	// redirect to plugin list
+	http.Redirect(w, r, "/plugins", http.StatusFound)
+}
+
+// Save the uploaded archive to the temporary directory removing
+// ones left unconfirmed
+func saveImportArchive(file io.Reader) (name string, err error) {
+	stale, _ := filepath.Glob(filepath.Join(os.TempDir(), importArchivePattern))
+	for _, path := range stale {
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > importArchiveMaxAge {
+			os.Remove(path)
+		}
+	}
+
+	archive, err := os.CreateTemp("", importArchivePattern)
+	if err != nil {
+		return "", err
+	}
+	defer archive.Close()
+	if _, err = io.Copy(archive, file); err != nil {
+		os.Remove(archive.Name())
+		return "", err
+	}
+
+	return filepath.Base(archive.Name()), archive.Close()
+}
+
+// Open the uploaded archive or the local directory as a file system
+func openImportSource(source importSource) (fsys fs.FS, closeSource func(), err error) {
+	if source.Archive != "" {
+		if !validateImportArchive(source.Archive) {
+			return nil, nil, fmt.Errorf("invalid archive name: %s", source.Archive)
+		}
+		archive, err := zip.OpenReader(filepath.Join(os.TempDir(), source.Archive))
+		if err != nil {
+			return nil, nil, err
+		}
+		return archive, func() { archive.Close() }, nil
+	}
+
+	if !filepath.IsAbs(source.Directory) {
+		return nil, nil, fmt.Errorf("directory path isn't absolute: %q", source.Directory)
+	}
+	info, err := os.Stat(source.Directory)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !info.IsDir() {
+		return nil, nil, fmt.Errorf("not a directory: %s", source.Directory)
+	}
+
+	return os.DirFS(source.Directory), func() {}, nil
+}
+
+// Read the Oxide directory and match its plugins to stored ones by the
+// names Oxide knows them by or by slugs of their titles
+func (s *Server) planImport(ctx context.Context, fsys fs.FS) (plan importPlan, err error) {
+	installation, err := oxide.Scan(fsys)
+	if err != nil {
+		return plan, err
+	}
+	plan.Problems = installation.Problems
+
+	langs, err := loadAvailableLangs()
+	if err != nil {
+		return plan, err
+	}
+
+	plugins, err := s.db.Queries().GetExportPlugins(ctx)
+	if err != nil {
+		return plan, err
+	}
+	byName := make(map[string]database.GetExportPluginsRow)
+	bySlug := make(map[string]database.GetExportPluginsRow)
+	for _, plugin := range plugins {
+		byName[strings.ToLower(oxide.PluginName(plugin.SourceFileName, plugin.Name))] = plugin
+		bySlug[plugin.Slug] = plugin
+	}
+
+	// stored contents to tell new, replaced and unchanged files apart
+	configs, err := s.db.Queries().GetExportConfigs(ctx)
+	if err != nil {
+		return plan, err
+	}
+	storedConfigs := make(map[int64]string)
+	for _, config := range configs {
+		storedConfigs[config.PluginID] = config.ConfigJson
+	}
+	localeRows, err := s.db.Queries().GetExportLocales(ctx)
+	if err != nil {
+		return plan, err
+	}
+	storedLocales := make(map[string]string)
+	for _, locale := range localeRows {
+		// imported lang files are normalized, stored ones may be not
+		content, err := locales.Normalize(locale.ContentJson)
+		if err != nil {
+			content = locale.ContentJson
+		}
+		storedLocales[fmt.Sprintf("%d/%s", locale.PluginID, locale.LangCode)] = content
+	}
+
+	planned := make(map[string]bool)
+	for _, plugin := range installation.Plugins {
+		imported := importedPlugin{Plugin: plugin, Title: plugin.Name}
+		if plugin.Info.Title != "" {
+			imported.Title = plugin.Info.Title
+		}
+		imported.Slug = slugify(imported.Title)
+
+		stored, ok := byName[strings.ToLower(plugin.Name)]
+		if !ok {
+			stored, ok = bySlug[imported.Slug]
+		}
+		if ok {
+			imported.PluginID, imported.Slug, imported.Title = stored.ID, stored.Slug, stored.Name
+		}
+		if imported.Slug == "" || planned[imported.Slug] {
+			plan.Problems = append(plan.Problems, fmt.Sprintf("%s: matches another imported plugin", plugin.Name))
+			continue
+		}
+		planned[imported.Slug] = true
+
+		// plugins to create have zero IDs and nothing stored
+		if plugin.Config != "" {
+			stored, exists := storedConfigs[imported.PluginID]
+			imported.ConfigState = importState(plugin.Config, stored, exists)
+		}
+		for langCode, content := range plugin.Locales {
+			// languages unknown to the locale forms are skipped
+			locale := importedLocale{LangCode: langCode, Content: content, State: importSkipped}
+			if langName, ok := langs[langCode]; ok {
+				stored, exists := storedLocales[fmt.Sprintf("%d/%s", imported.PluginID, langCode)]
+				locale.LangName = langName
+				locale.State = importState(content, stored, exists)
+			}
+			imported.Locales = append(imported.Locales, locale)
+		}
+		slices.SortFunc(imported.Locales, func(a, b importedLocale) int {
+			return strings.Compare(a.LangCode, b.LangCode)
+		})
+
+		plan.Plugins = append(plan.Plugins, imported)
+	}
+
+	return plan, nil
+}
+
+// Compare imported content with the stored one
+func importState(content, stored string, exists bool) string {
+	switch {
+	case !exists:
+		return importNew
+	case strings.TrimSpace(content) == strings.TrimSpace(stored):
+		return importUnchanged
+	}
+	return importReplace
+}
@@ -0,0 +1,229 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"adminrust/internal/database"
+)
+
+func TestImportOxideDirectory(t *testing.T) {
+	loadTestTemplates(t)
+	availableLangs = map[string]string{"en": "English", "ru": "Русский"}
+	t.Cleanup(func() { availableLangs = nil })
+	ctx := context.Background()
+	s := &Server{db: newTestDB(t)}
+	q := s.db.Queries()
+
+	pluginOrigin, err := q.AddOrigin(ctx, database.AddOriginParams{
+		Name: "uMod", Slug: "umod", Url: "https://umod.org", PathToPluginList: "/plugins",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = q.AddPlugin(ctx, database.AddPluginParams{
+		Name: "Kits", Slug: "kits", Url: "https://umod.org/plugins/kits", OriginID: pluginOrigin.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = q.AddPluginConfig(ctx, database.AddPluginConfigParams{ConfigJson: `{"Limit": 5}`, Slug: "kits"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"oxide/plugins/BetterChat.cs":   `[Info("Better Chat", "LaserHydra", "5.2.15")] [Description("Chat groups")] class BetterChat {}`,
+		"oxide/config/Kits.json":        `{"Limit": 5}`,
+		"oxide/config/BetterChat.json":  `{"Groups": []}`,
+		"oxide/lang/en/Kits.json":       `{"NoPermission": "No!"}`,
+		"oxide/lang/xx/Kits.json":       `{}`,
+		"oxide/lang/ru/BetterChat.json": `{"Muted": "Нельзя"}`,
+		"oxide/config/Broken.json":      `{`,
+	}
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err = os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// send a form to the import handler
+	send := func(handler http.HandlerFunc, form url.Values) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/import", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		handler(w, r)
+		return w
+	}
+
+	previewTests := []struct {
+		name          string
+		directory     string
+		expectedCode  int
+		expectedParts []string
+	}{
+		{
+			name:         "dry run",
+			directory:    dir,
+			expectedCode: http.StatusOK,
+			expectedParts: []string{
+				"Better Chat", "Create", "5.2.15", "unchanged", "skipped", "Broken.json", "Origin of new plugins",
+			},
+		},
+		{name: "relative path", directory: "oxide", expectedCode: http.StatusUnprocessableEntity},
+		{name: "missing directory", directory: filepath.Join(dir, "missing"), expectedCode: http.StatusUnprocessableEntity},
+		{name: "no oxide directory", directory: t.TempDir(), expectedCode: http.StatusUnprocessableEntity},
+	}
+	for _, test := range previewTests {
+		t.Run(test.name, func(t *testing.T) {
+			w := send(s.previewImport, url.Values{"directory": {test.directory}})
+			if w.Code != test.expectedCode {
+				t.Fatalf("previewImport() status = %d, want %d", w.Code, test.expectedCode)
+			}
+			for _, part := range test.expectedParts {
+				if !strings.Contains(w.Body.String(), part) {
+					t.Errorf("previewImport() body misses %q", part)
+				}
+			}
+		})
+	}
+
+	// the dry run saves nothing
+	if _, err = q.GetPlugin(ctx, "better-chat"); err == nil {
+		t.Fatal("previewImport() created a plugin")
+	}
+
+	// creating plugins requires an origin
+	if w := send(s.applyImport, url.Values{"directory": {dir}}); w.Code != http.StatusBadRequest {
+		t.Errorf("applyImport() without origin status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	w := send(s.applyImport, url.Values{"directory": {dir}, "originID": {strconv.FormatInt(pluginOrigin.ID, 10)}})
+	if w.Code != http.StatusFound {
+		t.Fatalf("applyImport() status = %d, want %d", w.Code, http.StatusFound)
+	}
+
+	betterChat, err := q.GetPlugin(ctx, "better-chat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if betterChat.Name != "Better Chat" || betterChat.Description != "Chat groups" || betterChat.Url != "" {
+		t.Errorf("created plugin = %+v", betterChat)
+	}
+	config, err := q.GetPluginConfig(ctx, "better-chat")
+	if err != nil || config.ConfigJson != `{"Groups": []}` {
+		t.Errorf("created plugin config = %q, %v", config.ConfigJson, err)
+	}
+	locales, err := q.GetPluginLocales(ctx, "kits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(locales) != 1 || locales[0].LangCode != "en" || locales[0].LangName != "English" {
+		t.Errorf("matched plugin locales = %+v, want English only", locales)
+	}
+
+	// importing again changes nothing and needs no origin
+	if w := send(s.applyImport, url.Values{"directory": {dir}}); w.Code != http.StatusFound {
+		t.Errorf("repeated applyImport() status = %d, want %d", w.Code, http.StatusFound)
+	}
+	plugins, err := q.GetPlugins(ctx)
+	if err != nil || len(plugins) != 2 {
+		t.Errorf("plugins after repeated import = %d, %v, want 2", len(plugins), err)
+	}
+}
+
+func TestImportOxideArchive(t *testing.T) {
+	loadTestTemplates(t)
+	availableLangs = map[string]string{"en": "English"}
+	t.Cleanup(func() { availableLangs = nil })
+	s := &Server{db: newTestDB(t)}
+
+	var archive bytes.Buffer
+	zipWriter := zip.NewWriter(&archive)
+	entry, err := zipWriter.Create("oxide/config/Kits.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = entry.Write([]byte(`{"Limit": 5}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err = zipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var body bytes.Buffer
+	upload := multipart.NewWriter(&body)
+	part, err := upload.CreateFormFile("archive", "server.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = part.Write(archive.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if err = upload.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptest.NewRequest("POST", "/import", &body)
+	r.Header.Set("Content-Type", upload.FormDataContentType())
+	w := httptest.NewRecorder()
+	s.previewImport(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("previewImport() status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	// the uploaded archive waits for confirming
+	match := regexp.MustCompile(`name="archive" value="([^"]+)"`).FindStringSubmatch(w.Body.String())
+	if match == nil {
+		t.Fatal("previewImport() body misses the archive name")
+	}
+	path := filepath.Join(os.TempDir(), match[1])
+	t.Cleanup(func() { os.Remove(path) })
+	if _, err = os.Stat(path); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"../secret.zip", "other.zip"} {
+		r = httptest.NewRequest("POST", "/import/apply", strings.NewReader(url.Values{"archive": {name}}.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w = httptest.NewRecorder()
+		s.applyImport(w, r)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("applyImport(%q) status = %d, want %d", name, w.Code, http.StatusBadRequest)
+		}
+	}
+
+	// the archive is removed after importing
+	pluginOrigin, err := s.db.Queries().AddOrigin(context.Background(), database.AddOriginParams{
+		Name: "uMod", Slug: "umod", Url: "https://umod.org", PathToPluginList: "/plugins",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	form := url.Values{"archive": {match[1]}, "originID": {strconv.FormatInt(pluginOrigin.ID, 10)}}
+	r = httptest.NewRequest("POST", "/import/apply", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w = httptest.NewRecorder()
+	s.applyImport(w, r)
+	if w.Code != http.StatusFound {
+		t.Errorf("applyImport() status = %d, want %d", w.Code, http.StatusFound)
+	}
+	if _, err = os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("archive still exists after importing: %v", err)
+	}
+}
@@ -0,0 +1,210 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"adminrust/internal/database"
+	"adminrust/internal/origin"
+	"adminrust/internal/scheduler"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Number of recent job runs shown on the jobs page
+const recentJobRunsLimit = 50
+
+// Background jobs with their default schedules
+func (s *Server) backgroundJobs() []scheduler.Job {
+	return []scheduler.Job{
+		{Name: "origin-sync", Schedule: "@daily", Task: s.syncAllOrigins},
+		{Name: "version-check", Schedule: "0 */6 * * *", Task: s.checkAllPluginVersions},
+		{Name: "hook-time-sample", Schedule: "*/15 * * * *", Task: s.sampleAllHookTimes},
+	}
+}
+
+// Job-related routes
+func (s *Server) registerJobRoutes(r *chi.Mux) {
+	r.Route("/jobs", func(r chi.Router) {
+		r.Get("/", s.getJobs)
+
+		r.Route("/{jobName:[a-z0-9-]+}", func(r chi.Router) {
+			r.Post("/run", s.runJob)
+			r.Post("/schedule", s.updateJobSchedule)
+		})
+	})
+}
+
+// Synchronize plugins of every origin that has an adapter
+func (s *Server) syncAllOrigins(ctx context.Context) error {
+	origins, err := s.db.Queries().GetOrigins(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, pluginOrigin := range origins {
+		if origin.AdapterName(pluginOrigin) == "" {
+			continue
+		}
+		report, err := s.syncOriginPlugins(ctx, pluginOrigin)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", pluginOrigin.Name, err))
+			continue
+		}
+		log.Printf("Origin %s synced: %+v\n", pluginOrigin.Name, report)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Check versions of all plugins
+func (s *Server) checkAllPluginVersions(ctx context.Context) error {
+	plugins, err := s.db.Queries().GetPlugins(ctx)
+	if err != nil {
+		return err
+	}
+
+	report := s.checkPluginVersions(ctx, plugins)
+	log.Printf("Versions checked: %d, new: %v\n", report.Checked, report.NewVersions)
+	if len(report.Failed) > 0 {
+		return fmt.Errorf("%d plugins failed: %v", len(report.Failed), report.Failed)
+	}
+
+	return nil
+}
+
+// Sample hook times of plugins loaded on every server with RCON
+func (s *Server) sampleAllHookTimes(ctx context.Context) error {
+	servers, err := s.db.Queries().GetServers(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	sampled := 0
+	for _, row := range servers {
+		if row.RconHost == "" || row.Framework != "oxide" {
+			continue
+		}
+		server, err := s.db.Queries().GetServer(ctx, row.Slug)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		listed, err := s.listServerPlugins(ctx, server)
+		if err == nil {
+			err = s.db.InTx(ctx, func(q *database.Queries) error {
+				return addHookTimeSamples(ctx, q, server.ID, listed)
+			})
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", server.Name, err))
+			continue
+		}
+		sampled++
+	}
+	log.Printf("Hook times sampled on %d servers\n", sampled)
+
+	// samples past the charted period are of no use
+	err = s.db.Queries().DeleteHookTimeSamplesBefore(ctx, time.Now().UTC().Add(-hookTimeHistory).Format(time.DateTime))
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Render a list of jobs with their recent runs
+func (s *Server) getJobs(w http.ResponseWriter, r *http.Request) {
+	jobs, err := s.db.Queries().GetJobs(r.Context())
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+	runs, err := s.db.Queries().GetRecentJobRuns(r.Context(), recentJobRunsLimit)
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// add scheduler state to stored jobs
+	type jobState struct {
+		database.Job
+		NextRun   string
+		IsRunning bool
+	}
+	jobStates := make([]jobState, 0, len(jobs))
+	for _, job := range jobs {
+		state := jobState{Job: job, IsRunning: s.scheduler.IsRunning(job.Name)}
+		if nextRun := s.scheduler.NextRun(job.Name); !nextRun.IsZero() {
+			state.NextRun = nextRun.UTC().Format(time.DateTime)
+		}
+		jobStates = append(jobStates, state)
+	}
+
+	content := struct {
+		Jobs []jobState
+		Runs []database.GetRecentJobRunsRow
+	}{jobStates, runs}
+
+	renderPage(w, "jobs", "Jobs", content, nil)
+}
+
+// Start the job in background and reload the jobs page
+func (s *Server) runJob(w http.ResponseWriter, r *http.Request) {
+	jobName := r.PathValue("jobName")
+
+	err := s.scheduler.RunNow(jobName)
+	switch {
+	case errors.Is(err, scheduler.ErrUnknownJob):
+		notFound(w, r)
+		return
+	case errors.Is(err, scheduler.ErrAlreadyRunning):
+		// nothing to do, the page shows the job is running
+	case err != nil:
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	w.Header().Set("HX-Redirect", "/jobs")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Update job schedule
+func (s *Server) updateJobSchedule(w http.ResponseWriter, r *http.Request) {
+	// check if the retrieved form contains hidden PUT method
+	if r.FormValue("_method") != "PUT" {
+		log.Println("post with no PUT input")
+		notAllowed(w, r)
+		return
+	}
+
+	jobName := r.PathValue("jobName")
+	schedule := r.FormValue("schedule")
+	if err := scheduler.ValidateSchedule(schedule); err != nil {
+		log.Printf("invalid schedule %q: %s\n", schedule, err)
+		badRequest(w)
+		return
+	}
+
+	err := s.scheduler.Reschedule(r.Context(), jobName, schedule)
+	if errors.Is(err, scheduler.ErrUnknownJob) {
+		notFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	http.Redirect(w, r, "/jobs", http.StatusFound)
+}
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"cmp"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"adminrust/internal/database"
+	"adminrust/internal/locales"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Language translations are compared with unless another one
+// is chosen with the base query parameter
+var defaultBaseLang = cmp.Or(os.Getenv("LOCALE_BASE_LANG"), "en")
+
+// Language codes as in locale routes
+var validateLangCode = validateByPattern(`^[a-zA-Z-]{2,5}$`)
+
+// Locale report routes
+func (s *Server) registerLocaleReportRoutes(r *chi.Mux) {
+	r.Get("/locales", s.getLocaleReport)
+}
+
+// Locale with its coverage of the base language
+type localeReport struct {
+	database.PluginLocale
+	IsBase bool
+	// missing if there's no base or the locale can't be parsed
+	Coverage *locales.Coverage
+	Problem  string
+}
+
+// Locales of a plugin compared with its base language
+type pluginLocaleReport struct {
+	Name, Slug string
+	HasBase    bool
+	Locales    []localeReport
+}
+
+// Coverage of a language summed up over plugins
+type langSummary struct {
+	LangCode, LangName string
+	Plugins            int
+	Complete           int
+	Total, Translated  int
+}
+
+// Share of translated base keys over every plugin
+func (l langSummary) Percent() int {
+	return locales.Coverage{Total: l.Total, Translated: l.Translated}.Percent()
+}
+
+// Base language from the query or the default one
+func baseLang(r *http.Request) string {
+	if langCode := r.URL.Query().Get("base"); validateLangCode(langCode) {
+		return langCode
+	}
+
+	return defaultBaseLang
+}
+
+// Compare plugin locales with the base language
+func compareLocales(pluginLocales []database.PluginLocale, baseCode string) (reports []localeReport, hasBase bool) {
+	var base locales.Messages
+	var baseErr error
+	for _, locale := range pluginLocales {
+		if locale.LangCode == baseCode {
+			hasBase = true
+			base, baseErr = locales.Parse(locale.ContentJson)
+		}
+	}
+
+	for _, locale := range pluginLocales {
+		report := localeReport{PluginLocale: locale, IsBase: locale.LangCode == baseCode}
+		messages, err := locales.Parse(locale.ContentJson)
+		switch {
+		case err != nil:
+			report.Problem = err.Error()
+		case report.IsBase || !hasBase:
+		case baseErr != nil:
+			report.Problem = "base locale can't be parsed"
+		default:
+			coverage := locales.Compare(base, messages)
+			report.Coverage = &coverage
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, hasBase
+}
+
+// Render coverage of every plugin locale and languages overall
+func (s *Server) getLocaleReport(w http.ResponseWriter, r *http.Request) {
+	rows, err := s.db.Queries().GetAllPluginLocales(r.Context())
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+	baseCode := baseLang(r)
+
+	// rows are ordered by plugins
+	var plugins []pluginLocaleReport
+	for start := 0; start < len(rows); {
+		end := start
+		var pluginLocales []database.PluginLocale
+		for ; end < len(rows) && rows[end].Slug == rows[start].Slug; end++ {
+			pluginLocales = append(pluginLocales, rows[end].PluginLocale)
+		}
+		report := pluginLocaleReport{Name: rows[start].Name, Slug: rows[start].Slug}
+		report.Locales, report.HasBase = compareLocales(pluginLocales, baseCode)
+		plugins = append(plugins, report)
+		start = end
+	}
+
+	var summaries []langSummary
+	byLang := make(map[string]int)
+	for _, plugin := range plugins {
+		for _, locale := range plugin.Locales {
+			if locale.Coverage == nil {
+				continue
+			}
+			i, ok := byLang[locale.LangCode]
+			if !ok {
+				i = len(summaries)
+				byLang[locale.LangCode] = i
+				summaries = append(summaries, langSummary{LangCode: locale.LangCode, LangName: locale.LangName})
+			}
+			summaries[i].Plugins++
+			summaries[i].Total += locale.Coverage.Total
+			summaries[i].Translated += locale.Coverage.Translated
+			if locale.Coverage.Complete() {
+				summaries[i].Complete++
+			}
+		}
+	}
+	slices.SortFunc(summaries, func(a, b langSummary) int {
+		return strings.Compare(a.LangCode, b.LangCode)
+	})
+
+	content := struct {
+		Plugins   []pluginLocaleReport
+		Languages []langSummary
+	}{plugins, summaries}
+	metaData := struct{ BaseLang string }{baseCode}
+
+	renderPage(w, "locale_report", "Locale Completeness", content, metaData)
+}
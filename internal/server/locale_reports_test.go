@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"adminrust/internal/database"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestLocaleReports(t *testing.T) {
+	loadTestTemplates(t)
+	ctx := context.Background()
+	s := &Server{db: newTestDB(t)}
+	q := s.db.Queries()
+
+	pluginOrigin, err := q.AddOrigin(ctx, database.AddOriginParams{
+		Name: "uMod", Slug: "umod", Url: "https://umod.org", PathToPluginList: "/plugins",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, slug := range []string{"kits", "teleport"} {
+		_, err = q.AddPlugin(ctx, database.AddPluginParams{
+			Name: slug, Slug: slug, Url: "https://umod.org/plugins/" + slug, OriginID: pluginOrigin.ID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	locales := []database.AddPluginLocaleParams{
+		{Slug: "kits", LangCode: "en", LangName: "English", ContentJson: `{"NoPermission": "No permission", "Title": "Kits"}`},
+		{Slug: "kits", LangCode: "ru", LangName: "Русский", ContentJson: `{"Title": "Kits", "Old": "Старое"}`},
+		{Slug: "kits", LangCode: "de", LangName: "Deutsch", ContentJson: `{"NoPermission": "Keine Rechte", "Title": "Sets"}`},
+		{Slug: "teleport", LangCode: "ru", LangName: "Русский", ContentJson: `{"Home": "Дом"}`},
+	}
+	for _, locale := range locales {
+		if _, err = q.AddPluginLocale(ctx, locale); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name          string
+		handler       http.HandlerFunc
+		url           string
+		expectedParts []string
+	}{
+		{
+			name:    "plugin tab",
+			handler: s.getPluginLocales,
+			url:     "/plugins/kits/loc",
+			expectedParts: []string{
+				"compared with <code>en</code>", ">base<", ">100%<", ">0%<",
+				"Missing keys", "<code class=\"me-2\">NoPermission</code>", "Untranslated keys", "Extra keys",
+			},
+		},
+		{
+			name:          "plugin tab with another base",
+			handler:       s.getPluginLocales,
+			url:           "/plugins/kits/loc?base=de",
+			expectedParts: []string{"compared with <code>de</code>", ">50%<"},
+		},
+		{
+			name:    "global report",
+			handler: s.getLocaleReport,
+			url:     "/locales",
+			expectedParts: []string{
+				"2 / 2", "0 / 2", "No <code>en</code> locale", "missing: <code class=\"me-1\">NoPermission</code>",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", test.url, nil)
+			// locale handlers read path parameters from chi
+			routeCtx := chi.NewRouteContext()
+			routeCtx.URLParams.Add("pluginSlug", "kits")
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, routeCtx))
+			w := httptest.NewRecorder()
+			test.handler(w, r)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+			}
+			for _, part := range test.expectedParts {
+				if !strings.Contains(w.Body.String(), part) {
+					t.Errorf("body misses %q", part)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"adminrust/internal/database"
+	"adminrust/internal/origin"
+)
+
+// Time limit for a single origin synchronization run from a handler
+const originSyncTimeout = 5 * time.Minute
+
+// Result of a single origin synchronization
+type syncReport struct {
+	Fetched   int
+	Created   int
+	Updated   int
+	Unchanged int
+	// listings with a slug taken by a plugin from another origin
+	Skipped []string
+}
+
+// Fetch plugin listings of the origin and upsert them into plugins
+func (s *Server) syncOriginPlugins(ctx context.Context, pluginOrigin database.PluginOrigin) (report syncReport, err error) {
+	adapter, err := origin.AdapterFor(pluginOrigin)
+	if err != nil {
+		return report, err
+	}
+
+	listings, err := adapter.FetchPlugins(ctx, pluginOrigin)
+	if err != nil {
+		return report, err
+	}
+	report.Fetched = len(listings)
+
+	// map known plugins of the origin to detect new and changed ones
+	knownPlugins, err := s.db.Queries().GetOriginPlugins(ctx, pluginOrigin.ID)
+	if err != nil {
+		return report, err
+	}
+	pluginsBySlug := make(map[string]database.Plugin, len(knownPlugins))
+	for _, plugin := range knownPlugins {
+		pluginsBySlug[plugin.Slug] = plugin
+	}
+
+	for _, listing := range listings {
+		slug := slugify(listing.Name)
+		if slug == "" {
+			log.Printf("skipping listing with unusable name: %q\n", listing.Name)
+			report.Skipped = append(report.Skipped, listing.Name)
+			continue
+		}
+
+		known, exists := pluginsBySlug[slug]
+		if exists && known.Name == listing.Name &&
+			known.Description == listing.Description && known.Url == listing.Url {
+			report.Unchanged++
+			continue
+		}
+
+		plugin, err := s.db.Queries().UpsertPlugin(ctx, database.UpsertPluginParams{
+			Name:        listing.Name,
+			Slug:        slug,
+			Description: listing.Description,
+			Url:         listing.Url,
+			OriginID:    pluginOrigin.ID,
+		})
+		// upsert returns nothing if the slug belongs to another origin
+		if errors.Is(err, sql.ErrNoRows) {
+			report.Skipped = append(report.Skipped, listing.Name)
+			continue
+		}
+		if err != nil {
+			return report, err
+		}
+
+		if exists {
+			report.Updated++
+		} else {
+			report.Created++
+		}
+		pluginsBySlug[slug] = plugin
+	}
+
+	return report, nil
+}
+
+// Synchronize origin plugins and render a report fragment
+func (s *Server) syncOrigin(w http.ResponseWriter, r *http.Request) {
+	originSlug := r.PathValue("originSlug")
+	pluginOrigin, err := s.db.Queries().GetOrigin(r.Context(), originSlug)
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	// walking through the whole listing takes longer than a usual request
+	rc := http.NewResponseController(w)
+	if err = rc.SetWriteDeadline(time.Now().Add(originSyncTimeout)); err != nil {
+		log.Println(err)
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), originSyncTimeout)
+	defer cancel()
+
+	report, err := s.syncOriginPlugins(ctx, pluginOrigin)
+	meta := struct{ Error string }{}
+	if err != nil {
+		log.Printf("Error syncing origin %s: %s\n", originSlug, err)
+		meta.Error = err.Error()
+	}
+
+	renderPage(w, "origin_sync", "Plugin Sync", report, meta)
+}
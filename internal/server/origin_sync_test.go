@@ -0,0 +1,95 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"adminrust/internal/database"
+)
+
+// A single-page uMod-like listing
+const umodListing = `{
+  "current_page": 1,
+  "last_page": 1,
+  "data": [
+    {"title": "Kits", "description": "Create kits", "url": "https://umod.org/plugins/kits"},
+    {"title": "Better Chat", "description": "Chat groups v2", "url": "https://umod.org/plugins/better-chat"},
+    {"title": "Gather Manager", "description": "Gather rates", "url": "https://umod.org/plugins/gather-manager"},
+    {"title": "Zone Manager", "description": "Zones", "url": "https://umod.org/plugins/zone-manager"}
+  ]
+}`
+
+func TestSyncOriginPlugins(t *testing.T) {
+	standIn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(umodListing))
+	}))
+	defer standIn.Close()
+
+	ctx := context.Background()
+	s := &Server{db: newTestDB(t)}
+	q := s.db.Queries()
+
+	umod, err := q.AddOrigin(ctx, database.AddOriginParams{
+		Name: "uMod", Slug: "umod", Url: standIn.URL, PathToPluginList: "/plugins/search.json", HasApi: 1, Adapter: "umod",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := q.AddOrigin(ctx, database.AddOriginParams{
+		Name: "Codefling", Slug: "codefling", Url: "https://codefling.com", PathToPluginList: "/plugins",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// existing plugins: one unchanged, one outdated, one owned by another origin
+	existing := []database.AddPluginParams{
+		{Name: "Kits", Slug: "kits", Description: "Create kits", Url: "https://umod.org/plugins/kits", OriginID: umod.ID},
+		{Name: "Better Chat", Slug: "better-chat", Description: "Chat groups", Url: "https://umod.org/plugins/better-chat", OriginID: umod.ID},
+		{Name: "Zone Manager", Slug: "zone-manager", Description: "Zones", Url: "https://codefling.com/plugins/zone-manager", OriginID: other.ID},
+	}
+	for _, params := range existing {
+		if _, err = q.AddPlugin(ctx, params); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := s.syncOriginPlugins(ctx, umod)
+	if err != nil {
+		t.Fatalf("syncOriginPlugins() error = %v", err)
+	}
+	expected := syncReport{Fetched: 4, Created: 1, Updated: 1, Unchanged: 1}
+	if report.Fetched != expected.Fetched || report.Created != expected.Created ||
+		report.Updated != expected.Updated || report.Unchanged != expected.Unchanged {
+		t.Errorf("syncOriginPlugins() report = %+v, want %+v", report, expected)
+	}
+	if len(report.Skipped) != 1 || report.Skipped[0] != "Zone Manager" {
+		t.Errorf("syncOriginPlugins() skipped = %v, want [Zone Manager]", report.Skipped)
+	}
+
+	updated, err := q.GetPlugin(ctx, "better-chat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Description != "Chat groups v2" {
+		t.Errorf("plugin description = %v, want %v", updated.Description, "Chat groups v2")
+	}
+	foreign, err := q.GetPlugin(ctx, "zone-manager")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if foreign.OriginID != other.ID {
+		t.Errorf("plugin of another origin was taken over")
+	}
+
+	// the second run has nothing to change
+	report, err = s.syncOriginPlugins(ctx, umod)
+	if err != nil {
+		t.Fatalf("syncOriginPlugins() error = %v", err)
+	}
+	if report.Unchanged != 3 || report.Created != 0 || report.Updated != 0 {
+		t.Errorf("repeated syncOriginPlugins() report = %+v, want 3 unchanged", report)
+	}
+}
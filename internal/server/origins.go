@@ -2,6 +2,7 @@ package server
 
 import (
 	"adminrust/internal/database"
+	"adminrust/internal/origin"
 	"fmt"
 	"log"
 	"net/http"
@@ -10,7 +11,7 @@ import (
 	"github.com/go-chi/chi/v5"
 )
 
-// Routes to get one/many, add, and delete origins
+// Routes to get one/many, add, delete, and sync origins
 func (s *Server) registerOriginRoutes(r *chi.Mux) {
 	r.Route("/origins", func(r chi.Router) {
 		r.Get("/", s.getOrigins)
@@ -26,6 +27,9 @@ func (s *Server) registerOriginRoutes(r *chi.Mux) {
 		r.Route("/{originSlug:[a-z0-9-]+}", func(r chi.Router) {
 			r.Get("/", s.getOrigin)
 			r.Delete("/", s.deleteOrigin)
+
+			r.Post("/sync", s.syncOrigin)
+			r.Post("/check", s.checkOriginVersions)
 		})
 	})
 }
@@ -46,21 +50,27 @@ func (s *Server) getOrigins(w http.ResponseWriter, r *http.Request) {
 // Render a detailed page for a specific origin by its ID
 func (s *Server) getOrigin(w http.ResponseWriter, r *http.Request) {
 	originSlug := r.PathValue("originSlug")
-	origin, err := s.db.Queries().GetOrigin(r.Context(), originSlug)
+	pluginOrigin, err := s.db.Queries().GetOrigin(r.Context(), originSlug)
 	if err != nil {
 		log.Println(err)
 		notFound(w, r)
 		return
 	}
 
+	// API origins without an adapter selected are synced too
+	meta := struct{ Adapter string }{origin.AdapterName(pluginOrigin)}
+
 	// populate and render detailed origin page
-	renderPage(w, "origin", origin.Name, origin, nil)
+	renderPage(w, "origin", pluginOrigin.Name, pluginOrigin, meta)
 }
 
 // Render the page with origin addition form
 func (s *Server) addOriginForm(w http.ResponseWriter, r *http.Request) {
+	// available adapters are used as meta data in form
+	meta := struct{ Adapters []string }{origin.AdapterNames()}
+
 	// populate and render origin addition form
-	renderPage(w, "add_origin", "Add Origin", nil, nil)
+	renderPage(w, "add_origin", "Add Origin", nil, meta)
 }
 
 // Post a new origin.
@@ -104,41 +114,51 @@ func (s *Server) addOrigin(w http.ResponseWriter, r *http.Request) {
 		pathToPluginList = "/" + strs[3]
 	}
 
+	// adapter is optional, but must be a known one if selected
+	adapter := r.FormValue("adapter")
+	if adapter != "" && !origin.IsKnownAdapter(adapter) {
+		log.Println("unknown adapter:", adapter)
+		badRequest(w)
+		return
+	}
+
 	slug := slugify(name)
 	originParams := database.AddOriginParams{
 		Name:             name,
 		Slug:             slug,
 		Url:              url,
 		PathToPluginList: pathToPluginList,
+		Adapter:          adapter,
 	}
 	hasAPI := r.FormValue("hasApi")
 	if hasAPI == "yes" {
 		originParams.HasApi = 1
 	}
 
-	origin, err := s.db.Queries().AddOrigin(r.Context(), originParams)
+	pluginOrigin, err := s.db.Queries().AddOrigin(r.Context(), originParams)
 	if err != nil {
 		log.Println(err)
 		internalServerErr(w)
 		return
 	}
 
-	http.Redirect(w, r, fmt.Sprintf("/origins/%s", origin.Slug), http.StatusFound)
+	http.Redirect(w, r, fmt.Sprintf("/origins/%s", pluginOrigin.Slug), http.StatusFound)
 }
 
 // Render an origin updating form
 func (s *Server) updateOriginForm(w http.ResponseWriter, r *http.Request) {
 	originSlug := r.PathValue("originSlug")
 	// get origin data from DB
-	origin, err := s.db.Queries().GetOrigin(r.Context(), originSlug)
+	pluginOrigin, err := s.db.Queries().GetOrigin(r.Context(), originSlug)
 	if err != nil {
 		log.Println(err)
 		notFound(w, r)
 		return
 	}
+	meta := struct{ Adapters []string }{origin.AdapterNames()}
 
 	// populate and render origin updating form
-	renderPage(w, "add_origin", "Update Origin", origin, nil)
+	renderPage(w, "add_origin", "Update Origin", pluginOrigin, meta)
 }
 
 // Update origin details
@@ -179,10 +199,19 @@ func (s *Server) updateOrigin(w http.ResponseWriter, r *http.Request) {
 		pathToPluginList = "/" + strs[3]
 	}
 
+	// adapter is optional, but must be a known one if selected
+	adapter := r.FormValue("adapter")
+	if adapter != "" && !origin.IsKnownAdapter(adapter) {
+		log.Println("unknown adapter:", adapter)
+		badRequest(w)
+		return
+	}
+
 	// prepare data for updating the origin in DB
 	updOriginParams := database.UpdateOriginParams{
 		Url:              url,
 		PathToPluginList: pathToPluginList,
+		Adapter:          adapter,
 		Slug:             originSlug,
 	}
 	hasAPI := r.FormValue("hasApi")
@@ -191,7 +220,7 @@ func (s *Server) updateOrigin(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// update the origin in DB
-	origin, err := s.db.Queries().UpdateOrigin(r.Context(), updOriginParams)
+	pluginOrigin, err := s.db.Queries().UpdateOrigin(r.Context(), updOriginParams)
 	if err != nil {
 		log.Println(err)
 		internalServerErr(w)
@@ -199,13 +228,19 @@ func (s *Server) updateOrigin(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// redirect to an origin detailed page
-	http.Redirect(w, r, fmt.Sprintf("/origins/%s", origin.Slug), http.StatusFound)
+	http.Redirect(w, r, fmt.Sprintf("/origins/%s", pluginOrigin.Slug), http.StatusFound)
 }
 
 // Delete origin by its ID and redirect to the origin list page
 func (s *Server) deleteOrigin(w http.ResponseWriter, r *http.Request) {
 	originSlug := r.PathValue("originSlug")
-	_, err := s.db.Queries().DeleteOrigin(r.Context(), originSlug)
+	// plugins of the origin are deleted with their sources
+	err := s.db.InTx(r.Context(), func(q *database.Queries) error {
+		if _, err := q.DeleteOrigin(r.Context(), originSlug); err != nil {
+			return err
+		}
+		return q.DeleteUnusedSourceBlobs(r.Context())
+	})
 	if err != nil {
 		log.Println(err)
 		internalServerErr(w)
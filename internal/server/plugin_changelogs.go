@@ -1,8 +1,18 @@
 package server
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"adminrust/internal/database"
+	"adminrust/internal/version"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -10,18 +20,296 @@ import (
 func (s *Server) registerPluginChangelogRoutes(r chi.Router) {
 	r.Route("/changelog", func(r chi.Router) {
 		r.Get("/", s.getPluginChangelog)
+		// version checking
+		r.Post("/check", s.checkPluginChangelog)
+		// adding
+		r.Get("/add", s.addPluginChangelogForm)
+		r.Post("/add", s.addPluginChangelog)
+		// editing
+		r.Get("/edit/{changelogID:[0-9]+}", s.updatePluginChangelogForm)
+		r.Post("/edit/{changelogID:[0-9]+}", s.updatePluginChangelog)
+		// deleting
+		r.Delete("/{changelogID:[0-9]+}", s.deletePluginChangelog)
 	})
 }
 
 // Get plugin version info
 func (s *Server) getPluginChangelog(w http.ResponseWriter, r *http.Request) {
 	pluginSlug := r.PathValue("pluginSlug")
+	s.renderPluginChangelog(w, r, pluginSlug, "")
+}
+
+// Check the plugin origin for a new version and show updated changelog
+func (s *Server) checkPluginChangelog(w http.ResponseWriter, r *http.Request) {
+	pluginSlug := r.PathValue("pluginSlug")
+	plugin, err := s.db.Queries().GetPlugin(r.Context(), pluginSlug)
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	report := s.checkPluginVersions(r.Context(), []database.Plugin{plugin})
+	message := "No new version found"
+	switch {
+	case len(report.Failed) > 0:
+		message = fmt.Sprintf("Check failed: %s", report.Failed[0])
+	case len(report.NewVersions) > 0:
+		message = fmt.Sprintf("New version found: %s", report.NewVersions[0])
+	}
+
+	s.renderPluginChangelog(w, r, pluginSlug, message)
+}
+
+// Render changelog tab with an optional status message
+func (s *Server) renderPluginChangelog(w http.ResponseWriter, r *http.Request, pluginSlug, message string) {
 	changelog, err := s.db.Queries().GetPluginChangelog(r.Context(), pluginSlug)
 	if err != nil {
 		log.Println(err)
 		internalServerErr(w)
 		return
 	}
+	sortChangelog(changelog)
+
+	metaData := struct {
+		CurrentURL string
+		Message    string
+	}{
+		CurrentURL: fmt.Sprintf("/plugins/%s/changelog", pluginSlug),
+		Message:    message,
+	}
+
+	renderPage(w, "plugin_changelogs", "", changelog, metaData)
+}
+
+// Sort changelog entries from the newest version to the oldest,
+// entries of the same version keep their order by update date
+func sortChangelog(changelog []database.PluginChangelog) {
+	slices.SortStableFunc(changelog, func(a, b database.PluginChangelog) int {
+		return version.Compare(b.Version, a.Version)
+	})
+}
+
+// Get the latest version of the changelog or false if it's empty
+func latestVersion(changelog []database.PluginChangelog) (latest string, ok bool) {
+	versions := make([]string, len(changelog))
+	for i, entry := range changelog {
+		versions[i] = entry.Version
+	}
+
+	return version.Latest(versions)
+}
+
+// Additional data of the form for adding changelog entry
+type changelogFormMeta struct {
+	LatestVersion string
+	Error         string
+}
+
+// Changelog entry fields received from a form
+type changelogInput struct {
+	Version    string
+	Changelog  string
+	UpdateDate string
+}
+
+// Read and validate changelog entry fields of the form
+func parseChangelogForm(r *http.Request) (input changelogInput, err error) {
+	// version must be dotted, e.g. 1.2.3, v1.2.3.4, or 2.0.0-beta
+	input.Version = strings.TrimSpace(r.FormValue("version"))
+	if !validateVersion(input.Version) {
+		return input, fmt.Errorf("invalid version: %q", input.Version)
+	}
+
+	// date comes from a date input in YYYY-MM-DD format
+	input.UpdateDate = r.FormValue("updateDate")
+	if _, err = time.Parse(time.DateOnly, input.UpdateDate); err != nil {
+		return input, fmt.Errorf("invalid update date: %q", input.UpdateDate)
+	}
+
+	// release notes can be empty since some authors don't write any
+	input.Changelog = strings.TrimSpace(r.FormValue("changelog"))
+
+	return input, nil
+}
+
+// Render form for adding changelog entry
+func (s *Server) addPluginChangelogForm(w http.ResponseWriter, r *http.Request) {
+	changelog, err := s.db.Queries().GetPluginChangelog(r.Context(), r.PathValue("pluginSlug"))
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// show the latest version as a hint for the new one
+	var metaData changelogFormMeta
+	metaData.LatestVersion, _ = latestVersion(changelog)
+
+	renderPage(w, "add_plugin_changelog", "Add Changelog Entry", nil, metaData)
+}
+
+// Add changelog entry
+func (s *Server) addPluginChangelog(w http.ResponseWriter, r *http.Request) {
+	input, err := parseChangelogForm(r)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	pluginSlug := r.PathValue("pluginSlug")
+	pluginID, err := s.db.Queries().GetPluginID(r.Context(), pluginSlug)
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	// an entry that isn't newer than the latest one is most likely a typo,
+	// so it's accepted only if forced
+	changelog, err := s.db.Queries().GetPluginChangelog(r.Context(), pluginSlug)
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+	latest, hasLatest := latestVersion(changelog)
+	if hasLatest && version.Compare(input.Version, latest) <= 0 && r.FormValue("force") != "yes" {
+		metaData := changelogFormMeta{
+			LatestVersion: latest,
+			Error:         fmt.Sprintf("Version %s is not newer than the latest version %s", input.Version, latest),
+		}
+		// show the form again with the entered values
+		entry := database.PluginChangelog{
+			Version:    input.Version,
+			Changelog:  input.Changelog,
+			UpdateDate: input.UpdateDate,
+		}
+		w.WriteHeader(http.StatusConflict)
+		renderPage(w, "add_plugin_changelog", "Add Changelog Entry", entry, metaData)
+		return
+	}
+
+	// save entry or Internal Server Error
+	_, err = s.db.Queries().AddPluginChangelog(r.Context(), database.AddPluginChangelogParams{
+		PluginID:   pluginID,
+		Version:    input.Version,
+		Changelog:  input.Changelog,
+		UpdateDate: input.UpdateDate,
+	})
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// redirect to plugin page
+	http.Redirect(w, r, fmt.Sprintf("/plugins/%s", pluginSlug), http.StatusFound)
+}
+
+// Render form for updating changelog entry
+func (s *Server) updatePluginChangelogForm(w http.ResponseWriter, r *http.Request) {
+	changelogID, err := strconv.ParseInt(r.PathValue("changelogID"), 10, 64)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	// get changelog entry or Not Found error
+	entry, err := s.db.Queries().GetPluginChangelogEntry(r.Context(), database.GetPluginChangelogEntryParams{
+		ID:   changelogID,
+		Slug: r.PathValue("pluginSlug"),
+	})
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	// show pre-populated form
+	renderPage(w, "add_plugin_changelog", "Update Changelog Entry", entry, nil)
+}
+
+// Update changelog entry
+func (s *Server) updatePluginChangelog(w http.ResponseWriter, r *http.Request) {
+	// check if the retrieved form contains hidden PUT method
+	if r.FormValue("_method") != "PUT" {
+		log.Println("post with no PUT input")
+		notAllowed(w, r)
+		return
+	}
+
+	changelogID, err := strconv.ParseInt(r.PathValue("changelogID"), 10, 64)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+	input, err := parseChangelogForm(r)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	pluginSlug := r.PathValue("pluginSlug")
+	// save entry updates or Internal Server Error
+	_, err = s.db.Queries().UpdatePluginChangelog(r.Context(), database.UpdatePluginChangelogParams{
+		Version:    input.Version,
+		Changelog:  input.Changelog,
+		UpdateDate: input.UpdateDate,
+		ID:         changelogID,
+		Slug:       pluginSlug,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		notFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// redirect to plugin page
+	http.Redirect(w, r, fmt.Sprintf("/plugins/%s", pluginSlug), http.StatusFound)
+}
+
+// Delete changelog entry
+func (s *Server) deletePluginChangelog(w http.ResponseWriter, r *http.Request) {
+	changelogID, err := strconv.ParseInt(r.PathValue("changelogID"), 10, 64)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	pluginSlug := r.PathValue("pluginSlug")
+	// the source of the version goes with it, so does its content
+	// unless other versions use it
+	err = s.db.InTx(r.Context(), func(q *database.Queries) error {
+		_, err := q.DeletePluginChangelog(r.Context(), database.DeletePluginChangelogParams{
+			ID:   changelogID,
+			Slug: pluginSlug,
+		})
+		if err != nil {
+			return err
+		}
+		return q.DeleteUnusedSourceBlobs(r.Context())
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		notFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
 
-	renderPage(w, "plugin_changelogs", "", changelog, nil)
+	// redirect to plugin page on success with HTMX
+	w.Header().Set("HX-Redirect", fmt.Sprintf("/plugins/%s", pluginSlug))
+	w.WriteHeader(http.StatusNoContent)
 }
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"adminrust/internal/database"
+)
+
+func TestParseChangelogForm(t *testing.T) {
+	tests := []struct {
+		name       string
+		version    string
+		updateDate string
+		isValid    bool
+	}{
+		{name: "three parts", version: "1.2.3", updateDate: "2025-03-01", isValid: true},
+		{name: "four parts", version: "1.2.3.4", updateDate: "2025-03-01", isValid: true},
+		{name: "pre-release", version: "2.0.0-beta", updateDate: "2025-03-01", isValid: true},
+		{name: "surrounding spaces", version: " 1.0.0 ", updateDate: "2025-03-01", isValid: true},
+		{name: "single part", version: "1", updateDate: "2025-03-01", isValid: true},
+		{name: "five parts", version: "1.2.3.4.5", updateDate: "2025-03-01", isValid: false},
+		{name: "prefixed", version: "v1.2.3", updateDate: "2025-03-01", isValid: true},
+		{name: "empty pre-release identifier", version: "1.2.3-.", updateDate: "2025-03-01", isValid: false},
+		{name: "doubled pre-release dot", version: "1.2-beta..1", updateDate: "2025-03-01", isValid: false},
+		{name: "empty version", version: "", updateDate: "2025-03-01", isValid: false},
+		{name: "wrong date format", version: "1.2.3", updateDate: "01.03.2025", isValid: false},
+		{name: "impossible date", version: "1.2.3", updateDate: "2025-02-30", isValid: false},
+		{name: "empty date", version: "1.2.3", updateDate: "", isValid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			form := url.Values{
+				"version":    {test.version},
+				"updateDate": {test.updateDate},
+				"changelog":  {"Fixed things"},
+			}
+			r := httptest.NewRequest("POST", "/plugins/kits/changelog/add", strings.NewReader(form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			_, err := parseChangelogForm(r)
+			if (err == nil) != test.isValid {
+				t.Errorf("parseChangelogForm() error = %v, want valid %v", err, test.isValid)
+			}
+		})
+	}
+}
+
+func TestSortChangelog(t *testing.T) {
+	// entries come from DB ordered by update date
+	changelog := []database.PluginChangelog{
+		{ID: 1, Version: "1.10.0", UpdateDate: "2025-03-01"},
+		{ID: 2, Version: "1.9.0", UpdateDate: "2025-03-01"},
+		{ID: 3, Version: "2.0.0-beta", UpdateDate: "2025-02-01"},
+		{ID: 4, Version: "2.0.0", UpdateDate: "2025-01-01"},
+		{ID: 5, Version: "1.10.0", UpdateDate: "2024-12-01"},
+	}
+
+	sortChangelog(changelog)
+
+	expectedIDs := []int64{4, 3, 1, 5, 2}
+	for i, entry := range changelog {
+		if entry.ID != expectedIDs[i] {
+			t.Fatalf("sortChangelog() entry %d = %+v, want ID %d", i, entry, expectedIDs[i])
+		}
+	}
+
+	latest, ok := latestVersion(changelog)
+	if !ok || latest != "2.0.0" {
+		t.Errorf("latestVersion() = %q, %v, want 2.0.0", latest, ok)
+	}
+}
+
+func TestPluginChangelogHandlersNotFound(t *testing.T) {
+	loadTestTemplates(t)
+	ctx := context.Background()
+	s := &Server{db: newTestDB(t)}
+	q := s.db.Queries()
+
+	pluginOrigin, err := q.AddOrigin(ctx, database.AddOriginParams{
+		Name: "uMod", Slug: "umod", Url: "https://umod.org", PathToPluginList: "/plugins",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entryID string
+	for _, slug := range []string{"kits", "teleport"} {
+		plugin, err := q.AddPlugin(ctx, database.AddPluginParams{
+			Name: slug, Slug: slug, Url: "https://umod.org/plugins/" + slug, OriginID: pluginOrigin.ID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry, err := q.AddPluginChangelog(ctx, database.AddPluginChangelogParams{
+			PluginID: plugin.ID, Version: "1.0.0", Changelog: "Released", UpdateDate: "2025-03-01",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if slug == "kits" {
+			entryID = strconv.FormatInt(entry.ID, 10)
+		}
+	}
+
+	send := func(handler http.HandlerFunc, method, slug, changelogID string, form url.Values) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/plugins/"+slug+"/changelog/"+changelogID, strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.SetPathValue("pluginSlug", slug)
+		r.SetPathValue("changelogID", changelogID)
+		w := httptest.NewRecorder()
+		handler(w, r)
+		return w
+	}
+	form := url.Values{"_method": {"PUT"}, "version": {"1.0.1"}, "updateDate": {"2025-03-02"}, "changelog": {"Fixes"}}
+
+	// entries of other plugins and missing ones can't be changed
+	for _, target := range []struct{ slug, changelogID string }{{"teleport", entryID}, {"kits", "999"}} {
+		if w := send(s.updatePluginChangelog, "POST", target.slug, target.changelogID, form); w.Code != http.StatusNotFound {
+			t.Errorf("updatePluginChangelog(%s, %s) status = %d, want %d", target.slug, target.changelogID, w.Code, http.StatusNotFound)
+		}
+		if w := send(s.deletePluginChangelog, "DELETE", target.slug, target.changelogID, nil); w.Code != http.StatusNotFound {
+			t.Errorf("deletePluginChangelog(%s, %s) status = %d, want %d", target.slug, target.changelogID, w.Code, http.StatusNotFound)
+		}
+	}
+
+	if w := send(s.deletePluginChangelog, "DELETE", "kits", entryID, nil); w.Code != http.StatusNoContent {
+		t.Errorf("deletePluginChangelog() status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
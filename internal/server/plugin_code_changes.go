@@ -0,0 +1,228 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"adminrust/internal/database"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Routes of manual code changes made to plugin sources
+func (s *Server) registerPluginCodeChangeRoutes(r chi.Router) {
+	r.Route("/edits", func(r chi.Router) {
+		r.Get("/", s.getPluginCodeChanges)
+		// adding
+		r.Get("/add", s.addPluginCodeChangeForm)
+		r.Post("/add", s.addPluginCodeChange)
+		// editing
+		r.Get("/edit/{changeID:[0-9]+}", s.updatePluginCodeChangeForm)
+		r.Post("/edit/{changeID:[0-9]+}", s.updatePluginCodeChange)
+		// deleting
+		r.Delete("/{changeID:[0-9]+}", s.deletePluginCodeChange)
+		// recording from patched source
+		r.Get("/record", s.recordPluginCodeHunksForm)
+		r.Post("/record", s.recordPluginCodeHunks)
+	})
+}
+
+// Fields of the code change form
+type codeChangeForm struct {
+	ModifiedPart string
+	RowStart     int64
+	RowEnd       int64
+	Comment      string
+	IsRelevant   int64
+}
+
+// Retrieve code change list
+func (s *Server) getPluginCodeChanges(w http.ResponseWriter, r *http.Request) {
+	pluginSlug := r.PathValue("pluginSlug")
+
+	changes, err := s.db.Queries().GetPluginCodeChanges(r.Context(), pluginSlug)
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	metaData := struct{ CurrentURL string }{
+		CurrentURL: fmt.Sprintf("/plugins/%s/edits", pluginSlug),
+	}
+
+	renderPage(w, "plugin_code_changes", "", changes, metaData)
+}
+
+// Read and validate fields of the code change form
+func parseCodeChangeForm(r *http.Request) (change codeChangeForm, err error) {
+	change.ModifiedPart = strings.TrimRight(r.FormValue("modifiedPart"), " \r\n\t")
+	if strings.TrimSpace(change.ModifiedPart) == "" {
+		return change, errors.New("empty modified part")
+	}
+
+	// rows are 1-based and the range is inclusive
+	change.RowStart, err = strconv.ParseInt(r.FormValue("rowStart"), 10, 64)
+	if err != nil {
+		return change, err
+	}
+	change.RowEnd, err = strconv.ParseInt(r.FormValue("rowEnd"), 10, 64)
+	if err != nil {
+		return change, err
+	}
+	if change.RowStart < 1 || change.RowEnd < change.RowStart {
+		return change, fmt.Errorf("invalid row range: %d-%d", change.RowStart, change.RowEnd)
+	}
+
+	change.Comment = strings.TrimSpace(r.FormValue("comment"))
+	if r.FormValue("isRelevant") == "yes" {
+		change.IsRelevant = 1
+	}
+
+	return change, nil
+}
+
+// Render form for adding code change
+func (s *Server) addPluginCodeChangeForm(w http.ResponseWriter, r *http.Request) {
+	renderPage(w, "add_plugin_code_change", "Add Code Edit", nil, nil)
+}
+
+// Add code change
+func (s *Server) addPluginCodeChange(w http.ResponseWriter, r *http.Request) {
+	change, err := parseCodeChangeForm(r)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	pluginSlug := r.PathValue("pluginSlug")
+	pluginID, err := s.db.Queries().GetPluginID(r.Context(), pluginSlug)
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	_, err = s.db.Queries().AddPluginCodeChange(r.Context(), database.AddPluginCodeChangeParams{
+		PluginID:     pluginID,
+		ModifiedPart: change.ModifiedPart,
+		RowStart:     change.RowStart,
+		RowEnd:       change.RowEnd,
+		Comment:      change.Comment,
+		IsRelevant:   change.IsRelevant,
+	})
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// redirect to plugin page
+	http.Redirect(w, r, fmt.Sprintf("/plugins/%s", pluginSlug), http.StatusFound)
+}
+
+// Render form for updating code change
+func (s *Server) updatePluginCodeChangeForm(w http.ResponseWriter, r *http.Request) {
+	changeID, err := strconv.ParseInt(r.PathValue("changeID"), 10, 64)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	// get code change or Not Found error
+	change, err := s.db.Queries().GetPluginCodeChange(r.Context(), database.GetPluginCodeChangeParams{
+		ID:   changeID,
+		Slug: r.PathValue("pluginSlug"),
+	})
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	// show pre-populated form
+	renderPage(w, "add_plugin_code_change", "Update Code Edit", change, nil)
+}
+
+// Update code change
+func (s *Server) updatePluginCodeChange(w http.ResponseWriter, r *http.Request) {
+	// check if the retrieved form contains hidden PUT method
+	if r.FormValue("_method") != "PUT" {
+		log.Println("post with no PUT input")
+		notAllowed(w, r)
+		return
+	}
+
+	changeID, err := strconv.ParseInt(r.PathValue("changeID"), 10, 64)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+	change, err := parseCodeChangeForm(r)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	pluginSlug := r.PathValue("pluginSlug")
+	// save code change updates, the change must belong to the plugin
+	_, err = s.db.Queries().UpdatePluginCodeChange(r.Context(), database.UpdatePluginCodeChangeParams{
+		ModifiedPart: change.ModifiedPart,
+		RowStart:     change.RowStart,
+		RowEnd:       change.RowEnd,
+		Comment:      change.Comment,
+		IsRelevant:   change.IsRelevant,
+		ID:           changeID,
+		Slug:         pluginSlug,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		notFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// redirect to plugin page
+	http.Redirect(w, r, fmt.Sprintf("/plugins/%s", pluginSlug), http.StatusFound)
+}
+
+// Delete code change
+func (s *Server) deletePluginCodeChange(w http.ResponseWriter, r *http.Request) {
+	changeID, err := strconv.ParseInt(r.PathValue("changeID"), 10, 64)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	pluginSlug := r.PathValue("pluginSlug")
+	_, err = s.db.Queries().DeletePluginCodeChange(r.Context(), database.DeletePluginCodeChangeParams{
+		ID:   changeID,
+		Slug: pluginSlug,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		notFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// redirect to plugin page on success with HTMX
+	w.Header().Set("HX-Redirect", fmt.Sprintf("/plugins/%s", pluginSlug))
+	w.WriteHeader(http.StatusNoContent)
+}
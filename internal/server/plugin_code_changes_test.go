@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"adminrust/internal/database"
+)
+
+func TestPluginCodeChangeHandlers(t *testing.T) {
+	loadTestTemplates(t)
+	ctx := context.Background()
+	s := &Server{db: newTestDB(t)}
+	q := s.db.Queries()
+
+	pluginOrigin, err := q.AddOrigin(ctx, database.AddOriginParams{
+		Name: "uMod", Slug: "umod", Url: "https://umod.org", PathToPluginList: "/plugins",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, slug := range []string{"kits", "teleport"} {
+		_, err = q.AddPlugin(ctx, database.AddPluginParams{
+			Name: slug, Slug: slug, Url: "https://umod.org/plugins/" + slug, OriginID: pluginOrigin.ID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// send a form to the handler of the plugin's code changes
+	send := func(handler http.HandlerFunc, method, slug, changeID string, form url.Values) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/plugins/"+slug+"/edits", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.SetPathValue("pluginSlug", slug)
+		r.SetPathValue("changeID", changeID)
+		w := httptest.NewRecorder()
+		handler(w, r)
+		return w
+	}
+
+	tests := []struct {
+		name         string
+		form         url.Values
+		expectedCode int
+	}{
+		{
+			name:         "valid",
+			form:         url.Values{"modifiedPart": {"int limit = 5;"}, "rowStart": {"10"}, "rowEnd": {"10"}, "isRelevant": {"yes"}},
+			expectedCode: http.StatusFound,
+		},
+		{
+			name:         "empty code",
+			form:         url.Values{"modifiedPart": {"  \n"}, "rowStart": {"10"}, "rowEnd": {"10"}},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "reversed rows",
+			form:         url.Values{"modifiedPart": {"int limit = 5;"}, "rowStart": {"10"}, "rowEnd": {"9"}},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "zero row",
+			form:         url.Values{"modifiedPart": {"int limit = 5;"}, "rowStart": {"0"}, "rowEnd": {"1"}},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := send(s.addPluginCodeChange, "POST", "kits", "", test.form)
+			if w.Code != test.expectedCode {
+				t.Errorf("addPluginCodeChange() status = %d, want %d", w.Code, test.expectedCode)
+			}
+		})
+	}
+
+	changes, err := q.GetPluginCodeChanges(ctx, "kits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].IsRelevant != 1 {
+		t.Fatalf("GetPluginCodeChanges() = %+v, want 1 relevant change", changes)
+	}
+	changeID := strconv.FormatInt(changes[0].ID, 10)
+
+	// unchecked relevance marks the change as outdated
+	w := send(s.updatePluginCodeChange, "POST", "kits", changeID, url.Values{
+		"_method": {"PUT"}, "modifiedPart": {"int limit = 10;"}, "rowStart": {"10"}, "rowEnd": {"11"}, "comment": {"Bigger limit"},
+	})
+	change, err := q.GetPluginCodeChange(ctx, database.GetPluginCodeChangeParams{ID: changes[0].ID, Slug: "kits"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusFound || change.RowEnd != 11 || change.IsRelevant != 0 || change.Comment != "Bigger limit" {
+		t.Errorf("updatePluginCodeChange() status = %d, change = %+v", w.Code, change)
+	}
+
+	// changes of other plugins are not accessible
+	w = send(s.updatePluginCodeChange, "POST", "teleport", changeID, url.Values{
+		"_method": {"PUT"}, "modifiedPart": {"x"}, "rowStart": {"1"}, "rowEnd": {"1"},
+	})
+	if w.Code != http.StatusNotFound {
+		t.Errorf("updatePluginCodeChange() of another plugin status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	w = send(s.deletePluginCodeChange, "DELETE", "teleport", changeID, nil)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("deletePluginCodeChange() of another plugin status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	w = send(s.deletePluginCodeChange, "DELETE", "kits", changeID, nil)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("deletePluginCodeChange() status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
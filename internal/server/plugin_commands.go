@@ -2,9 +2,14 @@ package server
 
 import (
 	"adminrust/internal/database"
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
@@ -17,6 +22,16 @@ func (s *Server) registerPluginCmdRoutes(r chi.Router) {
 
 		r.Get("/add", s.addPluginCommandsForm)
 		r.Post("/add", s.addPluginCommands)
+
+		r.Get("/edit/{commandID:[0-9]+}", s.updatePluginCommandForm)
+		r.Post("/edit/{commandID:[0-9]+}", s.updatePluginCommand)
+
+		r.Delete("/{commandID:[0-9]+}", s.deletePluginCommand)
+
+		// extracting from plugin source
+		r.Get("/analyze", s.analyzePluginSourceForm)
+		r.Post("/analyze", s.analyzePluginSource)
+		r.Post("/analyze/save", s.saveAnalyzedCommands)
 	})
 }
 
@@ -31,7 +46,11 @@ func (s *Server) getPluginCommands(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	renderPage(w, "plugin_commands", "", commands, nil)
+	metaData := struct{ CurrentURL string }{
+		CurrentURL: fmt.Sprintf("/plugins/%s/commands", pluginSlug),
+	}
+
+	renderPage(w, "plugin_commands", "", commands, metaData)
 }
 
 // Render a page with plugin commands addition form
@@ -54,6 +73,7 @@ func (s *Server) addPluginCommands(w http.ResponseWriter, r *http.Request) {
 	rawCommands := r.FormValue("commands")
 	if rawCommands == "" {
 		log.Println("Empty command list")
+		badRequest(w)
 		return
 	}
 	descrSep := r.FormValue("descr-sep")
@@ -65,48 +85,155 @@ func (s *Server) addPluginCommands(w http.ResponseWriter, r *http.Request) {
 		cmdSep = "\n"
 	}
 
-	// parse and convert commands
-	commandsMap, err := parseCommands(rawCommands, descrSep, cmdSep)
+	// parse commands keeping their order
+	commands, err := parseCommands(rawCommands, descrSep, cmdSep)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	// save commands to DB or Internal Server Error
+	err = s.savePluginCommands(r.Context(), pluginID, commands, r.FormValue("replaceAll") == "yes")
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// redirect to plugin page
+	http.Redirect(w, r, fmt.Sprintf("/plugins/%s", pluginSlug), http.StatusFound)
+}
+
+// Save commands in one transaction. Existing commands are removed first
+// in replace mode, otherwise new ones are appended after them.
+func (s *Server) savePluginCommands(ctx context.Context, pluginID int64, commands []database.AddPluginCommandsParams, replaceAll bool) error {
+	return s.db.InTx(ctx, func(q *database.Queries) error {
+		var lastPosition int64
+		if replaceAll {
+			if err := q.DeletePluginCommands(ctx, pluginID); err != nil {
+				return err
+			}
+		} else {
+			position, err := q.GetLastPluginCommandPosition(ctx, pluginID)
+			if err != nil {
+				return err
+			}
+			lastPosition = position
+		}
+
+		for i := range commands {
+			commands[i].PluginID = pluginID
+			commands[i].Position += lastPosition + 1
+		}
+		_, err := q.AddPluginCommands(ctx, commands)
+		return err
+	})
+}
+
+// Render form for updating a single command
+func (s *Server) updatePluginCommandForm(w http.ResponseWriter, r *http.Request) {
+	commandID, err := strconv.ParseInt(r.PathValue("commandID"), 10, 64)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	// get command or Not Found error
+	command, err := s.db.Queries().GetPluginCommand(r.Context(), database.GetPluginCommandParams{
+		ID:   commandID,
+		Slug: r.PathValue("pluginSlug"),
+	})
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	// show pre-populated form
+	renderPage(w, "edit_plugin_cmd", "Update Plugin Command", command, struct{ CmdTypes []string }{cmdTypes})
+}
+
+// Update a single command
+func (s *Server) updatePluginCommand(w http.ResponseWriter, r *http.Request) {
+	// check if the retrieved form contains hidden PUT method
+	if r.FormValue("_method") != "PUT" {
+		log.Println("post with no PUT input")
+		notAllowed(w, r)
+		return
+	}
+
+	commandID, err := strconv.ParseInt(r.PathValue("commandID"), 10, 64)
 	if err != nil {
 		log.Println(err)
+		badRequest(w)
 		return
 	}
-	var commandArgs []database.AddPluginCommandsParams
-	for cmd, descr := range commandsMap {
-		commandArgs = append(commandArgs, database.AddPluginCommandsParams{
-			PluginID:    pluginID,
-			Command:     cmd,
-			Description: descr,
-		})
+	command := strings.TrimSpace(r.FormValue("command"))
+	if command == "" {
+		log.Println("Empty command")
+		badRequest(w)
+		return
+	}
+	cmdType := r.FormValue("cmdType")
+	if !slices.Contains(cmdTypes, cmdType) {
+		log.Printf("Unknown command type: %q\n", cmdType)
+		badRequest(w)
+		return
 	}
 
-	// save commands to DB
-	_, err = s.db.Queries().AddPluginCommands(r.Context(), commandArgs)
+	pluginSlug := r.PathValue("pluginSlug")
+	// save command updates, the command must belong to the plugin
+	_, err = s.db.Queries().UpdatePluginCommand(r.Context(), database.UpdatePluginCommandParams{
+		Command:     command,
+		CmdType:     cmdType,
+		Args:        strings.TrimSpace(r.FormValue("args")),
+		Aliases:     strings.TrimSpace(r.FormValue("aliases")),
+		Permission:  strings.TrimSpace(r.FormValue("permission")),
+		Description: strings.TrimSpace(r.FormValue("description")),
+		ID:          commandID,
+		Slug:        pluginSlug,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		notFound(w, r)
+		return
+	}
 	if err != nil {
 		log.Println(err)
 		internalServerErr(w)
+		return
 	}
+
+	// redirect to plugin page
+	http.Redirect(w, r, fmt.Sprintf("/plugins/%s", pluginSlug), http.StatusFound)
 }
 
-// Split input string on rows then split each row on command and its description
-func parseCommands(rawCommands, descrSep, cmdSep string) (commands map[string]string, err error) {
-	commandRows := strings.Split(rawCommands, cmdSep)
-	if len(commandRows) == 1 {
-		err = fmt.Errorf("wrong command separator: <%s> or nothing to split", cmdSep)
+// Delete a single command
+func (s *Server) deletePluginCommand(w http.ResponseWriter, r *http.Request) {
+	commandID, err := strconv.ParseInt(r.PathValue("commandID"), 10, 64)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
 		return
 	}
 
-	// extract and save commands and their descriptions
-	commands = map[string]string{}
-	for _, row := range commandRows {
-		rowEls := strings.SplitN(row, descrSep, 2)
-		if len(rowEls) == 2 {
-			// clean and save command and description
-			cmd := strings.Trim(rowEls[0], " ")
-			descr := strings.Trim(rowEls[1], " ")
-			commands[cmd] = descr
-		}
+	pluginSlug := r.PathValue("pluginSlug")
+	_, err = s.db.Queries().DeletePluginCommand(r.Context(), database.DeletePluginCommandParams{
+		ID:   commandID,
+		Slug: pluginSlug,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		notFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
 	}
 
-	return commands, err
+	// redirect to plugin page on success with HTMX
+	w.Header().Set("HX-Redirect", fmt.Sprintf("/plugins/%s", pluginSlug))
+	w.WriteHeader(http.StatusNoContent)
 }
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"adminrust/internal/database"
+)
+
+func TestPluginCommandHandlers(t *testing.T) {
+	loadTestTemplates(t)
+	ctx := context.Background()
+	s := &Server{db: newTestDB(t)}
+	q := s.db.Queries()
+
+	pluginOrigin, err := q.AddOrigin(ctx, database.AddOriginParams{
+		Name: "uMod", Slug: "umod", Url: "https://umod.org", PathToPluginList: "/plugins",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, slug := range []string{"kits", "teleport"} {
+		_, err = q.AddPlugin(ctx, database.AddPluginParams{
+			Name: slug, Slug: slug, Url: "https://umod.org/plugins/" + slug, OriginID: pluginOrigin.ID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// send a form to the handler of the plugin's commands
+	send := func(handler http.HandlerFunc, method, slug, commandID string, form url.Values) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/plugins/"+slug+"/commands", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.SetPathValue("pluginSlug", slug)
+		r.SetPathValue("commandID", commandID)
+		w := httptest.NewRecorder()
+		handler(w, r)
+		return w
+	}
+	commandsOf := func(slug string) []database.PluginCommand {
+		commands, err := q.GetPluginCommands(ctx, slug)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return commands
+	}
+
+	w := send(s.addPluginCommands, "POST", "kits", "", url.Values{"commands": {"/kit - Show kits\n/kits - List kits"}})
+	if w.Code != http.StatusFound || len(commandsOf("kits")) != 2 {
+		t.Fatalf("addPluginCommands() status = %d, commands = %+v, want 2 commands", w.Code, commandsOf("kits"))
+	}
+
+	// replace mode removes existing commands
+	w = send(s.addPluginCommands, "POST", "kits", "", url.Values{
+		"commands":   {"/kit - Claim kit\n/kit.reset - Reset kits"},
+		"replaceAll": {"yes"},
+	})
+	commands := commandsOf("kits")
+	if w.Code != http.StatusFound || len(commands) != 2 {
+		t.Fatalf("replacing addPluginCommands() status = %d, commands = %+v, want 2 commands", w.Code, commands)
+	}
+	for _, command := range commands {
+		if command.Command == "/kits" {
+			t.Errorf("replaced command %q still exists", command.Command)
+		}
+	}
+
+	commandID := strconv.FormatInt(commands[0].ID, 10)
+	w = send(s.updatePluginCommand, "POST", "kits", commandID, url.Values{
+		"_method":     {"PUT"},
+		"command":     {"/kit"},
+		"cmdType":     {"chat"},
+		"args":        {"<name>"},
+		"description": {"Claim a kit"},
+	})
+	command, err := q.GetPluginCommand(ctx, database.GetPluginCommandParams{ID: commands[0].ID, Slug: "kits"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusFound || command.Description != "Claim a kit" || command.Args != "<name>" {
+		t.Errorf("updatePluginCommand() status = %d, command = %+v, want updated description", w.Code, command)
+	}
+
+	// commands of other plugins can't be changed through the plugin
+	w = send(s.deletePluginCommand, "DELETE", "teleport", commandID, nil)
+	if w.Code != http.StatusNotFound || len(commandsOf("kits")) != 2 {
+		t.Errorf("deletePluginCommand() of another plugin status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	w = send(s.deletePluginCommand, "DELETE", "kits", commandID, nil)
+	if w.Code != http.StatusNoContent || len(commandsOf("kits")) != 1 {
+		t.Errorf("deletePluginCommand() status = %d, commands = %+v, want 1 command", w.Code, commandsOf("kits"))
+	}
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"adminrust/internal/database"
+	"adminrust/internal/jsondoc"
+)
+
+// Result of comparing the stored config with a new default one
+type configDrift struct {
+	Defaults    string
+	DropRemoved bool
+	Changes     []jsondoc.Change
+	Merged      string
+}
+
+// Render form for checking stored config against a new default config
+func (s *Server) pluginCfgDriftForm(w http.ResponseWriter, r *http.Request) {
+	pluginSlug := r.PathValue("pluginSlug")
+	if _, err := s.db.Queries().GetPluginConfig(r.Context(), pluginSlug); err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	metaData := struct{ PluginURL string }{
+		PluginURL: fmt.Sprintf("/plugins/%s", pluginSlug),
+	}
+
+	renderPage(w, "plugin_config_drift", "Configuration Drift", nil, metaData)
+}
+
+// List keys added, removed or changed in the new default config
+// and prepare the merged config for saving
+func (s *Server) diffPluginCfg(w http.ResponseWriter, r *http.Request) {
+	pluginSlug := r.PathValue("pluginSlug")
+	config, err := s.db.Queries().GetPluginConfig(r.Context(), pluginSlug)
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	drift := configDrift{
+		Defaults:    r.FormValue("defaults"),
+		DropRemoved: r.FormValue("dropRemoved") == "yes",
+	}
+	defaults, err := jsondoc.Parse([]byte(drift.Defaults))
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+	stored, err := jsondoc.Parse([]byte(config.ConfigJson))
+	if err != nil {
+		log.Println(err)
+		errorHandler(w, http.StatusUnprocessableEntity, "Stored configuration isn't valid JSON")
+		return
+	}
+
+	drift.Changes = jsondoc.Diff(stored, defaults)
+	merged, err := jsondoc.Marshal(jsondoc.Merge(stored, defaults, drift.DropRemoved))
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+	drift.Merged = string(merged)
+
+	metaData := struct{ PluginURL string }{
+		PluginURL: fmt.Sprintf("/plugins/%s", pluginSlug),
+	}
+
+	renderPage(w, "plugin_config_drift", "Configuration Drift", drift, metaData)
+}
+
+// Save the merged config
+func (s *Server) mergePluginCfg(w http.ResponseWriter, r *http.Request) {
+	// check if the retrieved form contains hidden PUT method
+	if r.FormValue("_method") != "PUT" {
+		log.Println("post with no PUT input")
+		notAllowed(w, r)
+		return
+	}
+
+	// the merged config could be edited before saving
+	receivedCfg := r.FormValue("config")
+	if _, err := jsondoc.Parse([]byte(receivedCfg)); err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	pluginSlug := r.PathValue("pluginSlug")
+	_, err := s.db.Queries().UpdatePluginConfig(r.Context(), database.UpdatePluginConfigParams{
+		ConfigJson: receivedCfg,
+		Slug:       pluginSlug,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		notFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// redirect to plugin page
+	http.Redirect(w, r, fmt.Sprintf("/plugins/%s", pluginSlug), http.StatusFound)
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"adminrust/internal/database"
+)
+
+func TestPluginCfgDriftHandlers(t *testing.T) {
+	loadTestTemplates(t)
+	ctx := context.Background()
+	s := &Server{db: newTestDB(t)}
+	q := s.db.Queries()
+
+	pluginOrigin, err := q.AddOrigin(ctx, database.AddOriginParams{
+		Name: "uMod", Slug: "umod", Url: "https://umod.org", PathToPluginList: "/plugins",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, slug := range []string{"kits", "teleport"} {
+		_, err = q.AddPlugin(ctx, database.AddPluginParams{
+			Name: slug, Slug: slug, Url: "https://umod.org/plugins/" + slug, OriginID: pluginOrigin.ID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err = q.AddPluginConfig(ctx, database.AddPluginConfigParams{
+		ConfigJson: `{"Limit": 5, "Old": true}`,
+		Slug:       "kits",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// send a form to the handler of the plugin's config drift
+	send := func(handler http.HandlerFunc, slug string, form url.Values) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/plugins/"+slug+"/config/drift", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.SetPathValue("pluginSlug", slug)
+		w := httptest.NewRecorder()
+		handler(w, r)
+		return w
+	}
+
+	tests := []struct {
+		name          string
+		slug          string
+		form          url.Values
+		expectedCode  int
+		expectedParts []string
+	}{
+		{
+			name:          "changes listed",
+			slug:          "kits",
+			form:          url.Values{"defaults": {`{"Limit": 3, "Cooldown": 60}`}},
+			expectedCode:  http.StatusOK,
+			expectedParts: []string{"Added", "Cooldown", "Removed", "&#34;Old&#34;: true"},
+		},
+		{
+			name:          "removed dropped",
+			slug:          "kits",
+			form:          url.Values{"defaults": {`{"Limit": 3, "Cooldown": 60}`}, "dropRemoved": {"yes"}},
+			expectedCode:  http.StatusOK,
+			expectedParts: []string{"&#34;Limit&#34;: 5,\n  &#34;Cooldown&#34;: 60"},
+		},
+		{
+			name:          "no drift",
+			slug:          "kits",
+			form:          url.Values{"defaults": {`{"Limit": 1, "Old": false}`}},
+			expectedCode:  http.StatusOK,
+			expectedParts: []string{"matches the new default configuration"},
+		},
+		{name: "invalid defaults", slug: "kits", form: url.Values{"defaults": {"{"}}, expectedCode: http.StatusBadRequest},
+		{name: "no stored config", slug: "teleport", form: url.Values{"defaults": {"{}"}}, expectedCode: http.StatusNotFound},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := send(s.diffPluginCfg, test.slug, test.form)
+			if w.Code != test.expectedCode {
+				t.Fatalf("diffPluginCfg() status = %d, want %d", w.Code, test.expectedCode)
+			}
+			for _, part := range test.expectedParts {
+				if !strings.Contains(w.Body.String(), part) {
+					t.Errorf("diffPluginCfg() body misses %q", part)
+				}
+			}
+		})
+	}
+
+	// the merged config replaces the stored one
+	merged := "{\n  \"Limit\": 5,\n  \"Cooldown\": 60\n}"
+	saveTests := []struct {
+		name         string
+		slug         string
+		form         url.Values
+		expectedCode int
+	}{
+		{name: "no PUT", slug: "kits", form: url.Values{"config": {merged}}, expectedCode: http.StatusMethodNotAllowed},
+		{name: "invalid JSON", slug: "kits", form: url.Values{"_method": {"PUT"}, "config": {"{"}}, expectedCode: http.StatusBadRequest},
+		{name: "no stored config", slug: "teleport", form: url.Values{"_method": {"PUT"}, "config": {merged}}, expectedCode: http.StatusNotFound},
+		{name: "saved", slug: "kits", form: url.Values{"_method": {"PUT"}, "config": {merged}}, expectedCode: http.StatusFound},
+	}
+	for _, test := range saveTests {
+		t.Run(test.name, func(t *testing.T) {
+			w := send(s.mergePluginCfg, test.slug, test.form)
+			if w.Code != test.expectedCode {
+				t.Errorf("mergePluginCfg() status = %d, want %d", w.Code, test.expectedCode)
+			}
+		})
+	}
+
+	config, err := q.GetPluginConfig(ctx, "kits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.ConfigJson != merged {
+		t.Errorf("stored config = %q, want %q", config.ConfigJson, merged)
+	}
+}
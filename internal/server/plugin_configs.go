@@ -23,6 +23,10 @@ func (s *Server) registerPluginCfgRoutes(r chi.Router) {
 		// editing
 		r.Get("/edit", s.updatePluginCfgForm)
 		r.Post("/edit", s.updatePluginCfg)
+		// comparing with a new default config
+		r.Get("/drift", s.pluginCfgDriftForm)
+		r.Post("/drift", s.diffPluginCfg)
+		r.Post("/drift/save", s.mergePluginCfg)
 	})
 }
 
@@ -39,10 +43,12 @@ func (s *Server) getPluginCfg(w http.ResponseWriter, r *http.Request) {
 		AddURL     string
 		CurrentURL string
 		EditURL    string
+		DriftURL   string
 	}{
 		AddURL:     fmt.Sprintf("%s/add", r.URL.Path),
 		CurrentURL: r.URL.Path,
 		EditURL:    fmt.Sprintf("%s/edit", r.URL.Path),
+		DriftURL:   fmt.Sprintf("%s/drift", r.URL.Path),
 	}
 
 	// render populated page
@@ -0,0 +1,319 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"adminrust/internal/database"
+	"adminrust/internal/imaging"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Upload limits: the size of a single image and of the whole request
+const (
+	maxImageSize   = 5 << 20
+	maxImageUpload = 20 << 20
+)
+
+// Side of the square thumbnails fit into
+const thumbnailSide = 480
+
+// Image IDs are reused after deleting the newest image, so browsers
+// revalidate cached images by their ETags
+const imageCacheControl = "public, no-cache"
+
+// Image-related routes
+func (s *Server) registerPluginImageRoutes(r chi.Router) {
+	r.Route("/images", func(r chi.Router) {
+		r.Get("/", s.getPluginImages)
+		// uploading
+		r.Get("/upload", s.uploadPluginImagesForm)
+		r.Post("/upload", s.uploadPluginImages)
+		// serving
+		r.Get("/{imageID:[0-9]+}", s.getPluginImage)
+		r.Get("/{imageID:[0-9]+}/thumb", s.getPluginImageThumbnail)
+		// choosing cover and deleting
+		r.Post("/{imageID:[0-9]+}/cover", s.setPluginCover)
+		r.Delete("/{imageID:[0-9]+}", s.deletePluginImage)
+	})
+}
+
+// Uploaded image ready for saving
+type uploadedImage struct {
+	fileName          string
+	data              []byte
+	info              imaging.Info
+	thumbnail         []byte
+	thumbnailMimeType string
+}
+
+// Retrieve image list, the cover goes first
+func (s *Server) getPluginImages(w http.ResponseWriter, r *http.Request) {
+	pluginSlug := r.PathValue("pluginSlug")
+
+	images, err := s.db.Queries().GetPluginImages(r.Context(), pluginSlug)
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	metaData := struct{ CurrentURL string }{
+		CurrentURL: fmt.Sprintf("/plugins/%s/images", pluginSlug),
+	}
+
+	renderPage(w, "plugin_images", "", images, metaData)
+}
+
+// Render form for uploading images
+func (s *Server) uploadPluginImagesForm(w http.ResponseWriter, r *http.Request) {
+	metaData := struct{ MaxSizeMB int }{maxImageSize >> 20}
+
+	renderPage(w, "upload_plugin_images", "Upload Images", nil, metaData)
+}
+
+// Read an uploaded image checking its size and type by content
+func readUploadedImage(file io.Reader, fileName string) (image uploadedImage, err error) {
+	image.fileName = fileName
+	image.data, err = io.ReadAll(io.LimitReader(file, maxImageSize+1))
+	if err != nil {
+		return image, err
+	}
+	if len(image.data) > maxImageSize {
+		return image, fmt.Errorf("image %s exceeds %d bytes", fileName, maxImageSize)
+	}
+
+	image.info, err = imaging.Inspect(image.data)
+	if err != nil {
+		return image, fmt.Errorf("image %s: %w", fileName, err)
+	}
+	image.thumbnail, image.thumbnailMimeType, err = imaging.Thumbnail(image.data, image.info, thumbnailSide)
+	if err != nil {
+		return image, fmt.Errorf("thumbnail of %s: %w", fileName, err)
+	}
+
+	return image, nil
+}
+
+// Save uploaded images, the first image becomes the cover if there's none
+func (s *Server) uploadPluginImages(w http.ResponseWriter, r *http.Request) {
+	pluginSlug := r.PathValue("pluginSlug")
+	pluginID, err := s.db.Queries().GetPluginID(r.Context(), pluginSlug)
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	allowSlowUpload(w)
+	r.Body = http.MaxBytesReader(w, r.Body, maxImageUpload)
+	if err = r.ParseMultipartForm(maxImageUpload); err != nil {
+		log.Println(err)
+		errorHandler(w, http.StatusRequestEntityTooLarge, "Upload is too large")
+		return
+	}
+	headers := r.MultipartForm.File["images"]
+	if len(headers) == 0 {
+		log.Println("no images uploaded")
+		badRequest(w)
+		return
+	}
+
+	// every image is validated before saving any of them
+	var images []uploadedImage
+	for _, header := range headers {
+		file, err := header.Open()
+		if err != nil {
+			log.Println(err)
+			badRequest(w)
+			return
+		}
+		image, err := readUploadedImage(file, header.Filename)
+		file.Close()
+		if err != nil {
+			log.Println(err)
+			errorHandler(w, http.StatusUnsupportedMediaType, fmt.Sprintf("Image %s isn't supported or is too large", header.Filename))
+			return
+		}
+		images = append(images, image)
+	}
+
+	err = s.db.InTx(r.Context(), func(q *database.Queries) error {
+		for _, image := range images {
+			checksum := sha256.Sum256(image.data)
+			_, err := q.AddPluginImage(r.Context(), database.AddPluginImageParams{
+				PluginID:      pluginID,
+				FileName:      image.fileName,
+				MimeType:      image.info.MimeType,
+				Size:          int64(len(image.data)),
+				Width:         int64(image.info.Width),
+				Height:        int64(image.info.Height),
+				Sha256:        hex.EncodeToString(checksum[:]),
+				Image:         image.data,
+				Thumbnail:     image.thumbnail,
+				ThumbnailType: image.thumbnailMimeType,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return q.SetDefaultPluginCover(r.Context(), pluginID)
+	})
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// redirect to plugin page
+	http.Redirect(w, r, fmt.Sprintf("/plugins/%s", pluginSlug), http.StatusFound)
+}
+
+// Send image data with caching headers, conditional requests
+// are answered with Not Modified
+func serveImage(w http.ResponseWriter, r *http.Request, data []byte, mimeType, etag, createdAt string) {
+	w.Header().Set("Content-Type", mimeType)
+	w.Header().Set("Cache-Control", imageCacheControl)
+	w.Header().Set("ETag", strconv.Quote(etag))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	// zero time of an unparsable date skips Last-Modified
+	modified, _ := time.Parse(time.DateTime, createdAt)
+	http.ServeContent(w, r, "", modified, bytes.NewReader(data))
+}
+
+// Send original image
+func (s *Server) getPluginImage(w http.ResponseWriter, r *http.Request) {
+	imageID, err := strconv.ParseInt(r.PathValue("imageID"), 10, 64)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	image, err := s.db.Queries().GetPluginImage(r.Context(), database.GetPluginImageParams{
+		ID:   imageID,
+		Slug: r.PathValue("pluginSlug"),
+	})
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	serveImage(w, r, image.Image, image.MimeType, image.Sha256, image.CreatedAt)
+}
+
+// Send image thumbnail, small images are sent as they are
+func (s *Server) getPluginImageThumbnail(w http.ResponseWriter, r *http.Request) {
+	imageID, err := strconv.ParseInt(r.PathValue("imageID"), 10, 64)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	image, err := s.db.Queries().GetPluginImageThumbnail(r.Context(), database.GetPluginImageThumbnailParams{
+		ID:   imageID,
+		Slug: r.PathValue("pluginSlug"),
+	})
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	if len(image.Thumbnail) == 0 {
+		serveImage(w, r, image.Image, image.MimeType, image.Sha256, image.CreatedAt)
+		return
+	}
+	serveImage(w, r, image.Thumbnail, image.ThumbnailType, image.Sha256+"-thumb", image.CreatedAt)
+}
+
+// Make the image the plugin's cover
+func (s *Server) setPluginCover(w http.ResponseWriter, r *http.Request) {
+	imageID, err := strconv.ParseInt(r.PathValue("imageID"), 10, 64)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	pluginSlug := r.PathValue("pluginSlug")
+	pluginID, err := s.db.Queries().GetPluginID(r.Context(), pluginSlug)
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	// the previous cover is cleared first, a plugin has one cover at most
+	err = s.db.InTx(r.Context(), func(q *database.Queries) error {
+		if err := q.ClearPluginCover(r.Context(), pluginID); err != nil {
+			return err
+		}
+		_, err := q.SetPluginCover(r.Context(), database.SetPluginCoverParams{
+			ID:       imageID,
+			PluginID: pluginID,
+		})
+		return err
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		notFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// redirect to plugin page on success with HTMX
+	w.Header().Set("HX-Redirect", fmt.Sprintf("/plugins/%s", pluginSlug))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Delete image, another image becomes the cover if it was the one
+func (s *Server) deletePluginImage(w http.ResponseWriter, r *http.Request) {
+	imageID, err := strconv.ParseInt(r.PathValue("imageID"), 10, 64)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	pluginSlug := r.PathValue("pluginSlug")
+	err = s.db.InTx(r.Context(), func(q *database.Queries) error {
+		deleted, err := q.DeletePluginImage(r.Context(), database.DeletePluginImageParams{
+			ID:   imageID,
+			Slug: pluginSlug,
+		})
+		if err != nil {
+			return err
+		}
+		return q.SetDefaultPluginCover(r.Context(), deleted.PluginID)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		notFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// redirect to plugin page on success with HTMX
+	w.Header().Set("HX-Redirect", fmt.Sprintf("/plugins/%s", pluginSlug))
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"adminrust/internal/database"
+)
+
+func TestPluginImageHandlers(t *testing.T) {
+	loadTestTemplates(t)
+	ctx := context.Background()
+	s := &Server{db: newTestDB(t)}
+	q := s.db.Queries()
+
+	pluginOrigin, err := q.AddOrigin(ctx, database.AddOriginParams{
+		Name: "uMod", Slug: "umod", Url: "https://umod.org", PathToPluginList: "/plugins",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = q.AddPlugin(ctx, database.AddPluginParams{
+		Name: "Kits", Slug: "kits", Url: "https://umod.org/plugins/kits", OriginID: pluginOrigin.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var largePNG bytes.Buffer
+	if err = png.Encode(&largePNG, image.NewRGBA(image.Rect(0, 0, 2*thumbnailSide, thumbnailSide))); err != nil {
+		t.Fatal(err)
+	}
+
+	// upload files as images
+	upload := func(files map[string][]byte) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		for name, data := range files {
+			file, err := form.CreateFormFile("images", name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, _ = file.Write(data)
+		}
+		form.Close()
+
+		r := httptest.NewRequest("POST", "/plugins/kits/images/upload", &body)
+		r.Header.Set("Content-Type", form.FormDataContentType())
+		r.SetPathValue("pluginSlug", "kits")
+		w := httptest.NewRecorder()
+		s.uploadPluginImages(w, r)
+		return w
+	}
+
+	tests := []struct {
+		name         string
+		files        map[string][]byte
+		expectedCode int
+	}{
+		{name: "png", files: map[string][]byte{"kits.png": largePNG.Bytes()}, expectedCode: http.StatusFound},
+		{name: "not image", files: map[string][]byte{"kits.png": []byte("class Kits {}")}, expectedCode: http.StatusUnsupportedMediaType},
+		{name: "too large", files: map[string][]byte{"kits.png": make([]byte, maxImageSize+1)}, expectedCode: http.StatusUnsupportedMediaType},
+		{name: "no files", files: nil, expectedCode: http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := upload(test.files)
+			if w.Code != test.expectedCode {
+				t.Errorf("uploadPluginImages() status = %d, want %d", w.Code, test.expectedCode)
+			}
+		})
+	}
+
+	// the first image becomes the cover
+	if w := upload(map[string][]byte{"second.png": largePNG.Bytes()}); w.Code != http.StatusFound {
+		t.Fatalf("uploadPluginImages() status = %d, want %d", w.Code, http.StatusFound)
+	}
+	images, err := q.GetPluginImages(ctx, "kits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(images) != 2 || images[0].IsCover != 1 || images[0].FileName != "kits.png" || images[1].IsCover != 0 {
+		t.Fatalf("GetPluginImages() = %+v, want kits.png as cover", images)
+	}
+
+	// send a request for the second image to the handler
+	send := func(handler http.HandlerFunc, method string, header http.Header) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/plugins/kits/images", nil)
+		r.Header = header
+		r.SetPathValue("pluginSlug", "kits")
+		r.SetPathValue("imageID", fmt.Sprint(images[1].ID))
+		w := httptest.NewRecorder()
+		handler(w, r)
+		return w
+	}
+
+	// thumbnails are smaller and cached by ETag
+	w := send(s.getPluginImageThumbnail, "GET", http.Header{})
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "image/png" || etag == "" || w.Header().Get("Cache-Control") != imageCacheControl {
+		t.Fatalf("getPluginImageThumbnail() status = %d, headers = %v", w.Code, w.Header())
+	}
+	thumbnail, _, err := image.DecodeConfig(w.Body)
+	if err != nil || thumbnail.Width != thumbnailSide || thumbnail.Height != thumbnailSide/2 {
+		t.Errorf("thumbnail = %+v, %v, want %dx%d", thumbnail, err, thumbnailSide, thumbnailSide/2)
+	}
+	w = send(s.getPluginImageThumbnail, "GET", http.Header{"If-None-Match": {etag}})
+	if w.Code != http.StatusNotModified {
+		t.Errorf("conditional getPluginImageThumbnail() status = %d, want %d", w.Code, http.StatusNotModified)
+	}
+	w = send(s.getPluginImage, "GET", http.Header{})
+	if w.Code != http.StatusOK || !bytes.Equal(w.Body.Bytes(), largePNG.Bytes()) {
+		t.Errorf("getPluginImage() status = %d, the original isn't sent", w.Code)
+	}
+
+	// switching the cover and deleting it makes another image the cover
+	if w = send(s.setPluginCover, "POST", http.Header{}); w.Code != http.StatusNoContent {
+		t.Fatalf("setPluginCover() status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if w = send(s.deletePluginImage, "DELETE", http.Header{}); w.Code != http.StatusNoContent {
+		t.Fatalf("deletePluginImage() status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	plugins, err := q.GetPluginsWithCovers(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plugins) != 1 || plugins[0].CoverImageID.Int64 != images[0].ID {
+		t.Errorf("GetPluginsWithCovers() = %+v, want cover %d", plugins, images[0].ID)
+	}
+}
@@ -3,16 +3,94 @@ package server
 import (
 	"adminrust/internal/config"
 	"adminrust/internal/database"
+	"adminrust/internal/locales"
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"sync"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-playground/validator/v10"
 )
 
-// var availableLangs config.LangConfig
-var availableLangs map[string]string
+// Languages of the locale forms by their codes, read from config JSON
+// once and never modified after
+var (
+	availableLangsMu sync.Mutex
+	availableLangs   map[string]string
+)
+
+// Load available languages from config JSON once
+func loadAvailableLangs() (map[string]string, error) {
+	availableLangsMu.Lock()
+	defer availableLangsMu.Unlock()
+	if availableLangs != nil {
+		return availableLangs, nil
+	}
+
+	langCfg, err := config.ReadLangs()
+	if err != nil {
+		return nil, err
+	}
+	langs := map[string]string{}
+	for _, lang := range langCfg {
+		langs[lang.Code] = lang.Name
+	}
+	availableLangs = langs
+
+	return langs, nil
+}
+
+// Metadata of the locale form
+type localeFormMeta struct {
+	Locales map[string]string
+	// problems of the submitted locale shown with its content:
+	// the shape of the lang file or its messages
+	Problem      string
+	Issues       []locales.Issue
+	Submitted    string
+	SelectedLang string
+}
+
+// Check placeholders and rich-text tags of the locale against the plugin's
+// base language locale. Content that isn't a lang file isn't checked.
+func (s *Server) checkPluginLocale(ctx context.Context, pluginSlug, langCode, content string) ([]locales.Issue, error) {
+	messages, err := locales.Parse(content)
+	if err != nil {
+		return nil, nil
+	}
+
+	// the base language is checked for tags only
+	var base locales.Messages
+	if langCode != defaultBaseLang {
+		baseLocale, err := s.db.Queries().GetPluginLocale(ctx, database.GetPluginLocaleParams{
+			Slug:     pluginSlug,
+			LangCode: defaultBaseLang,
+		})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		// a broken base locale can't be compared with
+		base, _ = locales.Parse(baseLocale.ContentJson)
+	}
+
+	return locales.Check(base, messages), nil
+}
+
+// Normalize the submitted lang file and check its messages. The problem
+// describes content that isn't a flat object of messages.
+func (s *Server) validatePluginLocale(ctx context.Context, pluginSlug, langCode, submitted string) (content, problem string, issues []locales.Issue, err error) {
+	content, err = locales.Normalize(submitted)
+	if err != nil {
+		log.Printf("Error: invalid lang file: %s\n", err)
+		return "", "Invalid lang file: " + err.Error(), nil, nil
+	}
+
+	issues, err = s.checkPluginLocale(ctx, pluginSlug, langCode, content)
+	return content, "", issues, err
+}
 
 func (s *Server) registerPluginLocaleRoutes(r chi.Router) {
 	r.Route("/loc", func(r chi.Router) {
@@ -41,64 +119,85 @@ func (s *Server) getPluginLocales(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// compare translations with the base language
+	baseCode := baseLang(r)
+	reports, hasBase := compareLocales(locales, baseCode)
+
 	// prepare metadata
 	metaData := struct {
 		AddURL     string
 		CurrentURL string
+		BaseLang   string
+		HasBase    bool
 	}{
 		AddURL:     fmt.Sprintf("%s/add", r.URL.Path),
 		CurrentURL: r.URL.Path,
+		BaseLang:   baseCode,
+		HasBase:    hasBase,
 	}
 
-	renderPage(w, "plugin_locales", "Plugin Locales", locales, metaData)
+	renderPage(w, "plugin_locales", "Plugin Locales", reports, metaData)
 }
 
 // Render form for adding plugin locale
 func (s *Server) addPluginLocaleForm(w http.ResponseWriter, r *http.Request) {
 	// check if languages have been loaded from config JSON
-	if availableLangs == nil {
-		langCfg, err := config.ReadLangs()
-		if err != nil {
-			log.Println(err)
-			internalServerErr(w)
-			return
-		}
-		availableLangs = map[string]string{}
-		for _, lang := range langCfg {
-			availableLangs[lang.Code] = lang.Name
-		}
+	langs, err := loadAvailableLangs()
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
 	}
 	// prepare metadata
-	meta := struct{ Locales map[string]string }{availableLangs}
+	meta := localeFormMeta{Locales: langs}
 
 	renderPage(w, "add_plugin_locale", "Add Plugin Locale", nil, meta)
 }
 
-// Add locale for plugin. Expects JSON input
+// Add locale for plugin. Expects a flat JSON object of messages
 func (s *Server) addPluginLocale(w http.ResponseWriter, r *http.Request) {
-	// receive andvalidate JSON input
 	recievedLocale := r.FormValue("content")
-	validate := validator.New(validator.WithRequiredStructEnabled())
-	err := validate.Var(recievedLocale, "json")
+
+	// get and prepare locale parameters for querying
+	langs, err := loadAvailableLangs()
 	if err != nil {
-		log.Printf("Error: invalid JSON: %s\n", err)
-		badRequest(w)
+		log.Println(err)
+		internalServerErr(w)
 		return
 	}
-
-	// get and prepare locale parameters for querying
 	langCode := r.FormValue("lang-code")
-	langName, exists := availableLangs[langCode]
+	langName, exists := langs[langCode]
 	if !exists {
 		log.Printf("No languages available for language code: %s\n", langCode)
 		badRequest(w)
 		return
 	}
 	pluginSlug := chi.URLParam(r, "pluginSlug")
+
+	// show the invalid shape, broken placeholders and tags in the form
+	content, problem, issues, err := s.validatePluginLocale(r.Context(), pluginSlug, langCode, recievedLocale)
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+	if problem != "" || len(issues) > 0 {
+		meta := localeFormMeta{
+			Locales:      langs,
+			Problem:      problem,
+			Issues:       issues,
+			Submitted:    recievedLocale,
+			SelectedLang: langCode,
+		}
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		renderPage(w, "add_plugin_locale", "Add Plugin Locale", nil, meta)
+		return
+	}
+
 	params := database.AddPluginLocaleParams{
 		LangCode:    langCode,
 		LangName:    langName,
-		ContentJson: recievedLocale,
+		ContentJson: content,
 		Slug:        pluginSlug,
 	}
 
@@ -121,20 +220,14 @@ func (s *Server) updatePluginLocaleForm(w http.ResponseWriter, r *http.Request)
 	langCode := chi.URLParam(r, "lang-code")
 
 	// check if languages have been loaded from config JSON
-	if availableLangs == nil {
-		langCfg, err := config.ReadLangs()
-		if err != nil {
-			log.Println(err)
-			internalServerErr(w)
-			return
-		}
-		availableLangs = map[string]string{}
-		for _, lang := range langCfg {
-			availableLangs[lang.Code] = lang.Name
-		}
+	langs, err := loadAvailableLangs()
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
 	}
 	// validate lang code
-	_, exists := availableLangs[langCode]
+	_, exists := langs[langCode]
 	if !exists {
 		log.Printf("No languages available for language code: %s\n", langCode)
 		badRequest(w)
@@ -156,7 +249,7 @@ func (s *Server) updatePluginLocaleForm(w http.ResponseWriter, r *http.Request)
 	}
 
 	// render plugin locale addition form
-	renderPage(w, "add_plugin_locale", "Update Plugin Locale", locale, nil)
+	renderPage(w, "add_plugin_locale", "Update Plugin Locale", locale, localeFormMeta{})
 }
 
 // Update plugin locale
@@ -168,45 +261,48 @@ func (s *Server) updatePluginLocale(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// receive andvalidate JSON input
-	recievedLocale := r.FormValue("content")
-	validate := validator.New(validator.WithRequiredStructEnabled())
-	err := validate.Var(recievedLocale, "json")
-	if err != nil {
-		log.Printf("Error: invalid JSON: %s\n", err)
-		badRequest(w)
-		return
-	}
-
 	// get locale parameters
+	recievedLocale := r.FormValue("content")
 	pluginSlug := chi.URLParam(r, "pluginSlug")
 	langCode := r.FormValue("lang-code")
 
 	// check if languages have been loaded from config JSON
-	if availableLangs == nil {
-		langCfg, err := config.ReadLangs()
-		if err != nil {
-			log.Println(err)
-			internalServerErr(w)
-			return
-		}
-		availableLangs = map[string]string{}
-		for _, lang := range langCfg {
-			availableLangs[lang.Code] = lang.Name
-		}
+	langs, err := loadAvailableLangs()
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
 	}
 
 	// validate lang code
-	_, exists := availableLangs[langCode]
+	_, exists := langs[langCode]
 	if !exists {
 		log.Printf("No languages available for language code: %s\n", langCode)
 		badRequest(w)
 		return
 	}
 
+	// show the invalid shape, broken placeholders and tags in the form
+	content, problem, issues, err := s.validatePluginLocale(r.Context(), pluginSlug, langCode, recievedLocale)
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+	if problem != "" || len(issues) > 0 {
+		locale := database.PluginLocale{
+			LangCode:    langCode,
+			LangName:    langs[langCode],
+			ContentJson: recievedLocale,
+		}
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		renderPage(w, "add_plugin_locale", "Update Plugin Locale", locale, localeFormMeta{Problem: problem, Issues: issues})
+		return
+	}
+
 	// prepare locale for querying
 	params := database.UpdatePluginLocaleParams{
-		ContentJson: recievedLocale,
+		ContentJson: content,
 		LangCode:    langCode,
 		Slug:        pluginSlug,
 	}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"adminrust/internal/database"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestPluginLocaleChecks(t *testing.T) {
+	loadTestTemplates(t)
+	availableLangs = map[string]string{"en": "English", "ru": "Русский", "de": "Deutsch"}
+	t.Cleanup(func() { availableLangs = nil })
+	ctx := context.Background()
+	s := &Server{db: newTestDB(t)}
+	q := s.db.Queries()
+
+	pluginOrigin, err := q.AddOrigin(ctx, database.AddOriginParams{
+		Name: "uMod", Slug: "umod", Url: "https://umod.org", PathToPluginList: "/plugins",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = q.AddPlugin(ctx, database.AddPluginParams{
+		Name: "kits", Slug: "kits", Url: "https://umod.org/plugins/kits", OriginID: pluginOrigin.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = q.AddPluginLocale(ctx, database.AddPluginLocaleParams{
+		Slug: "kits", LangCode: "en", LangName: "English",
+		ContentJson: `{"Cooldown": "Wait <color=red>{0}</color>s"}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// send a form to the handler of the plugin's locales
+	send := func(handler http.HandlerFunc, form url.Values) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/plugins/kits/loc", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("pluginSlug", "kits")
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, routeCtx))
+		w := httptest.NewRecorder()
+		handler(w, r)
+		return w
+	}
+
+	tests := []struct {
+		name          string
+		handler       http.HandlerFunc
+		form          url.Values
+		expectedCode  int
+		expectedParts []string
+	}{
+		{
+			name:          "dropped placeholder",
+			handler:       s.addPluginLocale,
+			form:          url.Values{"lang-code": {"ru"}, "content": {`{"Cooldown": "Подождите <color=red></color>"}`}},
+			expectedCode:  http.StatusUnprocessableEntity,
+			expectedParts: []string{"placeholder {0} is missing", `value="ru" selected`, "Подождите"},
+		},
+		{
+			name:          "nested messages",
+			handler:       s.addPluginLocale,
+			form:          url.Values{"lang-code": {"ru"}, "content": {`{"Messages": {"Cooldown": "Подождите"}}`}},
+			expectedCode:  http.StatusUnprocessableEntity,
+			expectedParts: []string{"Invalid lang file: message &#34;Messages&#34; must be a string, not object", "Подождите"},
+		},
+		{
+			name:          "array",
+			handler:       s.addPluginLocale,
+			form:          url.Values{"lang-code": {"ru"}, "content": {`["Подождите"]`}},
+			expectedCode:  http.StatusUnprocessableEntity,
+			expectedParts: []string{"Invalid lang file: lang file must be an object of messages, not array"},
+		},
+		{
+			name:          "unclosed tag",
+			handler:       s.addPluginLocale,
+			form:          url.Values{"lang-code": {"ru"}, "content": {`{"Cooldown": "Подождите <color=red>{0}с"}`}},
+			expectedCode:  http.StatusUnprocessableEntity,
+			expectedParts: []string{"tag &lt;color&gt; isn&#39;t closed"},
+		},
+		{
+			name:         "valid",
+			handler:      s.addPluginLocale,
+			form:         url.Values{"lang-code": {"ru"}, "content": {"\ufeff{\"Cooldown\":\"Подождите <color=red>{0}</color>с\"}"}},
+			expectedCode: http.StatusFound,
+		},
+		{
+			name:          "update with broken tag",
+			handler:       s.updatePluginLocale,
+			form:          url.Values{"_method": {"PUT"}, "lang-code": {"ru"}, "content": {`{"Cooldown": "<b>{0}"}`}},
+			expectedCode:  http.StatusUnprocessableEntity,
+			expectedParts: []string{"tag &lt;b&gt; isn&#39;t closed", `name="_method" value="PUT"`},
+		},
+		{
+			name:          "base language checked for tags",
+			handler:       s.updatePluginLocale,
+			form:          url.Values{"_method": {"PUT"}, "lang-code": {"en"}, "content": {`{"Cooldown": "Wait {1}</b>"}`}},
+			expectedCode:  http.StatusUnprocessableEntity,
+			expectedParts: []string{"tag &lt;/b&gt; isn&#39;t opened"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := send(test.handler, test.form)
+			if w.Code != test.expectedCode {
+				t.Fatalf("status = %d, want %d", w.Code, test.expectedCode)
+			}
+			for _, part := range test.expectedParts {
+				if !strings.Contains(w.Body.String(), part) {
+					t.Errorf("body misses %q", part)
+				}
+			}
+		})
+	}
+
+	// saved lang files are normalized
+	locale, err := q.GetPluginLocale(ctx, database.GetPluginLocaleParams{Slug: "kits", LangCode: "ru"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedContent := "{\n  \"Cooldown\": \"Подождите <color=red>{0}</color>с\"\n}"
+	if locale.ContentJson != expectedContent {
+		t.Errorf("saved content = %q, want %q", locale.ContentJson, expectedContent)
+	}
+}
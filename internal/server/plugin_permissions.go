@@ -0,0 +1,320 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"adminrust/internal/analyzer"
+	"adminrust/internal/database"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Permission-related routes
+func (s *Server) registerPluginPermissionRoutes(r chi.Router) {
+	r.Route("/permissions", func(r chi.Router) {
+		r.Get("/", s.getPluginPermissions)
+		// adding
+		r.Get("/add", s.addPluginPermissionForm)
+		r.Post("/add", s.addPluginPermission)
+		// editing
+		r.Get("/edit/{permissionID:[0-9]+}", s.updatePluginPermissionForm)
+		r.Post("/edit/{permissionID:[0-9]+}", s.updatePluginPermission)
+		// deleting
+		r.Delete("/{permissionID:[0-9]+}", s.deletePluginPermission)
+		// extracting from plugin source
+		r.Get("/analyze", s.analyzePermissionsForm)
+		r.Post("/analyze", s.analyzePermissions)
+		r.Post("/analyze/save", s.saveAnalyzedPermissions)
+	})
+}
+
+// Permission proposed for saving after source analysis
+type proposedPermission struct {
+	analyzer.Permission
+	// the plugin has the permission saved already
+	Exists bool
+}
+
+// Retrieve permission list
+func (s *Server) getPluginPermissions(w http.ResponseWriter, r *http.Request) {
+	pluginSlug := r.PathValue("pluginSlug")
+
+	permissions, err := s.db.Queries().GetPluginPermissions(r.Context(), pluginSlug)
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	metaData := struct{ CurrentURL string }{
+		CurrentURL: fmt.Sprintf("/plugins/%s/permissions", pluginSlug),
+	}
+
+	renderPage(w, "plugin_permissions", "", permissions, metaData)
+}
+
+// Read and validate permission fields of the form
+func parsePermissionForm(r *http.Request) (permission, description string, err error) {
+	// permissions are prefixed with a plugin name, e.g. kits.vip
+	permission = strings.TrimSpace(r.FormValue("permission"))
+	if !validatePermission(permission) {
+		return "", "", fmt.Errorf("invalid permission: %q", permission)
+	}
+	description = strings.TrimSpace(r.FormValue("description"))
+
+	return permission, description, nil
+}
+
+// Render form for adding permission
+func (s *Server) addPluginPermissionForm(w http.ResponseWriter, r *http.Request) {
+	renderPage(w, "add_plugin_perm", "Add Permission", nil, nil)
+}
+
+// Add permission
+func (s *Server) addPluginPermission(w http.ResponseWriter, r *http.Request) {
+	permission, description, err := parsePermissionForm(r)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	pluginSlug := r.PathValue("pluginSlug")
+	pluginID, err := s.db.Queries().GetPluginID(r.Context(), pluginSlug)
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	// save permission, nothing is returned if it exists already
+	_, err = s.db.Queries().AddPluginPermission(r.Context(), database.AddPluginPermissionParams{
+		PluginID:    pluginID,
+		Permission:  permission,
+		Description: description,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		errorHandler(w, http.StatusConflict, "Permission already exists")
+		return
+	}
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// redirect to plugin page
+	http.Redirect(w, r, fmt.Sprintf("/plugins/%s", pluginSlug), http.StatusFound)
+}
+
+// Render form for updating permission
+func (s *Server) updatePluginPermissionForm(w http.ResponseWriter, r *http.Request) {
+	permissionID, err := strconv.ParseInt(r.PathValue("permissionID"), 10, 64)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	// get permission or Not Found error
+	permission, err := s.db.Queries().GetPluginPermission(r.Context(), database.GetPluginPermissionParams{
+		ID:   permissionID,
+		Slug: r.PathValue("pluginSlug"),
+	})
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	// show pre-populated form
+	renderPage(w, "add_plugin_perm", "Update Permission", permission, nil)
+}
+
+// Update permission
+func (s *Server) updatePluginPermission(w http.ResponseWriter, r *http.Request) {
+	// check if the retrieved form contains hidden PUT method
+	if r.FormValue("_method") != "PUT" {
+		log.Println("post with no PUT input")
+		notAllowed(w, r)
+		return
+	}
+
+	permissionID, err := strconv.ParseInt(r.PathValue("permissionID"), 10, 64)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+	permission, description, err := parsePermissionForm(r)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	pluginSlug := r.PathValue("pluginSlug")
+	// save permission updates, the permission must belong to the plugin
+	_, err = s.db.Queries().UpdatePluginPermission(r.Context(), database.UpdatePluginPermissionParams{
+		Permission:  permission,
+		Description: description,
+		ID:          permissionID,
+		Slug:        pluginSlug,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		notFound(w, r)
+		return
+	}
+	if database.IsUniqueViolation(err) {
+		errorHandler(w, http.StatusConflict, "Permission already exists")
+		return
+	}
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// redirect to plugin page
+	http.Redirect(w, r, fmt.Sprintf("/plugins/%s", pluginSlug), http.StatusFound)
+}
+
+// Delete permission
+func (s *Server) deletePluginPermission(w http.ResponseWriter, r *http.Request) {
+	permissionID, err := strconv.ParseInt(r.PathValue("permissionID"), 10, 64)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	pluginSlug := r.PathValue("pluginSlug")
+	_, err = s.db.Queries().DeletePluginPermission(r.Context(), database.DeletePluginPermissionParams{
+		ID:   permissionID,
+		Slug: pluginSlug,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		notFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// redirect to plugin page on success with HTMX
+	w.Header().Set("HX-Redirect", fmt.Sprintf("/plugins/%s", pluginSlug))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Render form for uploading plugin source to extract permissions from
+func (s *Server) analyzePermissionsForm(w http.ResponseWriter, r *http.Request) {
+	metaData := struct{ Hint string }{
+		Hint: "Permissions registered with RegisterPermission and required by Covalence commands are extracted for review.",
+	}
+
+	renderPage(w, "analyze_plugin_source", "Extract Permissions from Source", nil, metaData)
+}
+
+// Extract permissions from the uploaded plugin source and render them for review
+func (s *Server) analyzePermissions(w http.ResponseWriter, r *http.Request) {
+	pluginSlug := r.PathValue("pluginSlug")
+	existing, err := s.db.Queries().GetPluginPermissions(r.Context(), pluginSlug)
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// read the source file or Bad Request error
+	fileName, source, err := readPluginSource(w, r)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	result := analyzer.Analyze(source)
+	permissions := make([]proposedPermission, 0, len(result.Permissions))
+	for _, permission := range result.Permissions {
+		permissions = append(permissions, proposedPermission{
+			Permission: permission,
+			Exists: slices.ContainsFunc(existing, func(p database.PluginPermission) bool {
+				return p.Permission == permission.Name
+			}),
+		})
+	}
+
+	metaData := struct {
+		FileName   string
+		Unresolved []string
+	}{fileName, result.Unresolved}
+
+	renderPage(w, "review_plugin_perms", "Review Extracted Permissions", permissions, metaData)
+}
+
+// Save permissions selected on the review page, existing ones are skipped
+func (s *Server) saveAnalyzedPermissions(w http.ResponseWriter, r *http.Request) {
+	pluginSlug := r.PathValue("pluginSlug")
+	pluginID, err := s.db.Queries().GetPluginID(r.Context(), pluginSlug)
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	if err = r.ParseForm(); err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+	permissions := r.PostForm["permission"]
+	descriptions := r.PostForm["description"]
+	if len(permissions) != len(descriptions) {
+		log.Printf("%d descriptions for %d permissions\n", len(descriptions), len(permissions))
+		badRequest(w)
+		return
+	}
+
+	var params []database.AddPluginPermissionParams
+	for _, rawIndex := range r.PostForm["include"] {
+		i, err := strconv.Atoi(rawIndex)
+		if err != nil || i < 0 || i >= len(permissions) || !validatePermission(permissions[i]) {
+			log.Printf("invalid permission index: %q\n", rawIndex)
+			badRequest(w)
+			return
+		}
+		params = append(params, database.AddPluginPermissionParams{
+			PluginID:    pluginID,
+			Permission:  permissions[i],
+			Description: strings.TrimSpace(descriptions[i]),
+		})
+	}
+
+	// save permissions or Internal Server Error
+	err = s.db.InTx(r.Context(), func(q *database.Queries) error {
+		for _, param := range params {
+			_, err := q.AddPluginPermission(r.Context(), param)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// redirect to plugin page
+	http.Redirect(w, r, fmt.Sprintf("/plugins/%s", pluginSlug), http.StatusFound)
+}
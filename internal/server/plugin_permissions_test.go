@@ -0,0 +1,112 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"adminrust/internal/database"
+)
+
+func TestPluginPermissionHandlers(t *testing.T) {
+	loadTestTemplates(t)
+	ctx := context.Background()
+	s := &Server{db: newTestDB(t)}
+	q := s.db.Queries()
+
+	pluginOrigin, err := q.AddOrigin(ctx, database.AddOriginParams{
+		Name: "uMod", Slug: "umod", Url: "https://umod.org", PathToPluginList: "/plugins",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = q.AddPlugin(ctx, database.AddPluginParams{
+		Name: "Kits", Slug: "kits", Url: "https://umod.org/plugins/kits", OriginID: pluginOrigin.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// send a form to the handler of the plugin's permissions
+	send := func(handler http.HandlerFunc, form url.Values) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/plugins/kits/permissions", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.SetPathValue("pluginSlug", "kits")
+		w := httptest.NewRecorder()
+		handler(w, r)
+		return w
+	}
+
+	tests := []struct {
+		name         string
+		permission   string
+		expectedCode int
+	}{
+		{name: "new", permission: "kits.vip", expectedCode: http.StatusFound},
+		{name: "duplicate", permission: "kits.vip", expectedCode: http.StatusConflict},
+		{name: "no prefix", permission: "vip", expectedCode: http.StatusBadRequest},
+		{name: "spaces", permission: "kits. vip", expectedCode: http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := send(s.addPluginPermission, url.Values{"permission": {test.permission}, "description": {"VIP kits"}})
+			if w.Code != test.expectedCode {
+				t.Errorf("addPluginPermission() status = %d, want %d", w.Code, test.expectedCode)
+			}
+		})
+	}
+
+	// upload the source, the saved permission is marked as existing
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	file, err := form.CreateFormFile("source", "Kits.cs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = file.Write([]byte(`const string Prefix = "kits.";
+void Init() {
+    permission.RegisterPermission(Prefix + "vip", this);
+    permission.RegisterPermission(Prefix + "admin", this);
+}`))
+	form.Close()
+
+	r := httptest.NewRequest("POST", "/plugins/kits/permissions/analyze", &body)
+	r.Header.Set("Content-Type", form.FormDataContentType())
+	r.SetPathValue("pluginSlug", "kits")
+	w := httptest.NewRecorder()
+	s.analyzePermissions(w, r)
+	if page := w.Body.String(); w.Code != http.StatusOK || !strings.Contains(page, `value="kits.admin"`) || !strings.Contains(page, "already saved") {
+		t.Fatalf("analyzePermissions() status = %d, page doesn't propose kits.admin:\n%s", w.Code, page)
+	}
+
+	w = send(s.saveAnalyzedPermissions, url.Values{
+		"include":     {"1"},
+		"permission":  {"kits.vip", "kits.admin"},
+		"description": {"", "Manage kits"},
+	})
+	permissions, err := q.GetPluginPermissions(ctx, "kits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusFound || len(permissions) != 2 || permissions[0].Permission != "kits.admin" {
+		t.Errorf("saveAnalyzedPermissions() status = %d, permissions = %+v, want kits.admin and kits.vip", w.Code, permissions)
+	}
+
+	// renaming onto another saved permission conflicts as adding does
+	update := url.Values{"_method": {"PUT"}, "permission": {"kits.vip"}, "description": {"Manage kits"}}
+	r = httptest.NewRequest("POST", "/plugins/kits/permissions", strings.NewReader(update.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.SetPathValue("pluginSlug", "kits")
+	r.SetPathValue("permissionID", strconv.FormatInt(permissions[0].ID, 10))
+	w = httptest.NewRecorder()
+	s.updatePluginPermission(w, r)
+	if w.Code != http.StatusConflict {
+		t.Errorf("updatePluginPermission() to a saved name status = %d, want %d", w.Code, http.StatusConflict)
+	}
+}
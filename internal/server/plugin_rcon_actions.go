@@ -0,0 +1,252 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"adminrust/internal/database"
+	"adminrust/internal/oxide"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Statuses of RCON actions stored in rcon_actions
+const (
+	rconActionRunning = "running"
+	rconActionDone    = "done"
+	rconActionFailed  = "failed"
+)
+
+const (
+	// console output is collected until the server is quiet for this long
+	rconOutputQuiet = time.Second
+	// actions shown on the plugin page
+	rconActionHistory = 20
+)
+
+// Time an action may take including waiting for its console output,
+// shortened by tests
+var rconActionTimeout = 30 * time.Second
+
+// Header the authenticating proxy names users in. Clients can send any
+// header, so it's trusted only when set up, client addresses are
+// recorded otherwise.
+var actorHeader = os.Getenv("ACTOR_HEADER")
+
+// Routes running plugin actions on servers over RCON
+func (s *Server) registerPluginRconRoutes(r chi.Router) {
+	r.Route("/rcon", func(r chi.Router) {
+		r.Post("/{serverSlug:[a-z0-9-]+}/{action:reload|load|unload}", s.runPluginRconAction)
+		// polled by the action fragment until the action finishes
+		r.Get("/actions/{actionID:[0-9]+}", s.getPluginRconAction)
+	})
+}
+
+// Plugin names are single console arguments
+var validateRconPluginName = validateByPattern(`^[A-Za-z0-9_]+$`)
+
+// Console command of the action for the server framework
+func rconCommand(framework, action, pluginName string) (string, error) {
+	if !validateRconPluginName(pluginName) {
+		return "", fmt.Errorf("plugin name %q can't be sent as a console argument", pluginName)
+	}
+	if framework == "carbon" {
+		return fmt.Sprintf("c.%s %s", action, pluginName), nil
+	}
+
+	return fmt.Sprintf("oxide.%s %s", action, pluginName), nil
+}
+
+// Who runs the action: the user named by the proxy or the client address
+func requestActor(r *http.Request) string {
+	if actorHeader != "" {
+		if user := strings.TrimSpace(r.Header.Get(actorHeader)); user != "" {
+			return user
+		}
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+
+	return r.RemoteAddr
+}
+
+// Run the action on the server the plugin is installed on and render
+// a fragment following its output
+func (s *Server) runPluginRconAction(w http.ResponseWriter, r *http.Request) {
+	plugin, err := s.db.Queries().GetPlugin(r.Context(), r.PathValue("pluginSlug"))
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+	server, err := s.db.Queries().GetServer(r.Context(), r.PathValue("serverSlug"))
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+	// actions are offered for servers the plugin is installed on
+	_, err = s.db.Queries().GetServerPlugin(r.Context(), database.GetServerPluginParams{
+		ServerID: server.ID,
+		PluginID: plugin.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		notFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// servers know plugins by their file names
+	sourceFileName, err := s.db.Queries().GetPluginSourceFileName(r.Context(), plugin.ID)
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+	pluginName := oxide.PluginName(sourceFileName, plugin.Name)
+	action := r.PathValue("action")
+	command, err := rconCommand(server.Framework, action, pluginName)
+	if err != nil {
+		log.Println(err)
+		errorHandler(w, http.StatusUnprocessableEntity, "Plugin file name can't be used in console commands")
+		return
+	}
+
+	recorded, err := s.db.Queries().AddRconAction(r.Context(), database.AddRconActionParams{
+		ServerID: server.ID,
+		PluginID: plugin.ID,
+		Action:   action,
+		Command:  command,
+		Actor:    requestActor(r),
+	})
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// the action outlives the request, the fragment polls for its output
+	s.rcon.running.Add(1)
+	go func() {
+		defer s.rcon.running.Done()
+		s.executeRconAction(recorded, server, pluginName)
+	}()
+
+	s.renderPluginRconAction(w, r, recorded.ID)
+}
+
+// Render the action with the output received so far
+func (s *Server) getPluginRconAction(w http.ResponseWriter, r *http.Request) {
+	actionID, err := strconv.ParseInt(r.PathValue("actionID"), 10, 64)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	s.renderPluginRconAction(w, r, actionID)
+}
+
+// Render the action fragment, running actions poll for updates
+func (s *Server) renderPluginRconAction(w http.ResponseWriter, r *http.Request, actionID int64) {
+	action, err := s.db.Queries().GetRconAction(r.Context(), database.GetRconActionParams{
+		ID:   actionID,
+		Slug: r.PathValue("pluginSlug"),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		notFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	renderPage(w, "plugin_rcon_action", "RCON Action", action, nil)
+}
+
+// Send the command and record the response with related console
+// output as it arrives
+func (s *Server) executeRconAction(action database.RconAction, server database.Server, pluginName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), rconActionTimeout)
+	defer cancel()
+	// the action is recorded as finished after running out of time too
+	dbCtx := context.WithoutCancel(ctx)
+	q := s.db.Queries()
+
+	record := func(line string) {
+		err := q.AppendRconActionOutput(dbCtx, database.AppendRconActionOutputParams{Output: line + "\n", ID: action.ID})
+		if err != nil {
+			log.Println(err)
+		}
+	}
+	finish := func(status string) {
+		if err := q.FinishRconAction(dbCtx, database.FinishRconActionParams{Status: status, ID: action.ID}); err != nil {
+			log.Println(err)
+		}
+	}
+
+	client, err := s.rconClient(server)
+	if err != nil {
+		record(err.Error())
+		finish(rconActionFailed)
+		return
+	}
+
+	// plugins report loading to the console rather than in the response
+	frames, unsubscribe := client.Subscribe()
+	defer unsubscribe()
+
+	response, err := client.Execute(ctx, action.Command)
+	if err != nil {
+		log.Printf("Error running %q on %s: %s\n", action.Command, server.Name, err)
+		record(err.Error())
+		finish(rconActionFailed)
+		return
+	}
+	status := rconActionDone
+	if response.Message != "" {
+		record(response.Message)
+	}
+	if response.Type == "Error" {
+		status = rconActionFailed
+	}
+
+	quiet := time.NewTimer(rconOutputQuiet)
+	defer quiet.Stop()
+	for {
+		select {
+		case frame := <-frames:
+			// console output of other plugins and chat isn't related
+			if !strings.Contains(strings.ToLower(frame.Message), strings.ToLower(pluginName)) {
+				continue
+			}
+			record(frame.Message)
+			if frame.Type == "Error" {
+				status = rconActionFailed
+			}
+			quiet.Reset(rconOutputQuiet)
+		case <-quiet.C:
+			finish(status)
+			return
+		case <-ctx.Done():
+			finish(status)
+			return
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"adminrust/internal/database"
+	"adminrust/internal/rcon"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestRconCommand(t *testing.T) {
+	tests := []struct {
+		framework, action, pluginName string
+		expectedCommand               string
+	}{
+		{framework: "oxide", action: "reload", pluginName: "Kits", expectedCommand: "oxide.reload Kits"},
+		{framework: "oxide", action: "unload", pluginName: "Kits", expectedCommand: "oxide.unload Kits"},
+		{framework: "carbon", action: "load", pluginName: "Kits", expectedCommand: "c.load Kits"},
+		// uploaded file names may carry more arguments
+		{framework: "oxide", action: "reload", pluginName: "Kits (1)"},
+		{framework: "oxide", action: "reload", pluginName: `Kits"; quit`},
+		{framework: "oxide", action: "reload", pluginName: ""},
+	}
+
+	for _, test := range tests {
+		command, err := rconCommand(test.framework, test.action, test.pluginName)
+		if command != test.expectedCommand || (err == nil) != (test.expectedCommand != "") {
+			t.Errorf("rconCommand(%q, %q, %q) = %q, %v, want %q", test.framework, test.action, test.pluginName, command, err, test.expectedCommand)
+		}
+	}
+}
+
+func TestPluginRconActions(t *testing.T) {
+	// stand-in for a Rust server reporting plugin loading to the console
+	upgrader := websocket.Upgrader{}
+	standIn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/secret" && r.URL.Path != "/chatty" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		chatty := r.URL.Path == "/chatty"
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			var request rcon.Message
+			if err := conn.ReadJSON(&request); err != nil {
+				return
+			}
+			_ = conn.WriteJSON(rcon.Message{Identifier: request.Identifier, Type: "Generic"})
+			_ = conn.WriteJSON(rcon.Message{Identifier: -1, Message: "[Chat] Player: hi", Type: "Chat"})
+			_ = conn.WriteJSON(rcon.Message{Identifier: -1, Message: "Loaded plugin Kits v4.4.2 by k1lly0u", Type: "Generic"})
+			// a plugin logging without pause keeps the output coming
+			for chatty {
+				time.Sleep(50 * time.Millisecond)
+				if err := conn.WriteJSON(rcon.Message{Identifier: -1, Message: "[Kits] Kit given", Type: "Generic"}); err != nil {
+					return
+				}
+			}
+		}
+	}))
+	defer standIn.Close()
+	host, rawPort, err := net.SplitHostPort(strings.TrimPrefix(standIn.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, _ := strconv.ParseInt(rawPort, 10, 64)
+
+	loadTestTemplates(t)
+	ctx := context.Background()
+	s := &Server{db: newTestDB(t)}
+	q := s.db.Queries()
+
+	pluginOrigin, err := q.AddOrigin(ctx, database.AddOriginParams{
+		Name: "uMod", Slug: "umod", Url: "https://umod.org", PathToPluginList: "/plugins",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kits, err := q.AddPlugin(ctx, database.AddPluginParams{
+		Name: "Kits", Slug: "kits", Url: "https://umod.org/plugins/kits", OriginID: pluginOrigin.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	servers := []database.AddServerParams{
+		{Name: "Main", Slug: "main", Framework: "oxide", RconHost: host, RconPort: port, RconPassword: "secret"},
+		{Name: "Wrong", Slug: "wrong", Framework: "oxide", RconHost: host, RconPort: port, RconPassword: "guess"},
+		{Name: "Modded", Slug: "modded", Framework: "oxide", RconHost: host, RconPort: port, RconPassword: "secret"},
+		{Name: "Chatty", Slug: "chatty", Framework: "oxide", RconHost: host, RconPort: port, RconPassword: "chatty"},
+	}
+	for _, params := range servers {
+		server, err := q.AddServer(ctx, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the plugin isn't installed on the modded server
+		if server.Slug == "modded" {
+			continue
+		}
+		_, err = q.SetServerPlugin(ctx, database.SetServerPluginParams{
+			ServerID: server.ID, PluginID: kits.ID, Version: "4.4.2", InstalledAt: "2025-03-02",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// users are named by the proxy in front
+	defer func(header string) { actorHeader = header }(actorHeader)
+	actorHeader = "X-Forwarded-User"
+	run := func(serverSlug, action string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/plugins/kits/rcon/"+serverSlug+"/"+action, nil)
+		r.Header.Set("X-Forwarded-User", "alice")
+		r.SetPathValue("pluginSlug", "kits")
+		r.SetPathValue("serverSlug", serverSlug)
+		r.SetPathValue("action", action)
+		w := httptest.NewRecorder()
+		s.runPluginRconAction(w, r)
+		return w
+	}
+
+	tests := []struct {
+		name           string
+		serverSlug     string
+		action         string
+		expectedStatus string
+		expectedParts  []string
+	}{
+		{name: "reload", serverSlug: "main", action: "reload", expectedStatus: rconActionDone, expectedParts: []string{"Loaded plugin Kits v4.4.2"}},
+		{name: "wrong password", serverSlug: "wrong", action: "unload", expectedStatus: rconActionFailed, expectedParts: []string{rcon.ErrUnauthorized.Error()}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := run(test.serverSlug, test.action)
+			if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "oxide."+test.action+" Kits") {
+				t.Fatalf("runPluginRconAction() status = %d, body:\n%s", w.Code, w.Body.String())
+			}
+			s.rcon.running.Wait()
+
+			actions, err := q.GetPluginRconActions(ctx, database.GetPluginRconActionsParams{Slug: "kits", Limit: 1})
+			if err != nil || len(actions) != 1 {
+				t.Fatalf("GetPluginRconActions() = %+v, %v", actions, err)
+			}
+			action := actions[0]
+			if action.Status != test.expectedStatus || action.Actor != "alice" || action.FinishedAt == "" {
+				t.Errorf("recorded action = %+v, want %s by alice", action, test.expectedStatus)
+			}
+			if strings.Contains(action.Output, "[Chat]") {
+				t.Errorf("output = %q, want unrelated console output left out", action.Output)
+			}
+
+			// the polled fragment shows the output without polling further
+			r := httptest.NewRequest("GET", "/plugins/kits/rcon/actions/"+strconv.FormatInt(action.ID, 10), nil)
+			r.SetPathValue("pluginSlug", "kits")
+			r.SetPathValue("actionID", strconv.FormatInt(action.ID, 10))
+			w = httptest.NewRecorder()
+			s.getPluginRconAction(w, r)
+			for _, part := range test.expectedParts {
+				if !strings.Contains(w.Body.String(), part) {
+					t.Errorf("fragment misses %q:\n%s", part, w.Body.String())
+				}
+			}
+			if strings.Contains(w.Body.String(), "hx-trigger") {
+				t.Error("finished action fragment keeps polling")
+			}
+		})
+	}
+
+	// output that never quiets down is cut off by the action timeout
+	defer func(timeout time.Duration) { rconActionTimeout = timeout }(rconActionTimeout)
+	rconActionTimeout = 300 * time.Millisecond
+	if w := run("chatty", "reload"); w.Code != http.StatusOK {
+		t.Fatalf("runPluginRconAction() status = %d", w.Code)
+	}
+	s.rcon.running.Wait()
+	actions, err := q.GetPluginRconActions(ctx, database.GetPluginRconActionsParams{Slug: "kits", Limit: 1})
+	if err != nil || len(actions) != 1 {
+		t.Fatalf("GetPluginRconActions() = %+v, %v", actions, err)
+	}
+	if chatty := actions[0]; chatty.Status != rconActionDone || chatty.FinishedAt == "" || !strings.Contains(chatty.Output, "Kit given") {
+		t.Errorf("action cut off by the timeout = %+v, want a finished one", chatty)
+	}
+
+	if w := run("modded", "reload"); w.Code != http.StatusNotFound {
+		t.Errorf("action on server without the plugin status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	// the plugin page lists who ran what
+	r := httptest.NewRequest("GET", "/plugins/kits", nil)
+	r.SetPathValue("pluginSlug", "kits")
+	w := httptest.NewRecorder()
+	s.getPlugin(w, r)
+	for _, part := range []string{"/plugins/kits/rcon/main/reload", "alice", "oxide.unload Kits"} {
+		if !strings.Contains(w.Body.String(), part) {
+			t.Errorf("plugin page misses %q", part)
+		}
+	}
+
+	// actions interrupted by a restart don't stay running
+	server, err := q.GetServer(ctx, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	interrupted, err := q.AddRconAction(ctx, database.AddRconActionParams{
+		ServerID: server.ID, PluginID: kits.ID, Action: "reload", Command: "oxide.reload Kits", Actor: "alice",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = q.FailUnfinishedRconActions(ctx, "interrupted by restart\n"); err != nil {
+		t.Fatal(err)
+	}
+	action, err := q.GetRconAction(ctx, database.GetRconActionParams{ID: interrupted.ID, Slug: "kits"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if action.Status != rconActionFailed || interrupted.Status != rconActionRunning {
+		t.Errorf("interrupted action status = %q, want %q", action.Status, rconActionFailed)
+	}
+}
+
+func TestRequestActor(t *testing.T) {
+	defer func(header string) { actorHeader = header }(actorHeader)
+
+	tests := []struct {
+		name          string
+		actorHeader   string
+		expectedActor string
+	}{
+		{name: "trusted header", actorHeader: "X-Forwarded-User", expectedActor: "alice"},
+		// without a proxy anyone could claim a name
+		{name: "no header set up", actorHeader: "", expectedActor: "192.0.2.1"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actorHeader = test.actorHeader
+			r := httptest.NewRequest("POST", "/plugins/kits/rcon/main/reload", nil)
+			r.Header.Set("X-Forwarded-User", "alice")
+			if actor := requestActor(r); actor != test.expectedActor {
+				t.Errorf("requestActor() = %q, want %q", actor, test.expectedActor)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"adminrust/internal/analyzer"
+	"adminrust/internal/database"
+)
+
+// Maximum size of an uploaded plugin source file
+const maxSourceSize = 5 << 20
+
+// Command proposed for saving after source analysis
+type proposedCommand struct {
+	database.AddPluginCommandsParams
+	// method handling the command and where it's declared
+	Handler string
+	Line    int
+	// the plugin has a command with the same name already
+	Exists bool
+}
+
+// Render form for uploading plugin source to extract commands from
+func (s *Server) analyzePluginSourceForm(w http.ResponseWriter, r *http.Request) {
+	metaData := struct{ Hint string }{
+		Hint: "Commands declared with ChatCommand, ConsoleCommand, AddChatCommand, and AddCovalenceCommand are extracted for review.",
+	}
+
+	renderPage(w, "analyze_plugin_source", "Extract Commands from Source", nil, metaData)
+}
+
+// Extract commands from the uploaded plugin source and render them for review
+func (s *Server) analyzePluginSource(w http.ResponseWriter, r *http.Request) {
+	pluginSlug := r.PathValue("pluginSlug")
+	if _, err := s.db.Queries().GetPluginID(r.Context(), pluginSlug); err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+	existing, err := s.db.Queries().GetPluginCommands(r.Context(), pluginSlug)
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// read the source file or Bad Request error
+	fileName, source, err := readPluginSource(w, r)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	result := analyzer.Analyze(source)
+	commands := proposeCommands(result.Commands, existing)
+
+	metaData := struct {
+		FileName   string
+		Unresolved []string
+		CmdTypes   []string
+	}{fileName, result.Unresolved, cmdTypes}
+
+	renderPage(w, "review_plugin_cmds", "Review Extracted Commands", commands, metaData)
+}
+
+// Read an uploaded plugin C# source file
+func readPluginSource(w http.ResponseWriter, r *http.Request) (fileName string, source []byte, err error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxSourceSize)
+	file, header, err := r.FormFile("source")
+	if err != nil {
+		return "", nil, err
+	}
+	defer file.Close()
+
+	if !strings.EqualFold(filepath.Ext(header.Filename), ".cs") {
+		return "", nil, fmt.Errorf("not a C# file: %s", header.Filename)
+	}
+	source, err = io.ReadAll(file)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return header.Filename, source, nil
+}
+
+// Save commands selected on the review page
+func (s *Server) saveAnalyzedCommands(w http.ResponseWriter, r *http.Request) {
+	pluginSlug := r.PathValue("pluginSlug")
+	pluginID, err := s.db.Queries().GetPluginID(r.Context(), pluginSlug)
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	commands, err := parseReviewedCommands(r)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	// save commands to DB or Internal Server Error
+	err = s.savePluginCommands(r.Context(), pluginID, commands, r.FormValue("replaceAll") == "yes")
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// redirect to plugin page
+	http.Redirect(w, r, fmt.Sprintf("/plugins/%s", pluginSlug), http.StatusFound)
+}
+
+// Convert analyzed commands to rows in the format of manually added ones,
+// chat commands are shown with a leading slash
+func proposeCommands(commands []analyzer.Command, existing []database.PluginCommand) (proposed []proposedCommand) {
+	for i, command := range commands {
+		name := command.Name
+		aliases := slices.Clone(command.Aliases)
+		if command.Type != analyzer.TypeConsole {
+			name = "/" + name
+			for j := range aliases {
+				aliases[j] = "/" + aliases[j]
+			}
+		}
+
+		proposed = append(proposed, proposedCommand{
+			AddPluginCommandsParams: database.AddPluginCommandsParams{
+				Command:    name,
+				CmdType:    command.Type,
+				Aliases:    strings.Join(aliases, ", "),
+				Permission: command.Permission,
+				Position:   int64(i),
+			},
+			Handler: command.Handler,
+			Line:    command.Line,
+			Exists: slices.ContainsFunc(existing, func(c database.PluginCommand) bool {
+				return c.Command == name
+			}),
+		})
+	}
+
+	return proposed
+}
+
+// Read commands selected on the review page. Every row has the same set
+// of fields, and the "include" field holds indexes of selected rows.
+func parseReviewedCommands(r *http.Request) (commands []database.AddPluginCommandsParams, err error) {
+	if err = r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	fields := []string{"command", "cmdType", "args", "aliases", "permission", "description"}
+	rowCount := len(r.PostForm["command"])
+	for _, field := range fields {
+		if len(r.PostForm[field]) != rowCount {
+			return nil, fmt.Errorf("field %s has %d values for %d commands", field, len(r.PostForm[field]), rowCount)
+		}
+	}
+
+	for _, rawIndex := range r.PostForm["include"] {
+		i, err := strconv.Atoi(rawIndex)
+		if err != nil || i < 0 || i >= rowCount {
+			return nil, fmt.Errorf("invalid command index: %q", rawIndex)
+		}
+
+		command := database.AddPluginCommandsParams{
+			Command:     strings.TrimSpace(r.PostForm["command"][i]),
+			CmdType:     r.PostForm["cmdType"][i],
+			Args:        strings.TrimSpace(r.PostForm["args"][i]),
+			Aliases:     strings.TrimSpace(r.PostForm["aliases"][i]),
+			Permission:  strings.TrimSpace(r.PostForm["permission"][i]),
+			Description: strings.TrimSpace(r.PostForm["description"][i]),
+			Position:    int64(len(commands)),
+		}
+		if command.Command == "" {
+			return nil, fmt.Errorf("empty command at row %d", i)
+		}
+		if !slices.Contains(cmdTypes, command.CmdType) {
+			return nil, fmt.Errorf("unknown command type: %q", command.CmdType)
+		}
+		commands = append(commands, command)
+	}
+
+	if len(commands) == 0 {
+		return nil, fmt.Errorf("no commands selected")
+	}
+
+	return commands, nil
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"adminrust/internal/database"
+)
+
+func TestAnalyzePluginSource(t *testing.T) {
+	loadTestTemplates(t)
+	ctx := context.Background()
+	s := &Server{db: newTestDB(t)}
+	q := s.db.Queries()
+
+	pluginOrigin, err := q.AddOrigin(ctx, database.AddOriginParams{
+		Name: "uMod", Slug: "umod", Url: "https://umod.org", PathToPluginList: "/plugins",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plugin, err := q.AddPlugin(ctx, database.AddPluginParams{
+		Name: "Kits", Slug: "kits", Url: "https://umod.org/plugins/kits", OriginID: pluginOrigin.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = q.AddPluginCommand(ctx, database.AddPluginCommandParams{
+		PluginID: plugin.ID, Command: "/kit", CmdType: cmdTypeChat, Description: "Claim a kit",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// upload the source
+	analyze := func(pluginSlug string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		file, err := form.CreateFormFile("source", "Kits.cs")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = file.Write([]byte(`[ChatCommand("kit")] void CmdKit() {}
+[ConsoleCommand("kit.reset")] void CCmdReset() {}`))
+		form.Close()
+
+		r := httptest.NewRequest("POST", "/plugins/"+pluginSlug+"/commands/analyze", &body)
+		r.Header.Set("Content-Type", form.FormDataContentType())
+		r.SetPathValue("pluginSlug", pluginSlug)
+		w := httptest.NewRecorder()
+		s.analyzePluginSource(w, r)
+		return w
+	}
+	if w := analyze("missing"); w.Code != http.StatusNotFound {
+		t.Errorf("analyzePluginSource() of unknown plugin status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	w := analyze("kits")
+
+	page := w.Body.String()
+	if w.Code != http.StatusOK || !strings.Contains(page, `value="kit.reset"`) {
+		t.Fatalf("analyzePluginSource() status = %d, page doesn't propose kit.reset:\n%s", w.Code, page)
+	}
+	if !strings.Contains(page, "already saved") {
+		t.Error("analyzePluginSource() doesn't mark the saved /kit command")
+	}
+
+	// save only the new command
+	reviewed := url.Values{
+		"include":     {"1"},
+		"command":     {"/kit", "kit.reset"},
+		"cmdType":     {cmdTypeChat, cmdTypeConsole},
+		"args":        {"", ""},
+		"aliases":     {"", ""},
+		"permission":  {"", "kits.admin"},
+		"description": {"", "Reset kits"},
+	}
+	r := httptest.NewRequest("POST", "/plugins/kits/commands/analyze/save", strings.NewReader(reviewed.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.SetPathValue("pluginSlug", "kits")
+	w = httptest.NewRecorder()
+	s.saveAnalyzedCommands(w, r)
+
+	commands, err := q.GetPluginCommands(ctx, "kits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusFound || len(commands) != 2 {
+		t.Fatalf("saveAnalyzedCommands() status = %d, commands = %+v, want 2 commands", w.Code, commands)
+	}
+	if last := commands[1]; last.Command != "kit.reset" || last.CmdType != cmdTypeConsole || last.Permission != "kits.admin" {
+		t.Errorf("saved command = %+v, want console kit.reset with kits.admin", last)
+	}
+}
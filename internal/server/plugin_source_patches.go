@@ -0,0 +1,240 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"adminrust/internal/database"
+	"adminrust/internal/diff"
+	"adminrust/internal/patch"
+	"adminrust/internal/version"
+)
+
+// Code change with the outcome of applying its hunk to a source
+type appliedCodeChange struct {
+	database.PluginManualCodeChange
+	Lines  []diff.Line
+	Report patch.Report
+}
+
+// Render form for recording code changes from a patched source file
+func (s *Server) recordPluginCodeHunksForm(w http.ResponseWriter, r *http.Request) {
+	sources, err := s.db.Queries().GetPluginSources(r.Context(), r.PathValue("pluginSlug"))
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+	slices.SortStableFunc(sources, func(a, b database.GetPluginSourcesRow) int {
+		return version.Compare(b.Version, a.Version)
+	})
+
+	renderPage(w, "record_plugin_code_hunks", "Record Code Edits", nil, sources)
+}
+
+// Save differences between a stored source and its patched copy
+// as code changes, one per hunk
+func (s *Server) recordPluginCodeHunks(w http.ResponseWriter, r *http.Request) {
+	// read the patched file or Bad Request error
+	_, patched, err := readPluginSource(w, r)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+	base, err := s.getPluginSource(r, r.FormValue("sourceID"))
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	hunks := diff.Hunks(diff.SplitLines(string(base.Content)), diff.SplitLines(string(patched)), diffContext)
+	if len(hunks) == 0 {
+		errorHandler(w, http.StatusUnprocessableEntity, "The file doesn't differ from the stored source")
+		return
+	}
+
+	comment := strings.TrimSpace(r.FormValue("comment"))
+	err = s.db.InTx(r.Context(), func(q *database.Queries) error {
+		for _, hunk := range hunks {
+			// the modified part is what the hunk's rows look like after the edit
+			var modified []string
+			for _, line := range hunk.Lines {
+				if line.Op != diff.Delete {
+					modified = append(modified, line.Text)
+				}
+			}
+
+			_, err := q.AddPluginCodeHunk(r.Context(), database.AddPluginCodeHunkParams{
+				PluginID:     base.PluginID,
+				SourceID:     sql.NullInt64{Int64: base.ID, Valid: true},
+				Hunk:         hunk.String(),
+				ModifiedPart: strings.Join(modified, "\n"),
+				RowStart:     int64(max(hunk.NewStart, 1)),
+				RowEnd:       int64(max(hunk.NewStart+hunk.NewLines-1, 1)),
+				Comment:      comment,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// redirect to plugin page
+	http.Redirect(w, r, fmt.Sprintf("/plugins/%s", r.PathValue("pluginSlug")), http.StatusFound)
+}
+
+// Apply recorded hunks of the plugin to the source content
+func (s *Server) applyCodeHunks(ctx context.Context, pluginSlug string, content []byte) (changes []appliedCodeChange, result patch.Result, err error) {
+	recorded, err := s.db.Queries().GetPluginCodeHunks(ctx, pluginSlug)
+	if err != nil {
+		return nil, result, err
+	}
+
+	hunks := make([]diff.Hunk, len(recorded))
+	for i, change := range recorded {
+		hunks[i], err = diff.ParseHunk(change.Hunk)
+		if err != nil {
+			return nil, result, fmt.Errorf("code change %d: %w", change.ID, err)
+		}
+	}
+
+	result = patch.Apply(diff.SplitLines(string(content)), hunks)
+	for i, change := range recorded {
+		changes = append(changes, appliedCodeChange{
+			PluginManualCodeChange: change,
+			Lines:                  hunks[i].Lines,
+			Report:                 result.Reports[i],
+		})
+	}
+
+	return changes, result, nil
+}
+
+// Apply recorded hunks to the source of the newest stored version and
+// update their relevance and rows, reports whether there were hunks to apply
+func (s *Server) reconcileCodeChanges(ctx context.Context, pluginSlug string, source database.PluginSource) (bool, error) {
+	stored, err := s.db.Queries().GetPluginSource(ctx, database.GetPluginSourceParams{
+		ID:   source.ID,
+		Slug: pluginSlug,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	// changes are relevant to the newest version only
+	sources, err := s.db.Queries().GetPluginSources(ctx, pluginSlug)
+	if err != nil {
+		return false, err
+	}
+	for _, other := range sources {
+		if version.Compare(other.Version, stored.Version) > 0 {
+			return false, nil
+		}
+	}
+
+	changes, _, err := s.applyCodeHunks(ctx, pluginSlug, stored.Content)
+	if err != nil || len(changes) == 0 {
+		return false, err
+	}
+
+	err = s.db.InTx(ctx, func(q *database.Queries) error {
+		for _, change := range changes {
+			params := database.SetPluginCodeChangeRelevanceParams{
+				RowStart: change.RowStart,
+				RowEnd:   change.RowEnd,
+				ID:       change.ID,
+			}
+			// conflicting changes keep their rows for reference
+			if change.Report.Status != patch.Conflict {
+				params.IsRelevant = 1
+				params.RowStart = int64(change.Report.Start)
+				params.RowEnd = int64(max(change.Report.End, change.Report.Start))
+			}
+			if err := q.SetPluginCodeChangeRelevance(ctx, params); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return err == nil, err
+}
+
+// Render report of applying recorded hunks to a stored source
+func (s *Server) patchPluginSource(w http.ResponseWriter, r *http.Request) {
+	source, err := s.getPluginSource(r, r.PathValue("sourceID"))
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	pluginSlug := r.PathValue("pluginSlug")
+	changes, result, err := s.applyCodeHunks(r.Context(), pluginSlug, source.Content)
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	metaData := struct {
+		PluginURL, PatchedURL     string
+		Applied, Moved, Conflicts int
+	}{
+		PluginURL:  fmt.Sprintf("/plugins/%s", pluginSlug),
+		PatchedURL: fmt.Sprintf("/plugins/%s/sources/%d/patched", pluginSlug, source.ID),
+		Applied:    result.Count(patch.Applied),
+		Moved:      result.Count(patch.Moved),
+		Conflicts:  result.Count(patch.Conflict),
+	}
+
+	title := fmt.Sprintf("Code Edits for %s %s", source.FileName, source.Version)
+	renderPage(w, "plugin_source_patch", title, changes, metaData)
+}
+
+// Send stored source with recorded hunks applied
+func (s *Server) getPatchedPluginSource(w http.ResponseWriter, r *http.Request) {
+	source, err := s.getPluginSource(r, r.PathValue("sourceID"))
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	_, result, err := s.applyCodeHunks(r.Context(), r.PathValue("pluginSlug"), source.Content)
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// keep line breaks of the original file
+	lineBreak := "\n"
+	if bytes.Contains(source.Content, []byte("\r\n")) {
+		lineBreak = "\r\n"
+	}
+	merged := strings.Join(result.Lines, lineBreak) + lineBreak
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": source.FileName}))
+	w.Header().Set("Content-Length", strconv.Itoa(len(merged)))
+	if _, err = w.Write([]byte(merged)); err != nil {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,180 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"adminrust/internal/database"
+)
+
+func TestPluginSourcePatches(t *testing.T) {
+	loadTestTemplates(t)
+	ctx := context.Background()
+	s := &Server{db: newTestDB(t)}
+	q := s.db.Queries()
+
+	pluginOrigin, err := q.AddOrigin(ctx, database.AddOriginParams{
+		Name: "uMod", Slug: "umod", Url: "https://umod.org", PathToPluginList: "/plugins",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plugin, err := q.AddPlugin(ctx, database.AddPluginParams{
+		Name: "Kits", Slug: "kits", Url: "https://umod.org/plugins/kits", OriginID: pluginOrigin.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	versionIDs := make(map[string]int64)
+	for _, v := range []string{"1.0.0", "1.1.0", "1.2.0"} {
+		entry, err := q.AddPluginChangelog(ctx, database.AddPluginChangelogParams{
+			PluginID: plugin.ID, Version: v, UpdateDate: "2025-01-01",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		versionIDs[v] = entry.ID
+	}
+
+	// send a multipart form with the source file to the handler
+	send := func(handler http.HandlerFunc, fields map[string]string, source string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		for name, value := range fields {
+			_ = form.WriteField(name, value)
+		}
+		file, err := form.CreateFormFile("source", "Kits.cs")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = file.Write([]byte(source))
+		form.Close()
+
+		r := httptest.NewRequest("POST", "/plugins/kits", &body)
+		r.Header.Set("Content-Type", form.FormDataContentType())
+		r.SetPathValue("pluginSlug", "kits")
+		w := httptest.NewRecorder()
+		handler(w, r)
+		return w
+	}
+	changelogField := func(v string) map[string]string {
+		return map[string]string{"changelogID": fmt.Sprint(versionIDs[v])}
+	}
+
+	original := "class Kits\n{\n    int limit = 1;\n    int cooldown = 60;\n\n    void Init()\n    {\n        Puts(\"loaded\");\n    }\n}\n"
+	patched := strings.Replace(original, "int limit = 1;", "int limit = 5;", 1)
+	if w := send(s.uploadPluginSource, changelogField("1.0.0"), original); w.Code != http.StatusFound {
+		t.Fatalf("uploadPluginSource() status = %d, want %d", w.Code, http.StatusFound)
+	}
+	sources, err := q.GetPluginSources(ctx, "kits")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// record the edit against the first version
+	w := send(s.recordPluginCodeHunks, map[string]string{"sourceID": fmt.Sprint(sources[0].ID), "comment": "Bigger limit"}, patched)
+	if w.Code != http.StatusFound {
+		t.Fatalf("recordPluginCodeHunks() status = %d, want %d", w.Code, http.StatusFound)
+	}
+	w = send(s.recordPluginCodeHunks, map[string]string{"sourceID": fmt.Sprint(sources[0].ID)}, original)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("recordPluginCodeHunks() of unchanged file status = %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+
+	tests := []struct {
+		name               string
+		version            string
+		source             string
+		expectedRelevance  int64
+		expectedRowStart   int64
+		expectedPatchedRow string
+	}{
+		{
+			name:               "lines added above",
+			version:            "1.1.0",
+			source:             "// v1.1.0\n" + original,
+			expectedRelevance:  1,
+			expectedRowStart:   2,
+			expectedPatchedRow: "int limit = 5;",
+		},
+		{
+			name:               "edited line removed",
+			version:            "1.2.0",
+			source:             strings.Replace(original, "    int limit = 1;\n", "", 1),
+			expectedRelevance:  0,
+			expectedRowStart:   2,
+			expectedPatchedRow: "int cooldown = 60;",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := send(s.uploadPluginSource, changelogField(test.version), test.source)
+			location := w.Header().Get("Location")
+			if w.Code != http.StatusFound || !strings.HasSuffix(location, "/patch") {
+				t.Fatalf("uploadPluginSource() status = %d, location = %q, want the patch report", w.Code, location)
+			}
+
+			changes, err := q.GetPluginCodeHunks(ctx, "kits")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(changes) != 1 || changes[0].IsRelevant != test.expectedRelevance || changes[0].RowStart != test.expectedRowStart {
+				t.Errorf("code changes = %+v, want relevance %d from row %d", changes, test.expectedRelevance, test.expectedRowStart)
+			}
+
+			// the report and the merged file are available for the uploaded source
+			sourceID := strings.TrimSuffix(strings.TrimPrefix(location, "/plugins/kits/sources/"), "/patch")
+			for _, handler := range []http.HandlerFunc{s.patchPluginSource, s.getPatchedPluginSource} {
+				r := httptest.NewRequest("GET", location, nil)
+				r.SetPathValue("pluginSlug", "kits")
+				r.SetPathValue("sourceID", sourceID)
+				w = httptest.NewRecorder()
+				handler(w, r)
+				if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), test.expectedPatchedRow) {
+					t.Errorf("status = %d, page doesn't contain %q:\n%s", w.Code, test.expectedPatchedRow, w.Body.String())
+				}
+			}
+		})
+	}
+
+	// uploading an older version doesn't change relevance
+	w = send(s.uploadPluginSource, changelogField("1.0.0"), original)
+	if w.Code != http.StatusFound || w.Header().Get("Location") != "/plugins/kits" {
+		t.Errorf("uploadPluginSource() of older version status = %d, location = %q", w.Code, w.Header().Get("Location"))
+	}
+
+	// the hunk is kept while the modified part is and dropped once it's edited
+	changes, err := q.GetPluginCodeHunks(ctx, "kits")
+	if err != nil || len(changes) != 1 {
+		t.Fatalf("GetPluginCodeHunks() = %+v, %v", changes, err)
+	}
+	for _, test := range []struct {
+		modifiedPart string
+		expectedHunk bool
+	}{{changes[0].ModifiedPart, true}, {"    int limit = 10;", false}} {
+		form := url.Values{
+			"_method": {"PUT"}, "modifiedPart": {test.modifiedPart}, "rowStart": {"3"}, "rowEnd": {"3"},
+			"comment": {"Limit of kits"}, "isRelevant": {"yes"},
+		}
+		r := httptest.NewRequest("POST", "/plugins/kits/code-changes", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.SetPathValue("pluginSlug", "kits")
+		r.SetPathValue("changeID", fmt.Sprint(changes[0].ID))
+		w = httptest.NewRecorder()
+		s.updatePluginCodeChange(w, r)
+		change, err := q.GetPluginCodeChange(ctx, database.GetPluginCodeChangeParams{ID: changes[0].ID, Slug: "kits"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w.Code != http.StatusFound || (change.Hunk != "") != test.expectedHunk {
+			t.Errorf("updatePluginCodeChange() status = %d, hunk = %q, want kept %v", w.Code, change.Hunk, test.expectedHunk)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package server
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"adminrust/internal/database"
+	"adminrust/internal/diff"
+	"adminrust/internal/version"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Number of unchanged lines shown around changes in source diffs
+const diffContext = 3
+
+// Source-related routes
+func (s *Server) registerPluginSourceRoutes(r chi.Router) {
+	r.Route("/sources", func(r chi.Router) {
+		r.Get("/", s.getPluginSources)
+		// uploading
+		r.Get("/upload", s.uploadPluginSourceForm)
+		r.Post("/upload", s.uploadPluginSource)
+		// comparing versions
+		r.Get("/diff", s.diffPluginSources)
+		// applying recorded code changes
+		r.Get("/{sourceID:[0-9]+}/patch", s.patchPluginSource)
+		r.Get("/{sourceID:[0-9]+}/patched", s.getPatchedPluginSource)
+		// downloading and deleting
+		r.Get("/{sourceID:[0-9]+}/raw", s.getPluginSourceFile)
+		r.Delete("/{sourceID:[0-9]+}", s.deletePluginSource)
+	})
+}
+
+// Retrieve stored sources ordered from the newest version
+func (s *Server) getPluginSources(w http.ResponseWriter, r *http.Request) {
+	pluginSlug := r.PathValue("pluginSlug")
+
+	sources, err := s.db.Queries().GetPluginSources(r.Context(), pluginSlug)
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+	slices.SortStableFunc(sources, func(a, b database.GetPluginSourcesRow) int {
+		return version.Compare(b.Version, a.Version)
+	})
+
+	metaData := struct{ CurrentURL string }{
+		CurrentURL: fmt.Sprintf("/plugins/%s/sources", pluginSlug),
+	}
+
+	renderPage(w, "plugin_sources", "", sources, metaData)
+}
+
+// Render form for uploading source of a changelog version
+func (s *Server) uploadPluginSourceForm(w http.ResponseWriter, r *http.Request) {
+	changelog, err := s.db.Queries().GetPluginChangelog(r.Context(), r.PathValue("pluginSlug"))
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+	sortChangelog(changelog)
+
+	renderPage(w, "upload_plugin_source", "Upload Source", nil, changelog)
+}
+
+// Store uploaded source and link it to a changelog version,
+// a source uploaded for the version before is replaced.
+// Recorded code changes are applied to the newest version.
+func (s *Server) uploadPluginSource(w http.ResponseWriter, r *http.Request) {
+	// read the source file or Bad Request error
+	fileName, source, err := readPluginSource(w, r)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+	changelogID, err := strconv.ParseInt(r.FormValue("changelogID"), 10, 64)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	// the version must belong to the plugin
+	pluginSlug := r.PathValue("pluginSlug")
+	entry, err := s.db.Queries().GetPluginChangelogEntry(r.Context(), database.GetPluginChangelogEntryParams{
+		ID:   changelogID,
+		Slug: pluginSlug,
+	})
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	checksum := sha256.Sum256(source)
+	hash := hex.EncodeToString(checksum[:])
+
+	// save the content once per checksum and drop the replaced one if unused
+	var saved database.PluginSource
+	err = s.db.InTx(r.Context(), func(q *database.Queries) error {
+		err := q.AddSourceBlob(r.Context(), database.AddSourceBlobParams{
+			Sha256:  hash,
+			Content: source,
+			Size:    int64(len(source)),
+		})
+		if err != nil {
+			return err
+		}
+		saved, err = q.SetPluginSource(r.Context(), database.SetPluginSourceParams{
+			PluginID:    entry.PluginID,
+			ChangelogID: entry.ID,
+			FileName:    fileName,
+			Sha256:      hash,
+		})
+		if err != nil {
+			return err
+		}
+		return q.DeleteUnusedSourceBlobs(r.Context())
+	})
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// re-apply recorded code changes to the new version and show the report
+	reconciled, err := s.reconcileCodeChanges(r.Context(), pluginSlug, saved)
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+	if reconciled {
+		http.Redirect(w, r, fmt.Sprintf("/plugins/%s/sources/%d/patch", pluginSlug, saved.ID), http.StatusFound)
+		return
+	}
+
+	// redirect to plugin page
+	http.Redirect(w, r, fmt.Sprintf("/plugins/%s", pluginSlug), http.StatusFound)
+}
+
+// Get a stored source by the ID in the path or query parameter
+func (s *Server) getPluginSource(r *http.Request, rawID string) (source database.GetPluginSourceRow, err error) {
+	sourceID, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil {
+		return source, err
+	}
+
+	return s.db.Queries().GetPluginSource(r.Context(), database.GetPluginSourceParams{
+		ID:   sourceID,
+		Slug: r.PathValue("pluginSlug"),
+	})
+}
+
+// Send stored source as a file
+func (s *Server) getPluginSourceFile(w http.ResponseWriter, r *http.Request) {
+	source, err := s.getPluginSource(r, r.PathValue("sourceID"))
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": source.FileName}))
+	w.Header().Set("ETag", strconv.Quote(source.Sha256))
+	if _, err = w.Write(source.Content); err != nil {
+		log.Println(err)
+	}
+}
+
+// Render unified diff between two stored sources of the plugin
+func (s *Server) diffPluginSources(w http.ResponseWriter, r *http.Request) {
+	from, err := s.getPluginSource(r, r.URL.Query().Get("from"))
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+	to, err := s.getPluginSource(r, r.URL.Query().Get("to"))
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	content := struct {
+		From, To database.GetPluginSourceRow
+		Hunks    []diff.Hunk
+	}{
+		From:  from,
+		To:    to,
+		Hunks: diff.Hunks(diff.SplitLines(string(from.Content)), diff.SplitLines(string(to.Content)), diffContext),
+	}
+	metaData := struct{ PluginURL string }{
+		PluginURL: fmt.Sprintf("/plugins/%s", r.PathValue("pluginSlug")),
+	}
+
+	title := fmt.Sprintf("Source Changes %s → %s", from.Version, to.Version)
+	renderPage(w, "plugin_source_diff", title, content, metaData)
+}
+
+// Delete stored source, its content is kept while other versions use it
+func (s *Server) deletePluginSource(w http.ResponseWriter, r *http.Request) {
+	sourceID, err := strconv.ParseInt(r.PathValue("sourceID"), 10, 64)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	pluginSlug := r.PathValue("pluginSlug")
+	err = s.db.InTx(r.Context(), func(q *database.Queries) error {
+		_, err := q.DeletePluginSource(r.Context(), database.DeletePluginSourceParams{
+			ID:   sourceID,
+			Slug: pluginSlug,
+		})
+		if err != nil {
+			return err
+		}
+		return q.DeleteUnusedSourceBlobs(r.Context())
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		notFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// redirect to plugin page on success with HTMX
+	w.Header().Set("HX-Redirect", fmt.Sprintf("/plugins/%s", pluginSlug))
+	w.WriteHeader(http.StatusNoContent)
+}
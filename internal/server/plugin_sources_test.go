@@ -0,0 +1,151 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"adminrust/internal/database"
+)
+
+func TestPluginSourceHandlers(t *testing.T) {
+	loadTestTemplates(t)
+	ctx := context.Background()
+	s := &Server{db: newTestDB(t)}
+	q := s.db.Queries()
+
+	pluginOrigin, err := q.AddOrigin(ctx, database.AddOriginParams{
+		Name: "uMod", Slug: "umod", Url: "https://umod.org", PathToPluginList: "/plugins",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plugin, err := q.AddPlugin(ctx, database.AddPluginParams{
+		Name: "Kits", Slug: "kits", Url: "https://umod.org/plugins/kits", OriginID: pluginOrigin.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var versionIDs []int64
+	for _, v := range []string{"1.0.0", "1.1.0", "1.2.0"} {
+		entry, err := q.AddPluginChangelog(ctx, database.AddPluginChangelogParams{
+			PluginID: plugin.ID, Version: v, UpdateDate: "2025-01-01",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		versionIDs = append(versionIDs, entry.ID)
+	}
+
+	// upload the source for the changelog version
+	upload := func(changelogID int64, fileName, source string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		_ = form.WriteField("changelogID", fmt.Sprint(changelogID))
+		file, err := form.CreateFormFile("source", fileName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = file.Write([]byte(source))
+		form.Close()
+
+		r := httptest.NewRequest("POST", "/plugins/kits/sources/upload", &body)
+		r.Header.Set("Content-Type", form.FormDataContentType())
+		r.SetPathValue("pluginSlug", "kits")
+		w := httptest.NewRecorder()
+		s.uploadPluginSource(w, r)
+		return w
+	}
+
+	tests := []struct {
+		name         string
+		changelogID  int64
+		fileName     string
+		source       string
+		expectedCode int
+	}{
+		{name: "first version", changelogID: versionIDs[0], fileName: "Kits.cs", source: "class Kits\n{\n    int limit = 1;\n}\n", expectedCode: http.StatusFound},
+		{name: "second version", changelogID: versionIDs[1], fileName: "Kits.cs", source: "class Kits\n{\n    int limit = 2;\n}\n", expectedCode: http.StatusFound},
+		{name: "same content", changelogID: versionIDs[2], fileName: "Kits.cs", source: "class Kits\n{\n    int limit = 2;\n}\n", expectedCode: http.StatusFound},
+		{name: "unknown version", changelogID: 100, fileName: "Kits.cs", source: "class Kits {}", expectedCode: http.StatusNotFound},
+		{name: "not C#", changelogID: versionIDs[0], fileName: "Kits.json", source: "{}", expectedCode: http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := upload(test.changelogID, test.fileName, test.source)
+			if w.Code != test.expectedCode {
+				t.Errorf("uploadPluginSource() status = %d, want %d", w.Code, test.expectedCode)
+			}
+		})
+	}
+
+	sources, err := q.GetPluginSources(ctx, "kits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sources) != 3 || sources[1].Sha256 != sources[2].Sha256 {
+		t.Fatalf("GetPluginSources() = %+v, want 3 sources with equal content of the last two", sources)
+	}
+
+	// compare the first and the second versions
+	r := httptest.NewRequest("GET", fmt.Sprintf("/plugins/kits/sources/diff?from=%d&to=%d", sources[0].ID, sources[1].ID), nil)
+	r.SetPathValue("pluginSlug", "kits")
+	w := httptest.NewRecorder()
+	s.diffPluginSources(w, r)
+	page := w.Body.String()
+	if w.Code != http.StatusOK || !strings.Contains(page, "-    int limit = 1;") {
+		t.Errorf("diffPluginSources() status = %d, page doesn't show the changed line:\n%s", w.Code, page)
+	}
+
+	// deleting a source keeps the content used by another version
+	countBlobs := func() (count int) {
+		if err = s.db.(*testDB).db.QueryRow("SELECT count(*) FROM source_blobs").Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+		return count
+	}
+	for _, source := range sources[1:] {
+		r = httptest.NewRequest("DELETE", fmt.Sprintf("/plugins/kits/sources/%d", source.ID), nil)
+		r.SetPathValue("pluginSlug", "kits")
+		r.SetPathValue("sourceID", fmt.Sprint(source.ID))
+		w = httptest.NewRecorder()
+		s.deletePluginSource(w, r)
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("deletePluginSource() status = %d, want %d", w.Code, http.StatusNoContent)
+		}
+
+		blobCount := countBlobs()
+		if blobCount != 2 && source.ID == sources[1].ID {
+			t.Errorf("%d blobs after deleting a source with shared content, want 2", blobCount)
+		}
+		if blobCount != 1 && source.ID == sources[2].ID {
+			t.Errorf("%d blobs after deleting the last source using content, want 1", blobCount)
+		}
+	}
+
+	// contents of sources deleted with their version or plugin go too
+	r = httptest.NewRequest("DELETE", fmt.Sprintf("/plugins/kits/changelog/%d", versionIDs[0]), nil)
+	r.SetPathValue("pluginSlug", "kits")
+	r.SetPathValue("changelogID", fmt.Sprint(versionIDs[0]))
+	w = httptest.NewRecorder()
+	s.deletePluginChangelog(w, r)
+	if w.Code != http.StatusNoContent || countBlobs() != 0 {
+		t.Errorf("deletePluginChangelog() status = %d, %d blobs left, want none", w.Code, countBlobs())
+	}
+
+	if w = upload(versionIDs[1], "Kits.cs", "class Kits {}"); w.Code != http.StatusFound {
+		t.Fatalf("uploadPluginSource() status = %d, want %d", w.Code, http.StatusFound)
+	}
+	r = httptest.NewRequest("DELETE", "/plugins/kits", nil)
+	r.SetPathValue("pluginSlug", "kits")
+	w = httptest.NewRecorder()
+	s.deletePlugin(w, r)
+	if w.Code != http.StatusNoContent || countBlobs() != 0 {
+		t.Errorf("deletePlugin() status = %d, %d blobs left, want none", w.Code, countBlobs())
+	}
+}
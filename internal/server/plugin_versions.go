@@ -0,0 +1,151 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"adminrust/internal/database"
+	"adminrust/internal/origin"
+	"adminrust/internal/version"
+)
+
+// Date layouts origins use for release dates
+var releaseDateLayouts = []string{
+	time.RFC3339,
+	time.DateTime,
+	time.DateOnly,
+}
+
+// Result of checking plugin versions
+type versionCheckReport struct {
+	Checked     int
+	NewVersions []string
+	// plugins that couldn't be checked with the reason
+	Failed []string
+}
+
+// Fetch the latest release of the plugin from its origin and save it to
+// the changelog if it's newer than the latest entry. Returns a new version
+// or empty string.
+func (s *Server) checkPluginVersion(ctx context.Context, plugin database.Plugin, adapter origin.Adapter) (newVersion string, err error) {
+	release, err := adapter.FetchRelease(ctx, plugin.Url)
+	if err != nil {
+		return "", err
+	}
+	// unparsable versions can't be compared with the changelog ones
+	if _, err = version.Parse(release.Version); err != nil {
+		return "", fmt.Errorf("origin release: %w", err)
+	}
+
+	// a version is new if it's newer than the latest one in the changelog
+	changelog, err := s.db.Queries().GetPluginChangelog(ctx, plugin.Slug)
+	if err != nil {
+		return "", err
+	}
+	if latest, ok := latestVersion(changelog); ok && version.Compare(release.Version, latest) <= 0 {
+		return "", nil
+	}
+
+	// the new version has to be deployed to the server
+	err = s.db.InTx(ctx, func(q *database.Queries) error {
+		_, err := q.AddPluginChangelog(ctx, database.AddPluginChangelogParams{
+			PluginID:   plugin.ID,
+			Version:    release.Version,
+			Changelog:  release.Changelog,
+			UpdateDate: releaseDate(release.Date),
+		})
+		if err != nil {
+			return err
+		}
+		return q.SetPluginOutdated(ctx, plugin.ID)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return release.Version, nil
+}
+
+// Check versions of the given plugins, origins are requested only once
+func (s *Server) checkPluginVersions(ctx context.Context, plugins []database.Plugin) (report versionCheckReport) {
+	originAdapters := map[int64]origin.Adapter{}
+	for _, plugin := range plugins {
+		// imported plugins have no webpage until their origin lists them
+		if plugin.Url == "" {
+			continue
+		}
+		adapter, exists := originAdapters[plugin.OriginID]
+		if !exists {
+			pluginOrigin, err := s.db.Queries().GetOriginByID(ctx, plugin.OriginID)
+			if err == nil {
+				adapter, err = origin.AdapterFor(pluginOrigin)
+			}
+			if err != nil {
+				log.Printf("No adapter for origin %d: %s\n", plugin.OriginID, err)
+			}
+			originAdapters[plugin.OriginID] = adapter
+		}
+		if adapter == nil {
+			report.Failed = append(report.Failed, fmt.Sprintf("%s: %s", plugin.Name, origin.ErrNoAdapter))
+			continue
+		}
+
+		newVersion, err := s.checkPluginVersion(ctx, plugin, adapter)
+		if err != nil {
+			log.Printf("Error checking version of %s: %s\n", plugin.Name, err)
+			report.Failed = append(report.Failed, fmt.Sprintf("%s: %s", plugin.Name, err))
+			continue
+		}
+		report.Checked++
+		if newVersion != "" {
+			report.NewVersions = append(report.NewVersions, fmt.Sprintf("%s %s", plugin.Name, newVersion))
+		}
+	}
+
+	return report
+}
+
+// Convert a release date to the changelog date format, today is used
+// if the origin date is missing or unknown
+func releaseDate(rawDate string) string {
+	for _, layout := range releaseDateLayouts {
+		if date, err := time.Parse(layout, rawDate); err == nil {
+			return date.Format(time.DateOnly)
+		}
+	}
+
+	return time.Now().Format(time.DateOnly)
+}
+
+// Check versions of all plugins from the origin and render a report fragment
+func (s *Server) checkOriginVersions(w http.ResponseWriter, r *http.Request) {
+	originSlug := r.PathValue("originSlug")
+	pluginOrigin, err := s.db.Queries().GetOrigin(r.Context(), originSlug)
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	plugins, err := s.db.Queries().GetOriginPlugins(r.Context(), pluginOrigin.ID)
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// every plugin page is requested, which takes longer than a usual request
+	rc := http.NewResponseController(w)
+	if err = rc.SetWriteDeadline(time.Now().Add(originSyncTimeout)); err != nil {
+		log.Println(err)
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), originSyncTimeout)
+	defer cancel()
+
+	report := s.checkPluginVersions(ctx, plugins)
+
+	renderPage(w, "version_check", "Version Check", report, nil)
+}
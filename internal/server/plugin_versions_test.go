@@ -0,0 +1,79 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"adminrust/internal/database"
+)
+
+func TestCheckPluginVersions(t *testing.T) {
+	release := `{"latest_release_version": "4.4.2", "latest_release_at": "2025-03-01T10:00:00Z", "latest_release_changelog": "Fixed kits"}`
+	standIn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/plugins/kits.json" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(release))
+	}))
+	defer standIn.Close()
+
+	ctx := context.Background()
+	s := &Server{db: newTestDB(t)}
+	q := s.db.Queries()
+
+	umod, err := q.AddOrigin(ctx, database.AddOriginParams{
+		Name: "uMod", Slug: "umod", Url: standIn.URL, PathToPluginList: "/plugins/search.json", HasApi: 1, Adapter: "umod",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plugin, err := q.AddPlugin(ctx, database.AddPluginParams{
+		Name: "Kits", Slug: "kits", Url: standIn.URL + "/plugins/kits", OriginID: umod.ID, IsUpdatedOnServer: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := s.checkPluginVersions(ctx, []database.Plugin{plugin})
+	if report.Checked != 1 || len(report.NewVersions) != 1 || len(report.Failed) != 0 {
+		t.Fatalf("checkPluginVersions() report = %+v, want one new version", report)
+	}
+
+	changelog, err := q.GetPluginChangelog(ctx, "kits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changelog) != 1 || changelog[0].Version != "4.4.2" || changelog[0].UpdateDate != "2025-03-01" {
+		t.Errorf("changelog = %+v, want version 4.4.2 at 2025-03-01", changelog)
+	}
+	plugin, err = q.GetPlugin(ctx, "kits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plugin.IsUpdatedOnServer != 0 {
+		t.Error("plugin with a new version is still marked as updated on server")
+	}
+
+	// the same version isn't added twice
+	report = s.checkPluginVersions(ctx, []database.Plugin{plugin})
+	if report.Checked != 1 || len(report.NewVersions) != 0 {
+		t.Errorf("repeated checkPluginVersions() report = %+v, want no new versions", report)
+	}
+
+	// an origin rolled back to an older release
+	release = `{"latest_release_version": "4.4.0", "latest_release_at": "2025-02-01T10:00:00Z"}`
+	report = s.checkPluginVersions(ctx, []database.Plugin{plugin})
+	if report.Checked != 1 || len(report.NewVersions) != 0 {
+		t.Errorf("checkPluginVersions() of older release report = %+v, want no new versions", report)
+	}
+
+	// a version that can't be compared fails instead of being skipped
+	release = `{"latest_release_version": "4.5.0b", "latest_release_at": "2025-04-01T10:00:00Z"}`
+	report = s.checkPluginVersions(ctx, []database.Plugin{plugin})
+	if report.Checked != 0 || len(report.Failed) != 1 {
+		t.Errorf("checkPluginVersions() of invalid version report = %+v, want the plugin failed", report)
+	}
+}
@@ -2,6 +2,8 @@ package server
 
 import (
 	"adminrust/internal/database"
+	"adminrust/internal/version"
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -30,29 +32,92 @@ func (s *Server) registerPluginRoutes(r *chi.Mux) {
 
 			// changelogs-related
 			s.registerPluginChangelogRoutes(r)
+			// sources-related
+			s.registerPluginSourceRoutes(r)
 			// commands-related
 			s.registerPluginCmdRoutes(r)
+			// permissions-related
+			s.registerPluginPermissionRoutes(r)
+			// images-related
+			s.registerPluginImageRoutes(r)
 			// docs-related
 			s.registerPluginDocRoutes(r)
 			// config-related
 			s.registerPluginCfgRoutes(r)
 			// locale-related
 			s.registerPluginLocaleRoutes(r)
+			// code edits-related
+			s.registerPluginCodeChangeRoutes(r)
+			// RCON actions-related
+			s.registerPluginRconRoutes(r)
+			// hook time charts of servers
+			r.Get("/hook-times", s.getPluginHookTimes)
 		})
 	})
 }
 
 // Render a list of plugins
 func (s *Server) getPlugins(w http.ResponseWriter, r *http.Request) {
-	plugins, err := s.db.Queries().GetPlugins(r.Context())
+	plugins, err := s.db.Queries().GetPluginsWithCovers(r.Context())
 	if err != nil {
 		log.Println(err)
 		internalServerErr(w)
 		return
 	}
 
+	// without servers recorded the update flag is set by hand
+	hasServers, err := s.hasServers(r.Context())
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+	outdated, err := s.outdatedInstallations(r.Context())
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+	meta := struct {
+		HasServers bool
+		// number of servers with an outdated version by plugin IDs
+		Outdated map[int64]int
+	}{hasServers, outdated}
+
 	// render plugins page
-	renderPage(w, "plugins", "Plugins", plugins, nil)
+	renderPage(w, "plugins", "Plugins", plugins, meta)
+}
+
+// Whether any server is recorded, plugins are updated per server then
+func (s *Server) hasServers(ctx context.Context) (bool, error) {
+	count, err := s.db.Queries().CountServers(ctx)
+	return count > 0, err
+}
+
+// Count servers with an outdated version of every plugin
+func (s *Server) outdatedInstallations(ctx context.Context) (map[int64]int, error) {
+	changelogVersions, err := s.db.Queries().GetChangelogVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	versions := make(map[int64][]string)
+	for _, entry := range changelogVersions {
+		versions[entry.PluginID] = append(versions[entry.PluginID], entry.Version)
+	}
+	installed, err := s.db.Queries().GetInstalledVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	outdated := make(map[int64]int)
+	for _, installation := range installed {
+		latest, _ := version.Latest(versions[installation.PluginID])
+		if installStatus(installation.Version, latest) == installOutdated {
+			outdated[installation.PluginID]++
+		}
+	}
+
+	return outdated, nil
 }
 
 // Render a detailed page for a specific plugin by its ID
@@ -65,8 +130,30 @@ func (s *Server) getPlugin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// whether the plugin is updated is answered for every server
+	installations, err := s.pluginInstallations(r.Context(), plugin)
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+	// who reloaded what and when
+	actions, err := s.db.Queries().GetPluginRconActions(r.Context(), database.GetPluginRconActionsParams{
+		Slug:  pluginSlug,
+		Limit: rconActionHistory,
+	})
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+	meta := struct {
+		Installations []serverInstallation
+		RconActions   []database.GetPluginRconActionsRow
+	}{installations, actions}
+
 	// populate and render detailed origin page
-	renderPage(w, "plugin", plugin.Name, plugin, nil)
+	renderPage(w, "plugin", plugin.Name, plugin, meta)
 }
 
 // Render a page with plugin addition form
@@ -78,10 +165,16 @@ func (s *Server) addPluginForm(w http.ResponseWriter, r *http.Request) {
 		internalServerErr(w)
 		return
 	}
-	meta := struct{ Origins []database.PluginOrigin }{}
-	if origins != nil {
-		meta = struct{ Origins []database.PluginOrigin }{origins}
+	hasServers, err := s.hasServers(r.Context())
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
 	}
+	meta := struct {
+		Origins    []database.PluginOrigin
+		HasServers bool
+	}{origins, hasServers}
 
 	// populate and render plugin addition form
 	renderPage(w, "add_plugin", "Add Plugin", nil, meta)
@@ -136,8 +229,15 @@ func (s *Server) addPlugin(w http.ResponseWriter, r *http.Request) {
 		Url:         url,
 		OriginID:    int64(originId),
 	}
+	// with servers recorded updates are tracked by their installations
+	hasServers, err := s.hasServers(r.Context())
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
 	isUpdatedOnServer := r.FormValue("isUpdatedOnServer")
-	if isUpdatedOnServer == "yes" {
+	if !hasServers && isUpdatedOnServer == "yes" {
 		pluginParams.IsUpdatedOnServer = 1
 	}
 
@@ -177,11 +277,17 @@ func (s *Server) updatePluginForm(w http.ResponseWriter, r *http.Request) {
 		internalServerErr(w)
 		return
 	}
-	// use available origins as meta data in form
-	meta := struct{ Origins []origin }{}
-	if origins != nil {
-		meta = struct{ Origins []origin }{origins}
+	hasServers, err := s.hasServers(r.Context())
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
 	}
+	// use available origins as meta data in form
+	meta := struct {
+		Origins    []origin
+		HasServers bool
+	}{origins, hasServers}
 
 	// convert plugin with origins to a proper Plugin struct
 	plugin := database.Plugin{
@@ -245,8 +351,23 @@ func (s *Server) updatePlugin(w http.ResponseWriter, r *http.Request) {
 		OriginID:    int64(originId),
 		Slug:        pluginSlug,
 	}
-	isUpdatedOnServer := r.FormValue("isUpdatedOnServer")
-	if isUpdatedOnServer == "yes" {
+	// with servers recorded updates are tracked by their installations
+	// and the flag set by hand is kept as it was
+	hasServers, err := s.hasServers(r.Context())
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+	if hasServers {
+		current, err := s.db.Queries().GetPlugin(r.Context(), pluginSlug)
+		if err != nil {
+			log.Println(err)
+			notFound(w, r)
+			return
+		}
+		updPluginParams.IsUpdatedOnServer = current.IsUpdatedOnServer
+	} else if r.FormValue("isUpdatedOnServer") == "yes" {
 		updPluginParams.IsUpdatedOnServer = 1
 	}
 
@@ -265,7 +386,13 @@ func (s *Server) updatePlugin(w http.ResponseWriter, r *http.Request) {
 // Delete plugin by its ID and redirect to the plugin list page
 func (s *Server) deletePlugin(w http.ResponseWriter, r *http.Request) {
 	pluginSlug := r.PathValue("pluginSlug")
-	_, err := s.db.Queries().DeletePlugin(r.Context(), pluginSlug)
+	// contents of the deleted sources aren't needed by other plugins
+	err := s.db.InTx(r.Context(), func(q *database.Queries) error {
+		if _, err := q.DeletePlugin(r.Context(), pluginSlug); err != nil {
+			return err
+		}
+		return q.DeleteUnusedSourceBlobs(r.Context())
+	})
 	if err != nil {
 		log.Println(err)
 		internalServerErr(w)
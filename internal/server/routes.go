@@ -39,6 +39,19 @@ func (s *Server) RegisterRoutes() http.Handler {
 	// plugin-related routes
 	s.registerPluginRoutes(r)
 
+	// servers and plugins installed on them
+	s.registerServerRoutes(r)
+
+	// export and import of plugin files
+	s.registerExportRoutes(r)
+	s.registerImportRoutes(r)
+
+	// locale completeness report
+	s.registerLocaleReportRoutes(r)
+
+	// background job routes
+	s.registerJobRoutes(r)
+
 	r.Get("/health", s.healthHandler)
 
 	r.NotFound(notFound)
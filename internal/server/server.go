@@ -1,7 +1,10 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"os"
 	"strconv"
@@ -10,25 +13,47 @@ import (
 	_ "github.com/joho/godotenv/autoload"
 
 	"adminrust/internal/database"
+	"adminrust/internal/scheduler"
 )
 
 type Server struct {
 	port int
 
-	db database.Service
+	db        database.Service
+	scheduler *scheduler.Scheduler
+	rcon      rconClients
 }
 
-func NewServer() *http.Server {
+// Create HTTP server and start background job scheduler.
+//
+// The returned Server should be stopped on shutdown.
+func NewServer() (*http.Server, *Server) {
 	port, _ := strconv.Atoi(os.Getenv("PORT"))
+	db := database.NewDbService()
 	NewServer := &Server{
 		port: port,
 
-		db: database.NewDbService(),
+		db:        db,
+		scheduler: scheduler.New(db.Queries()),
 	}
 
 	// parse and cache templates
 	loadTemplates()
 
+	// actions running at shutdown won't be finished anymore
+	err := db.Queries().FailUnfinishedRconActions(context.Background(), "interrupted by restart\n")
+	if err != nil {
+		log.Println(err)
+	}
+
+	// register and start background jobs
+	for _, job := range NewServer.backgroundJobs() {
+		if err := NewServer.scheduler.Register(context.Background(), job); err != nil {
+			log.Fatal(err)
+		}
+	}
+	NewServer.scheduler.Start()
+
 	// declare Server config
 	server := &http.Server{
 		Addr:         fmt.Sprintf(":%d", NewServer.port),
@@ -38,5 +63,34 @@ func NewServer() *http.Server {
 		WriteTimeout: 30 * time.Second,
 	}
 
-	return server
+	return server, NewServer
+}
+
+// Interrupt background jobs and wait for them and for plugin actions
+// running over RCON until the context is done, then close RCON clients
+func (s *Server) Stop(ctx context.Context) error {
+	var errs []error
+	if err := s.scheduler.Stop(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("job scheduler: %w", err))
+	}
+
+	finished := make(chan struct{})
+	go func() {
+		s.rcon.running.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("RCON actions: %w", ctx.Err()))
+	}
+
+	s.rcon.mu.Lock()
+	defer s.rcon.mu.Unlock()
+	for serverID, client := range s.rcon.clients {
+		client.Close()
+		delete(s.rcon.clients, serverID)
+	}
+
+	return errors.Join(errs...)
 }
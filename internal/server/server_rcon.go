@@ -0,0 +1,107 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"adminrust/internal/database"
+	"adminrust/internal/rcon"
+)
+
+// Returned for servers without an RCON host
+var errNoRcon = errors.New("no RCON host set for the server")
+
+// RCON hosts are host names or IP addresses
+var validateRconHost = validateByPattern(`^([a-zA-Z0-9-]+(\.[a-zA-Z0-9-]+)*|[0-9a-fA-F:]+)$`)
+
+// Open RCON clients by server IDs, connections are reused between requests
+type rconClients struct {
+	mu      sync.Mutex
+	clients map[int64]*rcon.Client
+	// plugin actions running in the background
+	running sync.WaitGroup
+}
+
+// Return the RCON client of the server, replacing the one opened
+// before the RCON details were changed
+func (s *Server) rconClient(server database.Server) (*rcon.Client, error) {
+	if server.RconHost == "" {
+		return nil, errNoRcon
+	}
+	addr := net.JoinHostPort(server.RconHost, strconv.FormatInt(server.RconPort, 10))
+
+	s.rcon.mu.Lock()
+	defer s.rcon.mu.Unlock()
+
+	client, exists := s.rcon.clients[server.ID]
+	if exists && client.Addr == addr && client.Password == server.RconPassword {
+		return client, nil
+	}
+	if exists {
+		client.Close()
+	}
+	if s.rcon.clients == nil {
+		s.rcon.clients = make(map[int64]*rcon.Client)
+	}
+	client = rcon.New(server.RconHost, int(server.RconPort), server.RconPassword)
+	s.rcon.clients[server.ID] = client
+
+	return client, nil
+}
+
+// Close and forget the RCON client of the server, e.g. after deleting it
+func (s *Server) dropRconClient(serverID int64) {
+	s.rcon.mu.Lock()
+	defer s.rcon.mu.Unlock()
+
+	if client, exists := s.rcon.clients[serverID]; exists {
+		client.Close()
+		delete(s.rcon.clients, serverID)
+	}
+}
+
+// Part of the serverinfo command output shown on the server page
+type rconServerInfo struct {
+	Hostname   string
+	Map        string
+	Players    int
+	MaxPlayers int
+	Queued     int
+	Uptime     int
+	Protocol   string
+}
+
+// Check the RCON connection with the serverinfo command and render
+// a status fragment
+func (s *Server) checkServerRcon(w http.ResponseWriter, r *http.Request) {
+	serverSlug := r.PathValue("serverSlug")
+	server, err := s.db.Queries().GetServer(r.Context(), serverSlug)
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	var info rconServerInfo
+	meta := struct{ Error, Output string }{}
+	client, err := s.rconClient(server)
+	if err == nil {
+		var response rcon.Message
+		response, err = client.Execute(r.Context(), "serverinfo")
+		// other games and old servers may answer with plain text
+		if err == nil && json.Unmarshal([]byte(response.Message), &info) != nil {
+			meta.Output = response.Message
+		}
+	}
+	if err != nil {
+		log.Printf("Error checking RCON of %s: %s\n", serverSlug, err)
+		meta.Error = err.Error()
+	}
+
+	renderPage(w, "server_rcon_check", "RCON Connection", info, meta)
+}
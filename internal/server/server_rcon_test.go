@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"adminrust/internal/database"
+	"adminrust/internal/rcon"
+	"adminrust/internal/scheduler"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestCheckServerRcon(t *testing.T) {
+	// stand-in for a Rust server answering serverinfo
+	upgrader := websocket.Upgrader{}
+	standIn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/secret" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			var request rcon.Message
+			if err := conn.ReadJSON(&request); err != nil {
+				return
+			}
+			info := `{"Hostname": "Main Vanilla", "Map": "Procedural Map", "Players": 12, "MaxPlayers": 100}`
+			_ = conn.WriteJSON(rcon.Message{Identifier: request.Identifier, Message: info, Type: "Generic"})
+		}
+	}))
+	defer standIn.Close()
+	host, rawPort, err := net.SplitHostPort(strings.TrimPrefix(standIn.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, _ := strconv.ParseInt(rawPort, 10, 64)
+
+	loadTestTemplates(t)
+	ctx := context.Background()
+	s := &Server{db: newTestDB(t)}
+	q := s.db.Queries()
+	servers := []database.AddServerParams{
+		{Name: "Main", Slug: "main", Framework: "oxide", RconHost: host, RconPort: port, RconPassword: "secret"},
+		{Name: "Wrong", Slug: "wrong", Framework: "oxide", RconHost: host, RconPort: port, RconPassword: "guess"},
+		{Name: "Offline", Slug: "offline", Framework: "oxide"},
+	}
+	for _, params := range servers {
+		if _, err = q.AddServer(ctx, params); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		serverSlug    string
+		expectedParts []string
+	}{
+		{serverSlug: "main", expectedParts: []string{"Main Vanilla", "12 / 100"}},
+		{serverSlug: "wrong", expectedParts: []string{"RCON failed", rcon.ErrUnauthorized.Error()}},
+		{serverSlug: "offline", expectedParts: []string{"RCON failed", errNoRcon.Error()}},
+	}
+	for _, test := range tests {
+		t.Run(test.serverSlug, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/servers/"+test.serverSlug+"/rcon/check", nil)
+			r.SetPathValue("serverSlug", test.serverSlug)
+			w := httptest.NewRecorder()
+			s.checkServerRcon(w, r)
+			for _, part := range test.expectedParts {
+				if !strings.Contains(w.Body.String(), part) {
+					t.Errorf("body misses %q:\n%s", part, w.Body.String())
+				}
+			}
+		})
+	}
+
+	// clients are reused until RCON details change
+	server, err := q.GetServer(ctx, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := s.rconClient(server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := s.rconClient(server); again != first {
+		t.Error("rconClient() created a new client for the same details")
+	}
+	server.RconPassword = "changed"
+	if changed, _ := s.rconClient(server); changed == first {
+		t.Error("rconClient() reused the client after the password changed")
+	}
+
+	// deleting the server closes its client, deleting it again finds nothing
+	for _, expectedCode := range []int{http.StatusNoContent, http.StatusNotFound} {
+		r := httptest.NewRequest("DELETE", "/servers/main", nil)
+		r.SetPathValue("serverSlug", "main")
+		w := httptest.NewRecorder()
+		s.deleteServer(w, r)
+		if w.Code != expectedCode {
+			t.Errorf("deleteServer() status = %d, want %d", w.Code, expectedCode)
+		}
+	}
+	if _, exists := s.rcon.clients[server.ID]; exists {
+		t.Error("client of the deleted server is kept")
+	}
+}
+
+func TestServerStop(t *testing.T) {
+	db := newTestDB(t)
+	s := &Server{db: db, scheduler: scheduler.New(db.Queries())}
+	client, err := s.rconClient(database.Server{ID: 1, RconHost: "127.0.0.1", RconPort: 28016})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// an action that doesn't finish in time holds the stop up to the timeout
+	s.rcon.running.Add(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err = s.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Stop() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if _, err = client.Execute(context.Background(), "serverinfo"); !errors.Is(err, rcon.ErrClosed) {
+		t.Errorf("Execute() after Stop() error = %v, want %v", err, rcon.ErrClosed)
+	}
+	if len(s.rcon.clients) != 0 {
+		t.Errorf("clients after Stop() = %d, want none", len(s.rcon.clients))
+	}
+
+	s.rcon.running.Done()
+	if err = s.Stop(context.Background()); err != nil {
+		t.Errorf("Stop() with nothing running error = %v", err)
+	}
+}
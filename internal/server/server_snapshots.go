@@ -0,0 +1,267 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"adminrust/internal/database"
+	"adminrust/internal/oxide"
+	"adminrust/internal/version"
+)
+
+// Plugin of a server snapshot matched against the known plugins
+type reconciledPlugin struct {
+	// known plugin, empty for unknown ones
+	PluginName, PluginSlug string
+	// as reported by the server
+	Title, FileName, Version string
+	HookTime                 time.Duration
+	Error                    string
+	// installation recorded for the server
+	RecordedVersion string
+	LatestVersion   string
+}
+
+// Plugins a server reported loaded compared with the known and
+// recorded ones
+type serverReconciliation struct {
+	Server database.GetServersRow
+	// when the snapshot was taken, empty without one
+	SnapshotAt string
+	// loaded plugins that aren't known
+	Unknown []reconciledPlugin
+	// recorded installations that aren't loaded or failed to load
+	Missing []reconciledPlugin
+	// loaded plugins older than the latest changelog version
+	Outdated []reconciledPlugin
+	// loaded plugins of the latest or an unknown version
+	UpToDate []reconciledPlugin
+}
+
+// Match the snapshot plugins with the known ones by the names Oxide
+// knows them by
+func reconcilePlugins(
+	loaded []database.ServerSnapshotPlugin,
+	known []database.GetPluginFileNamesRow,
+	installed []database.GetServerPluginsRow,
+	versions map[int64][]string,
+) (reconciliation serverReconciliation) {
+	// Oxide names are case-insensitive
+	byName := make(map[string]database.GetPluginFileNamesRow, len(known))
+	for _, plugin := range known {
+		byName[strings.ToLower(oxide.PluginName(plugin.FileName, plugin.Name))] = plugin
+	}
+	recorded := make(map[int64]string, len(installed))
+	for _, installation := range installed {
+		recorded[installation.PluginID] = installation.Version
+	}
+
+	seen := make(map[int64]bool)
+	for _, listed := range loaded {
+		entry := reconciledPlugin{
+			Title:    listed.Title,
+			FileName: listed.FileName,
+			Version:  listed.Version,
+			HookTime: time.Duration(listed.HookTime * float64(time.Second)),
+			Error:    listed.Error,
+		}
+		plugin, ok := byName[strings.ToLower(oxide.PluginName(listed.FileName, listed.Title))]
+		if !ok {
+			reconciliation.Unknown = append(reconciliation.Unknown, entry)
+			continue
+		}
+		seen[plugin.ID] = true
+		entry.PluginName = plugin.Name
+		entry.PluginSlug = plugin.Slug
+		entry.RecordedVersion = recorded[plugin.ID]
+		entry.LatestVersion, _ = version.Latest(versions[plugin.ID])
+
+		switch {
+		case listed.Error != "":
+			reconciliation.Missing = append(reconciliation.Missing, entry)
+		case entry.LatestVersion != "" && version.Compare(entry.Version, entry.LatestVersion) < 0:
+			reconciliation.Outdated = append(reconciliation.Outdated, entry)
+		default:
+			reconciliation.UpToDate = append(reconciliation.UpToDate, entry)
+		}
+	}
+
+	for _, installation := range installed {
+		if seen[installation.PluginID] {
+			continue
+		}
+		latest, _ := version.Latest(versions[installation.PluginID])
+		reconciliation.Missing = append(reconciliation.Missing, reconciledPlugin{
+			PluginName:      installation.Name,
+			PluginSlug:      installation.Slug,
+			RecordedVersion: installation.Version,
+			LatestVersion:   latest,
+		})
+	}
+
+	return reconciliation
+}
+
+// Returned for servers whose plugin lists can't be read, only the
+// Oxide list format is known
+var errNoPluginList = errors.New("plugin lists are only read from Oxide servers")
+
+// Ask the server for its loaded plugins and the ones that failed to load
+func (s *Server) listServerPlugins(ctx context.Context, server database.Server) ([]oxide.ListedPlugin, error) {
+	if server.Framework != "oxide" {
+		return nil, errNoPluginList
+	}
+	client, err := s.rconClient(server)
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.Execute(ctx, oxide.PluginsCommand)
+	if err != nil {
+		return nil, err
+	}
+
+	return oxide.ParsePluginList(response.Message)
+}
+
+// Record hook times of the loaded plugins as samples
+func addHookTimeSamples(ctx context.Context, q *database.Queries, serverID int64, listed []oxide.ListedPlugin) error {
+	for _, plugin := range listed {
+		if plugin.Error != "" {
+			continue
+		}
+		err := q.AddHookTimeSample(ctx, database.AddHookTimeSampleParams{
+			ServerID: serverID,
+			Title:    plugin.Title,
+			FileName: plugin.FileName,
+			Version:  plugin.Version,
+			HookTime: plugin.HookTime.Seconds(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Ask the server for its plugins and store them as a new snapshot,
+// hook times of the loaded ones are sampled too
+func (s *Server) snapshotServerPlugins(ctx context.Context, server database.Server) (snapshot database.ServerSnapshot, err error) {
+	listed, err := s.listServerPlugins(ctx, server)
+	if err != nil {
+		return snapshot, err
+	}
+
+	err = s.db.InTx(ctx, func(q *database.Queries) error {
+		snapshot, err = q.AddServerSnapshot(ctx, server.ID)
+		if err != nil {
+			return err
+		}
+		for _, plugin := range listed {
+			err = q.AddServerSnapshotPlugin(ctx, database.AddServerSnapshotPluginParams{
+				SnapshotID: snapshot.ID,
+				Title:      plugin.Title,
+				Version:    plugin.Version,
+				Author:     plugin.Author,
+				HookTime:   plugin.HookTime.Seconds(),
+				FileName:   plugin.FileName,
+				Error:      plugin.Error,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return addHookTimeSamples(ctx, q, server.ID, listed)
+	})
+
+	return snapshot, err
+}
+
+// Take a snapshot of the server plugins and show the reconciliation
+func (s *Server) takeServerSnapshot(w http.ResponseWriter, r *http.Request) {
+	serverSlug := r.PathValue("serverSlug")
+	server, err := s.db.Queries().GetServer(r.Context(), serverSlug)
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	if _, err = s.snapshotServerPlugins(r.Context(), server); err != nil {
+		log.Printf("Error listing plugins of %s: %s\n", serverSlug, err)
+		// reported in place of the button like connection checks
+		meta := struct{ Error, Output string }{Error: err.Error()}
+		renderPage(w, "server_rcon_check", "RCON Connection", nil, meta)
+		return
+	}
+
+	w.Header().Set("HX-Redirect", "/servers/reconcile#"+serverSlug)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Render the latest snapshots of all servers reconciled with the known
+// plugins and recorded installations
+func (s *Server) getReconciliation(w http.ResponseWriter, r *http.Request) {
+	q := s.db.Queries()
+	servers, err := q.GetServers(r.Context())
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+	known, err := q.GetPluginFileNames(r.Context())
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+	changelogVersions, err := q.GetChangelogVersions(r.Context())
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+	versions := make(map[int64][]string)
+	for _, entry := range changelogVersions {
+		versions[entry.PluginID] = append(versions[entry.PluginID], entry.Version)
+	}
+
+	reconciliations := make([]serverReconciliation, 0, len(servers))
+	for _, server := range servers {
+		installed, err := q.GetServerPlugins(r.Context(), server.ID)
+		if err != nil {
+			log.Println(err)
+			internalServerErr(w)
+			return
+		}
+
+		// servers without a snapshot can't be reconciled yet
+		snapshot, err := q.GetLatestServerSnapshot(r.Context(), server.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			reconciliations = append(reconciliations, serverReconciliation{Server: server})
+			continue
+		}
+		if err != nil {
+			log.Println(err)
+			internalServerErr(w)
+			return
+		}
+		loaded, err := q.GetServerSnapshotPlugins(r.Context(), snapshot.ID)
+		if err != nil {
+			log.Println(err)
+			internalServerErr(w)
+			return
+		}
+
+		reconciliation := reconcilePlugins(loaded, known, installed, versions)
+		reconciliation.Server = server
+		reconciliation.SnapshotAt = snapshot.CreatedAt
+		reconciliations = append(reconciliations, reconciliation)
+	}
+
+	renderPage(w, "server_reconciliation", "Plugin Reconciliation", reconciliations, nil)
+}
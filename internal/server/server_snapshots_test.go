@@ -0,0 +1,174 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"adminrust/internal/database"
+	"adminrust/internal/oxide"
+	"adminrust/internal/rcon"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestReconcilePlugins(t *testing.T) {
+	known := []database.GetPluginFileNamesRow{
+		{ID: 1, Name: "Better Chat", Slug: "better-chat", FileName: "BetterChat.cs"},
+		{ID: 2, Name: "Kits", Slug: "kits"},
+		{ID: 3, Name: "Vanish", Slug: "vanish", FileName: "Vanish.cs"},
+		{ID: 4, Name: "Zone Manager", Slug: "zone-manager", FileName: "ZoneManager.cs"},
+	}
+	installed := []database.GetServerPluginsRow{
+		{PluginID: 2, Version: "4.4.1", Name: "Kits", Slug: "kits"},
+		{PluginID: 3, Version: "1.0.0", Name: "Vanish", Slug: "vanish"},
+		{PluginID: 4, Version: "3.1.0", Name: "Zone Manager", Slug: "zone-manager"},
+	}
+	loaded := []database.ServerSnapshotPlugin{
+		{Title: "Better Chat", Version: "5.2.14", FileName: "BetterChat.cs", HookTime: 0.12},
+		// matched by the name without a source file
+		{Title: "Kits", Version: "4.4.1", FileName: "kits.cs"},
+		{Title: "Vanish", FileName: "Vanish.cs", Error: "Failed to compile"},
+		{Title: "Admin Radar", Version: "5.3.3", FileName: "AdminRadar.cs"},
+	}
+	versions := map[int64][]string{1: {"5.2.13", "5.2.14"}, 2: {"4.4.1", "4.4.2"}}
+
+	reconciliation := reconcilePlugins(loaded, known, installed, versions)
+	groups := map[string][]reconciledPlugin{
+		"unknown":    reconciliation.Unknown,
+		"missing":    reconciliation.Missing,
+		"outdated":   reconciliation.Outdated,
+		"up to date": reconciliation.UpToDate,
+	}
+	expected := map[string][]string{
+		"unknown":    {"Admin Radar"},
+		"missing":    {"Vanish", "Zone Manager"},
+		"outdated":   {"Kits"},
+		"up to date": {"Better Chat"},
+	}
+	for group, names := range expected {
+		plugins := groups[group]
+		if len(plugins) != len(names) {
+			t.Errorf("%s = %+v, want %v", group, plugins, names)
+			continue
+		}
+		for i, plugin := range plugins {
+			if name := cmp.Or(plugin.PluginName, plugin.Title); name != names[i] {
+				t.Errorf("%s plugin %d = %q, want %q", group, i, name, names[i])
+			}
+		}
+	}
+	if kits := reconciliation.Outdated[0]; kits.RecordedVersion != "4.4.1" || kits.LatestVersion != "4.4.2" {
+		t.Errorf("outdated Kits = %+v, want recorded 4.4.1 and latest 4.4.2", kits)
+	}
+	if vanish := reconciliation.Missing[0]; vanish.Error == "" {
+		t.Errorf("missing Vanish = %+v, want the load error", vanish)
+	}
+}
+
+func TestTakeServerSnapshot(t *testing.T) {
+	// stand-in for a Rust server listing its plugins
+	upgrader := websocket.Upgrader{}
+	standIn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			var request rcon.Message
+			if err := conn.ReadJSON(&request); err != nil {
+				return
+			}
+			output := "Unknown command: " + request.Message
+			if request.Message == oxide.PluginsCommand {
+				output = "Listing 2 plugins:\n" +
+					`  01 "Kits" (4.4.1) by k1lly0u (0.05s / 12 KB) - Kits.cs` + "\n" +
+					`  02 "Admin Radar" (5.3.3) by nivex (1.20s / 2 MB) - AdminRadar.cs`
+			}
+			_ = conn.WriteJSON(rcon.Message{Identifier: request.Identifier, Message: output, Type: "Generic"})
+		}
+	}))
+	defer standIn.Close()
+	host, rawPort, err := net.SplitHostPort(strings.TrimPrefix(standIn.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, _ := strconv.ParseInt(rawPort, 10, 64)
+
+	loadTestTemplates(t)
+	ctx := context.Background()
+	s := &Server{db: newTestDB(t)}
+	q := s.db.Queries()
+
+	pluginOrigin, err := q.AddOrigin(ctx, database.AddOriginParams{
+		Name: "uMod", Slug: "umod", Url: "https://umod.org", PathToPluginList: "/plugins",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kits, err := q.AddPlugin(ctx, database.AddPluginParams{
+		Name: "Kits", Slug: "kits", Url: "https://umod.org/plugins/kits", OriginID: pluginOrigin.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = q.AddPluginChangelog(ctx, database.AddPluginChangelogParams{
+		PluginID: kits.ID, Version: "4.4.2", Changelog: "Fixes", UpdateDate: "2025-03-01",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, params := range []database.AddServerParams{
+		{Name: "Main", Slug: "main", Framework: "oxide", RconHost: host, RconPort: port},
+		{Name: "Offline", Slug: "offline", Framework: "oxide"},
+		{Name: "Modded", Slug: "modded", Framework: "carbon", RconHost: host, RconPort: port},
+	} {
+		if _, err = q.AddServer(ctx, params); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snapshot := func(serverSlug string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/servers/"+serverSlug+"/snapshot", nil)
+		r.SetPathValue("serverSlug", serverSlug)
+		w := httptest.NewRecorder()
+		s.takeServerSnapshot(w, r)
+		return w
+	}
+	if w := snapshot("main"); w.Code != http.StatusNoContent || w.Header().Get("HX-Redirect") != "/servers/reconcile#main" {
+		t.Fatalf("takeServerSnapshot() status = %d, body:\n%s", w.Code, w.Body.String())
+	}
+	// the snapshot samples hook times of the loaded plugins
+	main, err := q.GetServer(ctx, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	samples, err := q.GetServerHookTimes(ctx, database.GetServerHookTimesParams{ServerID: main.ID})
+	if err != nil || len(samples) != 2 {
+		t.Errorf("GetServerHookTimes() = %+v, %v, want Kits and Admin Radar", samples, err)
+	}
+	if w := snapshot("offline"); !strings.Contains(w.Body.String(), errNoRcon.Error()) {
+		t.Errorf("snapshot of server without RCON body:\n%s", w.Body.String())
+	}
+	if w := snapshot("modded"); !strings.Contains(w.Body.String(), errNoPluginList.Error()) {
+		t.Errorf("snapshot of Carbon server body:\n%s", w.Body.String())
+	}
+
+	r := httptest.NewRequest("GET", "/servers/reconcile", nil)
+	w := httptest.NewRecorder()
+	s.getReconciliation(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("getReconciliation() status = %d", w.Code)
+	}
+	for _, part := range []string{"Outdated (1)", `href="/plugins/kits"`, "Unknown (1)", "Admin Radar", "1.2s", "Set up RCON", "only read from Oxide servers"} {
+		if !strings.Contains(w.Body.String(), part) {
+			t.Errorf("reconciliation page misses %q", part)
+		}
+	}
+}
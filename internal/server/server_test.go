@@ -0,0 +1,54 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"sync"
+	"testing"
+
+	"adminrust/internal/database"
+	"adminrust/internal/database/dbtest"
+)
+
+// Templates are parsed once for all tests rendering pages
+var loadTestTemplatesOnce sync.Once
+
+// Parse HTML templates from the repository root
+func loadTestTemplates(t *testing.T) {
+	t.Helper()
+
+	loadTestTemplatesOnce.Do(func() {
+		// template paths are relative to the working directory
+		wd, err := os.Getwd()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = os.Chdir("../.."); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Chdir(wd)
+		loadTemplates()
+	})
+}
+
+// In-memory database service for handler and query tests
+type testDB struct {
+	db      *sql.DB
+	queries *database.Queries
+}
+
+func (t *testDB) Health() map[string]string  { return map[string]string{"status": "up"} }
+func (t *testDB) Queries() *database.Queries { return t.queries }
+func (t *testDB) Close() error               { return t.db.Close() }
+func (t *testDB) InTx(ctx context.Context, fn func(q *database.Queries) error) error {
+	return database.RunInTx(ctx, t.db, fn)
+}
+
+// Create an in-memory database with all "Up" migrations applied
+func newTestDB(t *testing.T) *testDB {
+	t.Helper()
+
+	db := dbtest.Open(t)
+	return &testDB{db: db, queries: database.New(db)}
+}
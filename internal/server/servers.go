@@ -0,0 +1,456 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"adminrust/internal/database"
+	"adminrust/internal/rcon"
+	"adminrust/internal/version"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Modding frameworks servers run plugins with
+var serverFrameworks = []string{"oxide", "carbon"}
+
+// Server directories are absolute paths on Linux or Windows machines
+var validateServerPath = validateByPattern(`^(/|[a-zA-Z]:[\\/])[^\x00]*$`)
+
+// Installation state of a plugin on a server
+const (
+	installUpToDate     = "up to date"
+	installOutdated     = "outdated"
+	installNotInstalled = "not installed"
+	// no changelog to compare the installed version with
+	installUnknown = "unknown"
+)
+
+// Routes to get one/many, add, update, and delete servers
+// and record plugins installed on them
+func (s *Server) registerServerRoutes(r *chi.Mux) {
+	r.Route("/servers", func(r chi.Router) {
+		r.Get("/", s.getServers)
+
+		r.Get("/add", s.addServerForm)
+		r.Post("/add", s.addServer)
+
+		r.Get("/reconcile", s.getReconciliation)
+
+		r.Route("/edit/{serverSlug:[a-z0-9-]+}", func(r chi.Router) {
+			r.Get("/", s.updateServerForm)
+			r.Post("/", s.updateServer)
+		})
+
+		r.Route("/{serverSlug:[a-z0-9-]+}", func(r chi.Router) {
+			r.Get("/", s.getServer)
+			r.Delete("/", s.deleteServer)
+
+			r.Post("/rcon/check", s.checkServerRcon)
+			r.Post("/snapshot", s.takeServerSnapshot)
+			r.Get("/hook-times", s.getServerHookTimes)
+
+			r.Post("/plugins", s.setServerPlugin)
+			r.Delete("/plugins/{pluginID:[0-9]+}", s.deleteServerPlugin)
+		})
+	})
+}
+
+// Plugin version installed on a server compared with the latest known one
+type serverInstallation struct {
+	ServerName, ServerSlug string
+	PluginID               int64
+	PluginName, PluginSlug string
+	Version, InstalledAt   string
+	LatestVersion          string
+	Status                 string
+	// whether plugin actions can be run on the server over RCON
+	HasRcon bool
+}
+
+// Compare the installed version with the latest changelog version
+func installStatus(installed, latest string) string {
+	switch {
+	case installed == "":
+		return installNotInstalled
+	case latest == "":
+		return installUnknown
+	case version.Compare(installed, latest) < 0:
+		return installOutdated
+	}
+
+	return installUpToDate
+}
+
+// Server details received from a form
+type serverForm struct {
+	Framework     string
+	RootPath      string
+	FrameworkPath string
+	Notes         string
+	RconHost      string
+	RconPort      int64
+	RconPassword  string
+}
+
+// Read and validate server details, paths and RCON are optional
+func parseServerForm(r *http.Request) (form serverForm, err error) {
+	form = serverForm{
+		Framework:     r.FormValue("framework"),
+		RootPath:      strings.TrimSpace(r.FormValue("rootPath")),
+		FrameworkPath: strings.TrimSpace(r.FormValue("frameworkPath")),
+		Notes:         strings.TrimSpace(r.FormValue("notes")),
+		RconHost:      strings.TrimSpace(r.FormValue("rconHost")),
+		RconPort:      rcon.DefaultPort,
+		RconPassword:  r.FormValue("rconPassword"),
+	}
+
+	if !slices.Contains(serverFrameworks, form.Framework) {
+		return form, fmt.Errorf("unknown framework: %q", form.Framework)
+	}
+	for _, path := range []string{form.RootPath, form.FrameworkPath} {
+		if path != "" && !validateServerPath(path) {
+			return form, fmt.Errorf("path isn't absolute: %q", path)
+		}
+	}
+
+	if form.RconHost != "" && !validateRconHost(form.RconHost) {
+		return form, fmt.Errorf("invalid RCON host: %q", form.RconHost)
+	}
+	if rawPort := strings.TrimSpace(r.FormValue("rconPort")); rawPort != "" {
+		form.RconPort, err = strconv.ParseInt(rawPort, 10, 64)
+		if err != nil || form.RconPort < 1 || form.RconPort > 65535 {
+			return form, fmt.Errorf("invalid RCON port: %q", rawPort)
+		}
+	}
+
+	return form, nil
+}
+
+// Get from DB and render a list of servers
+func (s *Server) getServers(w http.ResponseWriter, r *http.Request) {
+	servers, err := s.db.Queries().GetServers(r.Context())
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// populate and render servers page
+	renderPage(w, "servers", "Servers", servers, nil)
+}
+
+// Render a detailed page for a server with its installed plugins
+func (s *Server) getServer(w http.ResponseWriter, r *http.Request) {
+	serverSlug := r.PathValue("serverSlug")
+	server, err := s.db.Queries().GetServer(r.Context(), serverSlug)
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	installed, err := s.db.Queries().GetServerPlugins(r.Context(), server.ID)
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+	// latest versions of every plugin to tell outdated installations
+	changelogVersions, err := s.db.Queries().GetChangelogVersions(r.Context())
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+	versions := make(map[int64][]string)
+	for _, entry := range changelogVersions {
+		versions[entry.PluginID] = append(versions[entry.PluginID], entry.Version)
+	}
+
+	installations := make([]serverInstallation, 0, len(installed))
+	for _, plugin := range installed {
+		latest, _ := version.Latest(versions[plugin.PluginID])
+		installations = append(installations, serverInstallation{
+			ServerName:    server.Name,
+			ServerSlug:    server.Slug,
+			PluginID:      plugin.PluginID,
+			PluginName:    plugin.Name,
+			PluginSlug:    plugin.Slug,
+			Version:       plugin.Version,
+			InstalledAt:   plugin.InstalledAt,
+			LatestVersion: latest,
+			Status:        installStatus(plugin.Version, latest),
+		})
+	}
+
+	// plugins to choose from when recording an installation
+	plugins, err := s.db.Queries().GetPlugins(r.Context())
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	content := struct {
+		Server        database.Server
+		Installations []serverInstallation
+	}{server, installations}
+	metaData := struct {
+		Plugins []database.Plugin
+		Today   string
+	}{plugins, time.Now().Format(time.DateOnly)}
+
+	// populate and render detailed server page
+	renderPage(w, "server", server.Name, content, metaData)
+}
+
+// Render the page with server addition form
+func (s *Server) addServerForm(w http.ResponseWriter, r *http.Request) {
+	meta := struct{ Frameworks []string }{serverFrameworks}
+
+	// populate and render server addition form
+	renderPage(w, "add_server", "Add Server", nil, meta)
+}
+
+// Post a new server.
+//
+// Redirects to a detailed page for newly created server.
+func (s *Server) addServer(w http.ResponseWriter, r *http.Request) {
+	name := r.FormValue("name")
+	if !validateName(name) {
+		log.Println("name error:", name)
+		badRequest(w)
+		return
+	}
+
+	form, err := parseServerForm(r)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	server, err := s.db.Queries().AddServer(r.Context(), database.AddServerParams{
+		Name:          name,
+		Slug:          slugify(name),
+		Framework:     form.Framework,
+		RootPath:      form.RootPath,
+		FrameworkPath: form.FrameworkPath,
+		Notes:         form.Notes,
+		RconHost:      form.RconHost,
+		RconPort:      form.RconPort,
+		RconPassword:  form.RconPassword,
+	})
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/servers/%s", server.Slug), http.StatusFound)
+}
+
+// Render a server updating form
+func (s *Server) updateServerForm(w http.ResponseWriter, r *http.Request) {
+	serverSlug := r.PathValue("serverSlug")
+	server, err := s.db.Queries().GetServer(r.Context(), serverSlug)
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+	meta := struct{ Frameworks []string }{serverFrameworks}
+
+	// populate and render server updating form
+	renderPage(w, "add_server", "Update Server", server, meta)
+}
+
+// Update server details
+func (s *Server) updateServer(w http.ResponseWriter, r *http.Request) {
+	// check if the retrieved form contains hidden PUT method
+	if r.FormValue("_method") != "PUT" {
+		log.Println("post with no PUT input")
+		notAllowed(w, r)
+		return
+	}
+
+	serverSlug := r.PathValue("serverSlug")
+	server, err := s.db.Queries().GetServer(r.Context(), serverSlug)
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	form, err := parseServerForm(r)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+	// the password isn't shown in the form and is kept unless a new one is typed
+	if form.RconPassword == "" {
+		form.RconPassword = server.RconPassword
+	}
+
+	server, err = s.db.Queries().UpdateServer(r.Context(), database.UpdateServerParams{
+		Framework:     form.Framework,
+		RootPath:      form.RootPath,
+		FrameworkPath: form.FrameworkPath,
+		Notes:         form.Notes,
+		RconHost:      form.RconHost,
+		RconPort:      form.RconPort,
+		RconPassword:  form.RconPassword,
+		Slug:          serverSlug,
+	})
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	// redirect to a server detailed page
+	http.Redirect(w, r, fmt.Sprintf("/servers/%s", server.Slug), http.StatusFound)
+}
+
+// Delete server with its installations and redirect to the server list page
+func (s *Server) deleteServer(w http.ResponseWriter, r *http.Request) {
+	serverSlug := r.PathValue("serverSlug")
+	server, err := s.db.Queries().DeleteServer(r.Context(), serverSlug)
+	if errors.Is(err, sql.ErrNoRows) {
+		notFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+	s.dropRconClient(server.ID)
+
+	w.Header().Set("HX-Redirect", "/servers")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Record the plugin version installed on the server, replacing
+// the previous one
+func (s *Server) setServerPlugin(w http.ResponseWriter, r *http.Request) {
+	serverSlug := r.PathValue("serverSlug")
+	server, err := s.db.Queries().GetServer(r.Context(), serverSlug)
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+
+	pluginID, err := s.db.Queries().GetPluginID(r.Context(), r.FormValue("plugin"))
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+	pluginVersion := strings.TrimSpace(r.FormValue("version"))
+	if !validateVersion(pluginVersion) {
+		log.Println("invalid version:", pluginVersion)
+		badRequest(w)
+		return
+	}
+	// installations recorded without a date are made today
+	installedAt := r.FormValue("installedAt")
+	if installedAt == "" {
+		installedAt = time.Now().Format(time.DateOnly)
+	}
+	if _, err = time.Parse(time.DateOnly, installedAt); err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	_, err = s.db.Queries().SetServerPlugin(r.Context(), database.SetServerPluginParams{
+		ServerID:    server.ID,
+		PluginID:    pluginID,
+		Version:     pluginVersion,
+		InstalledAt: installedAt,
+	})
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/servers/%s", server.Slug), http.StatusFound)
+}
+
+// Forget the plugin installed on the server
+func (s *Server) deleteServerPlugin(w http.ResponseWriter, r *http.Request) {
+	serverSlug := r.PathValue("serverSlug")
+	server, err := s.db.Queries().GetServer(r.Context(), serverSlug)
+	if err != nil {
+		log.Println(err)
+		notFound(w, r)
+		return
+	}
+	pluginID, err := strconv.ParseInt(r.PathValue("pluginID"), 10, 64)
+	if err != nil {
+		log.Println(err)
+		badRequest(w)
+		return
+	}
+
+	_, err = s.db.Queries().DeleteServerPlugin(r.Context(), database.DeleteServerPluginParams{
+		ServerID: server.ID,
+		PluginID: pluginID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		notFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Println(err)
+		internalServerErr(w)
+		return
+	}
+
+	w.Header().Set("HX-Redirect", fmt.Sprintf("/servers/%s", server.Slug))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Installation state of the plugin on every server
+func (s *Server) pluginInstallations(ctx context.Context, plugin database.Plugin) ([]serverInstallation, error) {
+	servers, err := s.db.Queries().GetPluginServers(ctx, plugin.Slug)
+	if err != nil {
+		return nil, err
+	}
+	changelog, err := s.db.Queries().GetPluginChangelog(ctx, plugin.Slug)
+	if err != nil {
+		return nil, err
+	}
+	latest, _ := latestVersion(changelog)
+
+	installations := make([]serverInstallation, 0, len(servers))
+	for _, server := range servers {
+		installations = append(installations, serverInstallation{
+			ServerName:    server.Name,
+			ServerSlug:    server.Slug,
+			PluginID:      plugin.ID,
+			PluginName:    plugin.Name,
+			PluginSlug:    plugin.Slug,
+			Version:       server.Version,
+			InstalledAt:   server.InstalledAt,
+			LatestVersion: latest,
+			Status:        installStatus(server.Version, latest),
+			HasRcon:       server.RconHost != "",
+		})
+	}
+
+	return installations, nil
+}
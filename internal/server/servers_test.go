@@ -0,0 +1,195 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"adminrust/internal/database"
+)
+
+func TestInstallStatus(t *testing.T) {
+	tests := []struct {
+		installed, latest string
+		expectedStatus    string
+	}{
+		{installed: "1.2.0", latest: "1.10.0", expectedStatus: installOutdated},
+		{installed: "1.10.0", latest: "1.10.0", expectedStatus: installUpToDate},
+		// installed from a source newer than the changelog knows
+		{installed: "2.0.0", latest: "1.10.0", expectedStatus: installUpToDate},
+		{installed: "1.0.0", latest: "", expectedStatus: installUnknown},
+		{installed: "", latest: "1.0.0", expectedStatus: installNotInstalled},
+	}
+
+	for _, test := range tests {
+		if status := installStatus(test.installed, test.latest); status != test.expectedStatus {
+			t.Errorf("installStatus(%q, %q) = %q, want %q", test.installed, test.latest, status, test.expectedStatus)
+		}
+	}
+}
+
+func TestParseServerForm(t *testing.T) {
+	tests := []struct {
+		name    string
+		form    url.Values
+		isValid bool
+	}{
+		{name: "linux paths", form: url.Values{"framework": {"oxide"}, "rootPath": {"/home/rust"}, "frameworkPath": {"/home/rust/oxide"}}, isValid: true},
+		{name: "windows paths", form: url.Values{"framework": {"carbon"}, "rootPath": {`C:\rust`}}, isValid: true},
+		{name: "no paths", form: url.Values{"framework": {"oxide"}}, isValid: true},
+		{name: "relative path", form: url.Values{"framework": {"oxide"}, "rootPath": {"rust/server"}}, isValid: false},
+		{name: "unknown framework", form: url.Values{"framework": {"umod"}}, isValid: false},
+		{name: "rcon", form: url.Values{"framework": {"oxide"}, "rconHost": {"rust.example.com"}, "rconPort": {"28016"}}, isValid: true},
+		{name: "rcon ipv6 host", form: url.Values{"framework": {"oxide"}, "rconHost": {"::1"}}, isValid: true},
+		{name: "rcon host with path", form: url.Values{"framework": {"oxide"}, "rconHost": {"rust.example.com/rcon"}}, isValid: false},
+		{name: "rcon port out of range", form: url.Values{"framework": {"oxide"}, "rconHost": {"127.0.0.1"}, "rconPort": {"70000"}}, isValid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/servers/add", strings.NewReader(test.form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			_, err := parseServerForm(r)
+			if (err == nil) != test.isValid {
+				t.Errorf("parseServerForm() error = %v, want valid %v", err, test.isValid)
+			}
+		})
+	}
+}
+
+func TestServerInstallations(t *testing.T) {
+	loadTestTemplates(t)
+	ctx := context.Background()
+	s := &Server{db: newTestDB(t)}
+	q := s.db.Queries()
+
+	pluginOrigin, err := q.AddOrigin(ctx, database.AddOriginParams{
+		Name: "uMod", Slug: "umod", Url: "https://umod.org", PathToPluginList: "/plugins",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kits, err := q.AddPlugin(ctx, database.AddPluginParams{
+		Name: "Kits", Slug: "kits", Url: "https://umod.org/plugins/kits", OriginID: pluginOrigin.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pluginVersion := range []string{"4.4.1", "4.4.2"} {
+		_, err = q.AddPluginChangelog(ctx, database.AddPluginChangelogParams{
+			PluginID: kits.ID, Version: pluginVersion, Changelog: "Fixes", UpdateDate: "2025-03-01",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// without servers the update flag is set by hand
+	w := httptest.NewRecorder()
+	s.getPlugins(w, httptest.NewRequest("GET", "/plugins", nil))
+	if !strings.Contains(w.Body.String(), "isUpdatedOnServer") {
+		t.Errorf("plugin list without servers misses the update flag:\n%s", w.Body.String())
+	}
+
+	for _, name := range []string{"Main", "Modded"} {
+		_, err = q.AddServer(ctx, database.AddServerParams{Name: name, Slug: slugify(name), Framework: "oxide"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// record installations through the form
+	install := func(serverSlug, pluginSlug, pluginVersion string) *httptest.ResponseRecorder {
+		form := url.Values{"plugin": {pluginSlug}, "version": {pluginVersion}, "installedAt": {"2025-03-02"}}
+		r := httptest.NewRequest("POST", "/servers/"+serverSlug+"/plugins", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.SetPathValue("serverSlug", serverSlug)
+		w := httptest.NewRecorder()
+		s.setServerPlugin(w, r)
+		return w
+	}
+	if w := install("main", "kits", "4.4.1"); w.Code != http.StatusFound {
+		t.Fatalf("setServerPlugin() status = %d, want %d", w.Code, http.StatusFound)
+	}
+	// a newer version replaces the recorded one
+	if w := install("main", "kits", "4.4.2"); w.Code != http.StatusFound {
+		t.Fatalf("setServerPlugin() status = %d, want %d", w.Code, http.StatusFound)
+	}
+	if w := install("modded", "kits", "4.4.1"); w.Code != http.StatusFound {
+		t.Fatalf("setServerPlugin() status = %d, want %d", w.Code, http.StatusFound)
+	}
+	if w := install("main", "missing", "1.0.0"); w.Code != http.StatusBadRequest {
+		t.Errorf("unknown plugin status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if w := install("main", "kits", "latest"); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid version status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	installations, err := s.pluginInstallations(ctx, kits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{"main": installUpToDate, "modded": installOutdated}
+	if len(installations) != len(expected) {
+		t.Fatalf("pluginInstallations() = %+v, want 2 servers", installations)
+	}
+	for _, installation := range installations {
+		if installation.Status != expected[installation.ServerSlug] || installation.LatestVersion != "4.4.2" {
+			t.Errorf("installation on %s = %+v, want %s", installation.ServerSlug, installation, expected[installation.ServerSlug])
+		}
+	}
+
+	// the plugin page shows the status on every server
+	r := httptest.NewRequest("GET", "/plugins/kits", nil)
+	r.SetPathValue("pluginSlug", "kits")
+	w = httptest.NewRecorder()
+	s.getPlugin(w, r)
+	for _, part := range []string{`href="/servers/main"`, installUpToDate, installOutdated} {
+		if !strings.Contains(w.Body.String(), part) {
+			t.Errorf("plugin page misses %q", part)
+		}
+	}
+
+	// the plugin list and form leave the update flag set by hand out
+	r = httptest.NewRequest("GET", "/plugins", nil)
+	w = httptest.NewRecorder()
+	s.getPlugins(w, r)
+	if !strings.Contains(w.Body.String(), "outdated on 1 server<") || strings.Contains(w.Body.String(), "isUpdatedOnServer") {
+		t.Errorf("plugin list misses the outdated servers or shows the update flag:\n%s", w.Body.String())
+	}
+	r = httptest.NewRequest("GET", "/plugins/edit/kits", nil)
+	r.SetPathValue("pluginSlug", "kits")
+	w = httptest.NewRecorder()
+	s.updatePluginForm(w, r)
+	if w.Code != http.StatusOK || strings.Contains(w.Body.String(), "isUpdatedOnServer") {
+		t.Errorf("plugin form status = %d, want no update flag", w.Code)
+	}
+
+	// removed installations turn into not installed ones
+	r = httptest.NewRequest("DELETE", "/servers/modded/plugins/1", nil)
+	r.SetPathValue("serverSlug", "modded")
+	r.SetPathValue("pluginID", "1")
+	w = httptest.NewRecorder()
+	s.deleteServerPlugin(w, r)
+	if w.Code != http.StatusNoContent || w.Header().Get("HX-Redirect") != "/servers/modded" {
+		t.Fatalf("deleteServerPlugin() status = %d, redirect = %q", w.Code, w.Header().Get("HX-Redirect"))
+	}
+
+	r = httptest.NewRequest("GET", "/servers/modded", nil)
+	r.SetPathValue("serverSlug", "modded")
+	w = httptest.NewRecorder()
+	s.getServer(w, r)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "No plugins recorded on this server") {
+		t.Errorf("server page status = %d, want no plugins listed", w.Code)
+	}
+	installations, err = s.pluginInstallations(ctx, kits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if installations[1].Status != installNotInstalled {
+		t.Errorf("installation on modded = %+v, want not installed", installations[1])
+	}
+}
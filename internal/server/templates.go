@@ -38,10 +38,19 @@ func loadTemplates() {
 	templateNames := []string{
 		"add_origin", "origin", "origins",
 		"add_plugin", "plugin", "plugins",
-		"add_plugin_cmds",
+		"add_plugin_changelog",
+		"upload_plugin_source", "plugin_source_diff",
+		"add_plugin_cmds", "edit_plugin_cmd",
+		"analyze_plugin_source", "review_plugin_cmds",
+		"add_plugin_perm", "review_plugin_perms",
+		"upload_plugin_images",
 		"add_plugin_doc",
-		"add_plugin_cfg",
-		"add_plugin_locale",
+		"add_plugin_cfg", "plugin_config_drift",
+		"add_plugin_locale", "locale_report",
+		"add_plugin_code_change", "record_plugin_code_hunks", "plugin_source_patch",
+		"add_server", "server", "servers", "server_reconciliation",
+		"export", "import", "import_preview",
+		"jobs",
 		"http_error",
 	}
 	// populate the base template with content templates and cache each one
@@ -56,8 +65,9 @@ func loadTemplates() {
 
 	// process templates for inner-page tabs
 	tabTemplateNames := []string{
-		"plugin_changelogs", "plugin_commands",
-		"plugin_doc", "plugin_cfg", "plugin_locales",
+		"plugin_changelogs", "plugin_sources", "plugin_commands", "plugin_permissions", "plugin_images",
+		"plugin_doc", "plugin_cfg", "plugin_locales", "plugin_code_changes",
+		"origin_sync", "version_check", "server_rcon_check", "plugin_rcon_action", "hook_time_charts",
 	}
 	for _, tabTempl := range tabTemplateNames {
 		absPath := makeAbsTemplPath(absTemplateDir, tabTempl)
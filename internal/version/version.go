@@ -0,0 +1,165 @@
+package version
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Maximum number of dot-separated numeric parts, e.g. 1.2.3.4
+const maxParts = 4
+
+var (
+	numberPattern     = regexp.MustCompile(`^\d+$`)
+	identifierPattern = regexp.MustCompile(`^[0-9A-Za-z]+$`)
+)
+
+// A dotted plugin version like 1.2.3, 1.2.3.4 or 2.0.0-beta
+type Version struct {
+	// numeric parts, e.g. [1 2 3]
+	Parts []int
+	// pre-release identifiers after a hyphen, e.g. [beta 1] for "-beta.1"
+	PreRelease []string
+}
+
+// Parse a dotted version. An optional "v" prefix is ignored, since
+// plugin authors often use it.
+func Parse(raw string) (v Version, err error) {
+	s := strings.TrimSpace(raw)
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "v"), "V")
+
+	numbers, preRelease, hasPreRelease := strings.Cut(s, "-")
+	if hasPreRelease {
+		if preRelease == "" {
+			return v, fmt.Errorf("empty pre-release in version %q", raw)
+		}
+		v.PreRelease = strings.Split(preRelease, ".")
+		for _, id := range v.PreRelease {
+			if !identifierPattern.MatchString(id) {
+				return Version{}, fmt.Errorf("invalid pre-release %q in version %q", preRelease, raw)
+			}
+		}
+	}
+
+	parts := strings.Split(numbers, ".")
+	if len(parts) > maxParts {
+		return Version{}, fmt.Errorf("too many parts in version %q", raw)
+	}
+	for _, part := range parts {
+		if !numberPattern.MatchString(part) {
+			return Version{}, fmt.Errorf("invalid part %q in version %q", part, raw)
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid part %q in version %q: %w", part, raw, err)
+		}
+		v.Parts = append(v.Parts, n)
+	}
+
+	return v, nil
+}
+
+// Convert version back to its dotted form
+func (v Version) String() string {
+	parts := make([]string, len(v.Parts))
+	for i, n := range v.Parts {
+		parts[i] = strconv.Itoa(n)
+	}
+
+	s := strings.Join(parts, ".")
+	if len(v.PreRelease) > 0 {
+		s += "-" + strings.Join(v.PreRelease, ".")
+	}
+
+	return s
+}
+
+// Compare versions and return -1 if v is older than other, 1 if it's
+// newer, and 0 if they are equal. Missing parts are zeros, so 1.2 equals
+// 1.2.0, and a pre-release is older than its release.
+func (v Version) Compare(other Version) int {
+	for i := range max(len(v.Parts), len(other.Parts)) {
+		if c := cmp.Compare(partAt(v.Parts, i), partAt(other.Parts, i)); c != 0 {
+			return c
+		}
+	}
+
+	// a release is newer than any of its pre-releases
+	switch {
+	case len(v.PreRelease) == 0 && len(other.PreRelease) == 0:
+		return 0
+	case len(v.PreRelease) == 0:
+		return 1
+	case len(other.PreRelease) == 0:
+		return -1
+	}
+
+	for i := range min(len(v.PreRelease), len(other.PreRelease)) {
+		if c := compareIdentifiers(v.PreRelease[i], other.PreRelease[i]); c != 0 {
+			return c
+		}
+	}
+
+	// more identifiers mean a later pre-release, e.g. beta < beta.1
+	return cmp.Compare(len(v.PreRelease), len(other.PreRelease))
+}
+
+// Compare raw versions, unparsable versions are older than any valid one
+func Compare(a, b string) int {
+	va, errA := Parse(a)
+	vb, errB := Parse(b)
+	switch {
+	case errA != nil && errB != nil:
+		return 0
+	case errA != nil:
+		return -1
+	case errB != nil:
+		return 1
+	}
+
+	return va.Compare(vb)
+}
+
+// Return the newest of raw versions or false if none of them is valid
+func Latest(versions []string) (latest string, ok bool) {
+	var latestVersion Version
+	for _, raw := range versions {
+		v, err := Parse(raw)
+		if err != nil {
+			continue
+		}
+		if !ok || v.Compare(latestVersion) > 0 {
+			latest, latestVersion, ok = raw, v, true
+		}
+	}
+
+	return latest, ok
+}
+
+// Get a numeric part or zero if the version is shorter
+func partAt(parts []int, i int) int {
+	if i < len(parts) {
+		return parts[i]
+	}
+
+	return 0
+}
+
+// Numeric identifiers are compared numerically and are older than
+// alphanumeric ones, which are compared lexically
+func compareIdentifiers(a, b string) int {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		return cmp.Compare(na, nb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,97 @@
+package version
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		expectedOutput string
+		isValid        bool
+	}{
+		{name: "three parts", input: "1.2.3", expectedOutput: "1.2.3", isValid: true},
+		{name: "four parts", input: "1.2.3.4", expectedOutput: "1.2.3.4", isValid: true},
+		{name: "pre-release", input: "2.0.0-beta", expectedOutput: "2.0.0-beta", isValid: true},
+		{name: "dotted pre-release", input: "2.0.0-rc.1", expectedOutput: "2.0.0-rc.1", isValid: true},
+		{name: "prefixed", input: "v1.2.3", expectedOutput: "1.2.3", isValid: true},
+		{name: "leading zeros", input: "1.02.003", expectedOutput: "1.2.3", isValid: true},
+		{name: "single part", input: "7", expectedOutput: "7", isValid: true},
+		{name: "five parts", input: "1.2.3.4.5", isValid: false},
+		{name: "empty part", input: "1..3", isValid: false},
+		{name: "signed part", input: "1.+2.3", isValid: false},
+		{name: "letters", input: "1.2.x", isValid: false},
+		{name: "empty pre-release", input: "1.2.3-", isValid: false},
+		{name: "empty pre-release identifier", input: "1.2.3-rc..1", isValid: false},
+		{name: "empty", input: "", isValid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v, err := Parse(test.input)
+			if (err == nil) != test.isValid {
+				t.Fatalf("Parse() error = %v, want valid %v", err, test.isValid)
+			}
+			if err == nil && v.String() != test.expectedOutput {
+				t.Errorf("Parse() version = %v, want %v", v, test.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name           string
+		a, b           string
+		expectedOutput int
+	}{
+		{name: "equal", a: "1.2.3", b: "1.2.3", expectedOutput: 0},
+		{name: "numeric not lexical", a: "1.10.0", b: "1.9.0", expectedOutput: 1},
+		{name: "major wins", a: "2.0.0", b: "1.99.99", expectedOutput: 1},
+		{name: "missing parts are zeros", a: "1.2", b: "1.2.0.0", expectedOutput: 0},
+		{name: "fourth part", a: "1.2.3.4", b: "1.2.3", expectedOutput: 1},
+		{name: "pre-release is older than release", a: "2.0.0-beta", b: "2.0.0", expectedOutput: -1},
+		{name: "pre-release is newer than previous release", a: "2.0.0-beta", b: "1.9.9", expectedOutput: 1},
+		{name: "pre-release identifiers", a: "2.0.0-alpha", b: "2.0.0-beta", expectedOutput: -1},
+		{name: "numeric pre-release identifiers", a: "2.0.0-rc.10", b: "2.0.0-rc.2", expectedOutput: 1},
+		{name: "numeric identifier is older than alphanumeric", a: "2.0.0-1", b: "2.0.0-alpha", expectedOutput: -1},
+		{name: "more pre-release identifiers", a: "2.0.0-beta.1", b: "2.0.0-beta", expectedOutput: 1},
+		{name: "invalid is older than valid", a: "latest", b: "0.0.1", expectedOutput: -1},
+		{name: "both invalid", a: "latest", b: "newest", expectedOutput: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := Compare(test.a, test.b); result != test.expectedOutput {
+				t.Errorf("Compare(%q, %q) = %v, want %v", test.a, test.b, result, test.expectedOutput)
+			}
+			if result := Compare(test.b, test.a); result != -test.expectedOutput {
+				t.Errorf("Compare(%q, %q) = %v, want %v", test.b, test.a, result, -test.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestLatest(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          []string
+		expectedOutput string
+		isFound        bool
+	}{
+		{name: "unordered", input: []string{"1.2.0", "1.10.0", "1.9.5"}, expectedOutput: "1.10.0", isFound: true},
+		{name: "release after pre-release", input: []string{"2.0.0-beta", "2.0.0", "1.5.0"}, expectedOutput: "2.0.0", isFound: true},
+		{name: "first of equal ones", input: []string{"1.2", "1.2.0"}, expectedOutput: "1.2", isFound: true},
+		{name: "invalid skipped", input: []string{"latest", "0.1.0"}, expectedOutput: "0.1.0", isFound: true},
+		{name: "no valid", input: []string{"latest"}, isFound: false},
+		{name: "empty", input: nil, isFound: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			latest, ok := Latest(test.input)
+			if ok != test.isFound || latest != test.expectedOutput {
+				t.Errorf("Latest() = %q, %v, want %q, %v", latest, ok, test.expectedOutput, test.isFound)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+-- name: GetExportPlugins :many
+SELECT plugins.id, plugins.name, plugins.slug,
+    CAST(COALESCE((
+        SELECT plugin_sources.file_name
+        FROM plugin_sources
+        WHERE plugin_sources.plugin_id = plugins.id
+        ORDER BY plugin_sources.updated_at DESC, plugin_sources.id DESC
+        LIMIT 1
+    ), '') AS TEXT) AS source_file_name,
+    EXISTS (
+        SELECT 1
+        FROM plugin_configs
+        WHERE plugin_configs.plugin_id = plugins.id
+    ) AS has_config,
+    (
+        SELECT COUNT(*)
+        FROM plugin_locales
+        WHERE plugin_locales.plugin_id = plugins.id
+    ) AS locale_count
+FROM plugins
+ORDER BY plugins.name;
+
+-- name: GetExportConfigs :many
+SELECT plugin_id, config_json, updated_at
+FROM plugin_configs;
+
+-- name: GetExportLocales :many
+SELECT plugin_id, lang_code, content_json, updated_at
+FROM plugin_locales
+ORDER BY lang_code;
\ No newline at end of file
@@ -0,0 +1,23 @@
+-- name: AddHookTimeSample :exec
+INSERT INTO hook_time_samples(server_id, title, file_name, version, hook_time, sampled_at)
+VALUES (?, ?, ?, ?, ?, datetime('now'));
+
+-- name: GetPluginHookTimes :many
+SELECT servers.name AS server_name, servers.slug AS server_slug,
+    hook_time_samples.sampled_at, hook_time_samples.version, hook_time_samples.hook_time
+FROM hook_time_samples
+JOIN servers ON servers.id = hook_time_samples.server_id
+WHERE hook_time_samples.file_name = ? COLLATE NOCASE
+    AND hook_time_samples.sampled_at >= ?
+ORDER BY servers.name, servers.id, hook_time_samples.sampled_at, hook_time_samples.id;
+
+-- name: GetServerHookTimes :many
+SELECT title, file_name, sampled_at, version, hook_time
+FROM hook_time_samples
+WHERE server_id = ? AND sampled_at >= ?
+ORDER BY file_name COLLATE NOCASE, sampled_at, id;
+
+-- name: DeleteHookTimeSamplesBefore :exec
+DELETE
+FROM hook_time_samples
+WHERE sampled_at < ?;
\ No newline at end of file
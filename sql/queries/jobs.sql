@@ -0,0 +1,44 @@
+-- name: EnsureJob :one
+INSERT INTO jobs(name, schedule, created_at, updated_at)
+VALUES (?, ?, datetime('now'), datetime('now'))
+ON CONFLICT(name) DO UPDATE
+SET name = excluded.name
+RETURNING *;
+
+-- name: GetJobs :many
+SELECT *
+FROM jobs
+ORDER BY name;
+
+-- name: UpdateJobSchedule :one
+UPDATE jobs
+SET schedule = ?,
+    updated_at = datetime('now')
+WHERE name = ?
+RETURNING *;
+
+-- name: AddJobRun :one
+INSERT INTO job_runs(job_id, trigger, status, started_at)
+VALUES (?, ?, 'running', datetime('now'))
+RETURNING *;
+
+-- name: FinishJobRun :exec
+UPDATE job_runs
+SET status = ?,
+    error = ?,
+    finished_at = datetime('now')
+WHERE id = ?;
+
+-- name: FailUnfinishedJobRuns :exec
+UPDATE job_runs
+SET status = 'failed',
+    error = ?,
+    finished_at = datetime('now')
+WHERE status = 'running';
+
+-- name: GetRecentJobRuns :many
+SELECT job_runs.*, jobs.name AS job_name
+FROM job_runs
+JOIN jobs ON jobs.id = job_runs.job_id
+ORDER BY job_runs.started_at DESC, job_runs.id DESC
+LIMIT ?;
\ No newline at end of file
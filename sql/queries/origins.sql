@@ -1,6 +1,6 @@
 -- name: AddOrigin :one
-INSERT INTO plugin_origins(name, slug, url, path_to_plugin_list, has_api, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, datetime('now'), datetime('now'))
+INSERT INTO plugin_origins(name, slug, url, path_to_plugin_list, has_api, adapter, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))
 RETURNING *;
 
 -- name: GetOrigins :many
@@ -18,6 +18,7 @@ UPDATE plugin_origins
 SET url = ?,
     path_to_plugin_list = ?,
     has_api = ?,
+    adapter = ?,
     updated_at = datetime('now')
 WHERE slug = ?
 RETURNING *;
@@ -26,4 +27,9 @@ RETURNING *;
 DELETE
 FROM plugin_origins
 WHERE slug = ?
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: GetOriginByID :one
+SELECT *
+FROM plugin_origins
+WHERE id = ?;
\ No newline at end of file
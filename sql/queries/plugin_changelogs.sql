@@ -11,4 +11,36 @@ WHERE plugin_id = (
     FROM plugins
     WHERE slug = ?
 )
-ORDER BY update_date DESC, updated_at DESC;
\ No newline at end of file
+ORDER BY update_date DESC, updated_at DESC;
+
+-- name: GetPluginChangelogEntry :one
+SELECT *
+FROM plugin_changelogs
+WHERE plugin_changelogs.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+);
+
+-- name: UpdatePluginChangelog :one
+UPDATE plugin_changelogs
+SET version = ?,
+    changelog = ?,
+    update_date = ?,
+    updated_at = datetime('now')
+WHERE plugin_changelogs.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+RETURNING *;
+
+-- name: DeletePluginChangelog :one
+DELETE
+FROM plugin_changelogs
+WHERE plugin_changelogs.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+RETURNING *;
\ No newline at end of file
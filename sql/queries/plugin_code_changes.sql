@@ -0,0 +1,73 @@
+-- name: AddPluginCodeChange :one
+INSERT INTO plugin_manual_code_changes(plugin_id, modified_part, row_start, row_end, comment, is_relevant, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))
+RETURNING *;
+
+-- name: GetPluginCodeChanges :many
+SELECT *
+FROM plugin_manual_code_changes
+WHERE plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+ORDER BY row_start, id;
+
+-- name: GetPluginCodeChange :one
+SELECT *
+FROM plugin_manual_code_changes
+WHERE plugin_manual_code_changes.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+);
+
+-- name: UpdatePluginCodeChange :one
+-- the recorded hunk is dropped once the modified part is edited
+UPDATE plugin_manual_code_changes
+SET hunk = CASE WHEN modified_part = sqlc.arg(modified_part) THEN hunk ELSE '' END,
+    modified_part = sqlc.arg(modified_part),
+    row_start = sqlc.arg(row_start),
+    row_end = sqlc.arg(row_end),
+    comment = sqlc.arg(comment),
+    is_relevant = sqlc.arg(is_relevant),
+    updated_at = datetime('now')
+WHERE plugin_manual_code_changes.id = sqlc.arg(id) AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = sqlc.arg(slug)
+)
+RETURNING *;
+
+-- name: DeletePluginCodeChange :one
+DELETE
+FROM plugin_manual_code_changes
+WHERE plugin_manual_code_changes.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+RETURNING *;
+
+-- name: AddPluginCodeHunk :one
+INSERT INTO plugin_manual_code_changes(plugin_id, source_id, hunk, modified_part, row_start, row_end, comment, is_relevant, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, 1, datetime('now'), datetime('now'))
+RETURNING *;
+
+-- name: GetPluginCodeHunks :many
+SELECT *
+FROM plugin_manual_code_changes
+WHERE hunk != '' AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+ORDER BY row_start, id;
+
+-- name: SetPluginCodeChangeRelevance :exec
+UPDATE plugin_manual_code_changes
+SET is_relevant = ?,
+    row_start = ?,
+    row_end = ?,
+    updated_at = datetime('now')
+WHERE id = ?;
\ No newline at end of file
@@ -1,6 +1,6 @@
 -- name: AddPluginCommand :one
-INSERT INTO plugin_commands(plugin_id, command, description, created_at, updated_at)
-VALUES (?, ?, ?, datetime('now'), datetime('now'))
+INSERT INTO plugin_commands(plugin_id, command, cmd_type, args, aliases, permission, description, position, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))
 RETURNING *;
 
 -- name: GetPluginCommands :many
@@ -10,10 +10,50 @@ WHERE plugin_id = (
     SELECT id
     FROM plugins
     WHERE slug = ?
+)
+ORDER BY position, id;
+
+-- name: GetLastPluginCommandPosition :one
+SELECT CAST(COALESCE(MAX(position), 0) AS INTEGER)
+FROM plugin_commands
+WHERE plugin_id = ?;
+
+-- name: GetPluginCommand :one
+SELECT *
+FROM plugin_commands
+WHERE plugin_commands.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
 );
 
+-- name: UpdatePluginCommand :one
+UPDATE plugin_commands
+SET command = ?,
+    cmd_type = ?,
+    args = ?,
+    aliases = ?,
+    permission = ?,
+    description = ?,
+    updated_at = datetime('now')
+WHERE plugin_commands.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+RETURNING *;
+
 -- name: DeletePluginCommand :one
 DELETE
 FROM plugin_commands
-WHERE plugin_id = ?
-RETURNING *;
\ No newline at end of file
+WHERE plugin_commands.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+RETURNING *;
+
+-- name: DeletePluginCommands :exec
+DELETE
+FROM plugin_commands
+WHERE plugin_id = ?;
\ No newline at end of file
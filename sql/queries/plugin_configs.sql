@@ -32,4 +32,11 @@ WHERE plugin_id = (
     FROM plugins
     WHERE slug = ?
 )
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: SetPluginConfig :exec
+INSERT INTO plugin_configs(plugin_id, config_json, created_at, updated_at)
+VALUES (?, ?, datetime('now'), datetime('now'))
+ON CONFLICT (plugin_id) DO UPDATE
+SET config_json = excluded.config_json,
+    updated_at = datetime('now');
\ No newline at end of file
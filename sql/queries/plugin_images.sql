@@ -0,0 +1,75 @@
+-- name: AddPluginImage :one
+INSERT INTO plugin_images(plugin_id, file_name, mime_type, size, width, height, sha256, image, thumbnail, thumbnail_type, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))
+RETURNING id;
+
+-- name: GetPluginImages :many
+SELECT id, plugin_id, file_name, mime_type, size, width, height, is_cover, created_at
+FROM plugin_images
+WHERE plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+ORDER BY is_cover DESC, id;
+
+-- name: GetPluginImage :one
+SELECT id, file_name, mime_type, sha256, image, created_at
+FROM plugin_images
+WHERE plugin_images.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+);
+
+-- name: GetPluginImageThumbnail :one
+SELECT id, file_name, mime_type, sha256, image, thumbnail, thumbnail_type, created_at
+FROM plugin_images
+WHERE plugin_images.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+);
+
+-- name: ClearPluginCover :exec
+UPDATE plugin_images
+SET is_cover = 0,
+    updated_at = datetime('now')
+WHERE is_cover = 1 AND plugin_id = ?;
+
+-- name: SetPluginCover :one
+UPDATE plugin_images
+SET is_cover = 1,
+    updated_at = datetime('now')
+WHERE plugin_images.id = ? AND plugin_id = ?
+RETURNING id;
+
+-- name: SetDefaultPluginCover :exec
+UPDATE plugin_images
+SET is_cover = 1,
+    updated_at = datetime('now')
+WHERE plugin_images.id = (
+    SELECT min(id)
+    FROM plugin_images images
+    WHERE images.plugin_id = ?
+) AND NOT EXISTS (
+    SELECT 1
+    FROM plugin_images covers
+    WHERE covers.plugin_id = plugin_images.plugin_id AND covers.is_cover = 1
+);
+
+-- name: DeletePluginImage :one
+DELETE
+FROM plugin_images
+WHERE plugin_images.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+RETURNING id, plugin_id;
+
+-- name: GetPluginsWithCovers :many
+SELECT plugins.*, plugin_images.id AS cover_image_id
+FROM plugins
+LEFT JOIN plugin_images ON plugin_images.plugin_id = plugins.id AND plugin_images.is_cover = 1
+ORDER BY plugins.name;
\ No newline at end of file
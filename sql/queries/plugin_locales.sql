@@ -46,4 +46,21 @@ WHERE lang_code = ? AND plugin_id = (
     FROM plugins
     WHERE slug = ?
 )
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: SetPluginLocale :exec
+INSERT INTO plugin_locales(
+    plugin_id, lang_code,
+    lang_name, content_json,
+    created_at, updated_at
+)
+VALUES (?, ?, ?, ?, datetime('now'), datetime('now'))
+ON CONFLICT (plugin_id, lang_code) DO UPDATE
+SET content_json = excluded.content_json,
+    updated_at = datetime('now');
+
+-- name: GetAllPluginLocales :many
+SELECT plugins.name, plugins.slug, sqlc.embed(plugin_locales)
+FROM plugin_locales
+JOIN plugins ON plugins.id = plugin_locales.plugin_id
+ORDER BY plugins.name, plugin_locales.lang_code;
\ No newline at end of file
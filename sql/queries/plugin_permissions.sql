@@ -0,0 +1,46 @@
+-- name: AddPluginPermission :one
+INSERT INTO plugin_permissions(plugin_id, permission, description, created_at, updated_at)
+VALUES (?, ?, ?, datetime('now'), datetime('now'))
+ON CONFLICT(plugin_id, permission) DO NOTHING
+RETURNING *;
+
+-- name: GetPluginPermissions :many
+SELECT *
+FROM plugin_permissions
+WHERE plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+ORDER BY permission;
+
+-- name: GetPluginPermission :one
+SELECT *
+FROM plugin_permissions
+WHERE plugin_permissions.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+);
+
+-- name: UpdatePluginPermission :one
+UPDATE plugin_permissions
+SET permission = ?,
+    description = ?,
+    updated_at = datetime('now')
+WHERE plugin_permissions.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+RETURNING *;
+
+-- name: DeletePluginPermission :one
+DELETE
+FROM plugin_permissions
+WHERE plugin_permissions.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+RETURNING *;
\ No newline at end of file
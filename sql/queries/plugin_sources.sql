@@ -0,0 +1,62 @@
+-- name: AddSourceBlob :exec
+INSERT INTO source_blobs(sha256, content, size, created_at)
+VALUES (?, ?, ?, datetime('now'))
+ON CONFLICT (sha256) DO NOTHING;
+
+-- name: DeleteUnusedSourceBlobs :exec
+DELETE
+FROM source_blobs
+WHERE sha256 NOT IN (
+    SELECT sha256
+    FROM plugin_sources
+);
+
+-- name: SetPluginSource :one
+INSERT INTO plugin_sources(plugin_id, changelog_id, file_name, sha256, created_at, updated_at)
+VALUES (?, ?, ?, ?, datetime('now'), datetime('now'))
+ON CONFLICT (changelog_id) DO UPDATE
+SET file_name = excluded.file_name,
+    sha256 = excluded.sha256,
+    updated_at = datetime('now')
+RETURNING *;
+
+-- name: GetPluginSources :many
+SELECT plugin_sources.*, plugin_changelogs.version, source_blobs.size
+FROM plugin_sources
+JOIN plugin_changelogs ON plugin_changelogs.id = plugin_sources.changelog_id
+JOIN source_blobs ON source_blobs.sha256 = plugin_sources.sha256
+WHERE plugin_sources.plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+);
+
+-- name: GetPluginSource :one
+SELECT plugin_sources.*, plugin_changelogs.version, source_blobs.content
+FROM plugin_sources
+JOIN plugin_changelogs ON plugin_changelogs.id = plugin_sources.changelog_id
+JOIN source_blobs ON source_blobs.sha256 = plugin_sources.sha256
+WHERE plugin_sources.id = ? AND plugin_sources.plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+);
+
+-- name: DeletePluginSource :one
+DELETE
+FROM plugin_sources
+WHERE plugin_sources.id = ? AND plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE slug = ?
+)
+RETURNING *;
+
+-- name: GetPluginSourceFileName :one
+SELECT CAST(COALESCE((
+    SELECT file_name
+    FROM plugin_sources
+    WHERE plugin_id = ?
+    ORDER BY updated_at DESC, id DESC
+    LIMIT 1
+), '') AS TEXT) AS file_name;
\ No newline at end of file
@@ -40,4 +40,27 @@ RETURNING *;
 DELETE
 FROM plugins
 WHERE slug = ?
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: GetOriginPlugins :many
+SELECT *
+FROM plugins
+WHERE origin_id = ?
+ORDER BY name;
+
+-- name: UpsertPlugin :one
+INSERT INTO plugins(name, slug, description, url, origin_id, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, datetime('now'), datetime('now'))
+ON CONFLICT(slug) DO UPDATE
+SET name = excluded.name,
+    description = excluded.description,
+    url = excluded.url,
+    updated_at = datetime('now')
+WHERE plugins.origin_id = excluded.origin_id
+RETURNING *;
+
+-- name: SetPluginOutdated :exec
+UPDATE plugins
+SET is_updated_on_server = 0,
+    updated_at = datetime('now')
+WHERE id = ?;
\ No newline at end of file
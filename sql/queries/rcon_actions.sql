@@ -0,0 +1,38 @@
+-- name: AddRconAction :one
+INSERT INTO rcon_actions(server_id, plugin_id, action, command, actor, status, created_at)
+VALUES (?, ?, ?, ?, ?, 'running', datetime('now'))
+RETURNING *;
+
+-- name: AppendRconActionOutput :exec
+UPDATE rcon_actions
+SET output = output || ?
+WHERE id = ?;
+
+-- name: FinishRconAction :exec
+UPDATE rcon_actions
+SET status = ?,
+    finished_at = datetime('now')
+WHERE id = ?;
+
+-- name: FailUnfinishedRconActions :exec
+UPDATE rcon_actions
+SET status = 'failed',
+    output = output || ?,
+    finished_at = datetime('now')
+WHERE status = 'running';
+
+-- name: GetRconAction :one
+SELECT rcon_actions.*, servers.name AS server_name, plugins.slug AS plugin_slug
+FROM rcon_actions
+JOIN servers ON servers.id = rcon_actions.server_id
+JOIN plugins ON plugins.id = rcon_actions.plugin_id
+WHERE rcon_actions.id = ? AND plugins.slug = ?;
+
+-- name: GetPluginRconActions :many
+SELECT rcon_actions.*, servers.name AS server_name, plugins.slug AS plugin_slug
+FROM rcon_actions
+JOIN servers ON servers.id = rcon_actions.server_id
+JOIN plugins ON plugins.id = rcon_actions.plugin_id
+WHERE plugins.slug = ?
+ORDER BY rcon_actions.created_at DESC, rcon_actions.id DESC
+LIMIT ?;
\ No newline at end of file
@@ -0,0 +1,33 @@
+-- name: AddServerSnapshot :one
+INSERT INTO server_snapshots(server_id, created_at)
+VALUES (?, datetime('now'))
+RETURNING *;
+
+-- name: AddServerSnapshotPlugin :exec
+INSERT INTO server_snapshot_plugins(snapshot_id, title, version, author, hook_time, file_name, error)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetLatestServerSnapshot :one
+SELECT *
+FROM server_snapshots
+WHERE server_id = ?
+ORDER BY created_at DESC, id DESC
+LIMIT 1;
+
+-- name: GetServerSnapshotPlugins :many
+SELECT *
+FROM server_snapshot_plugins
+WHERE snapshot_id = ?
+ORDER BY title;
+
+-- name: GetPluginFileNames :many
+SELECT plugins.id, plugins.name, plugins.slug,
+    CAST(COALESCE((
+        SELECT file_name
+        FROM plugin_sources
+        WHERE plugin_sources.plugin_id = plugins.id
+        ORDER BY updated_at DESC, id DESC
+        LIMIT 1
+    ), '') AS TEXT) AS file_name
+FROM plugins
+ORDER BY plugins.name;
\ No newline at end of file
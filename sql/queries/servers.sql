@@ -0,0 +1,88 @@
+-- name: AddServer :one
+INSERT INTO servers(name, slug, framework, root_path, framework_path, notes, rcon_host, rcon_port, rcon_password, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))
+RETURNING *;
+
+-- name: GetServers :many
+SELECT servers.*, (
+    SELECT count(*)
+    FROM server_plugins
+    WHERE server_plugins.server_id = servers.id
+) AS plugin_count
+FROM servers
+ORDER BY name;
+
+-- name: GetServer :one
+SELECT *
+FROM servers
+WHERE slug = ?;
+
+-- name: UpdateServer :one
+UPDATE servers
+SET framework = ?,
+    root_path = ?,
+    framework_path = ?,
+    notes = ?,
+    rcon_host = ?,
+    rcon_port = ?,
+    rcon_password = ?,
+    updated_at = datetime('now')
+WHERE slug = ?
+RETURNING *;
+
+-- name: DeleteServer :one
+DELETE
+FROM servers
+WHERE slug = ?
+RETURNING *;
+
+-- name: SetServerPlugin :one
+INSERT INTO server_plugins(server_id, plugin_id, version, installed_at, created_at, updated_at)
+VALUES (?, ?, ?, ?, datetime('now'), datetime('now'))
+ON CONFLICT (server_id, plugin_id) DO UPDATE
+SET version = excluded.version,
+    installed_at = excluded.installed_at,
+    updated_at = datetime('now')
+RETURNING *;
+
+-- name: GetServerPlugins :many
+SELECT server_plugins.*, plugins.name, plugins.slug
+FROM server_plugins
+JOIN plugins ON plugins.id = server_plugins.plugin_id
+WHERE server_plugins.server_id = ?
+ORDER BY plugins.name;
+
+-- name: DeleteServerPlugin :one
+DELETE
+FROM server_plugins
+WHERE server_id = ? AND plugin_id = ?
+RETURNING *;
+
+-- name: GetPluginServers :many
+SELECT servers.name, servers.slug, servers.rcon_host,
+    CAST(COALESCE(server_plugins.version, '') AS TEXT) AS version,
+    CAST(COALESCE(server_plugins.installed_at, '') AS TEXT) AS installed_at
+FROM servers
+LEFT JOIN server_plugins ON server_plugins.server_id = servers.id AND server_plugins.plugin_id = (
+    SELECT id
+    FROM plugins
+    WHERE plugins.slug = ?
+)
+ORDER BY servers.name;
+
+-- name: GetChangelogVersions :many
+SELECT plugin_id, version
+FROM plugin_changelogs;
+
+-- name: GetServerPlugin :one
+SELECT *
+FROM server_plugins
+WHERE server_id = ? AND plugin_id = ?;
+
+-- name: CountServers :one
+SELECT count(*)
+FROM servers;
+
+-- name: GetInstalledVersions :many
+SELECT plugin_id, version
+FROM server_plugins;
\ No newline at end of file
@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE plugin_origins ADD COLUMN adapter TEXT DEFAULT '' NOT NULL;
+
+-- origins with API were synced by the uMod adapter before the column existed
+UPDATE plugin_origins SET adapter = 'umod' WHERE has_api = 1;
+
+-- +goose Down
+ALTER TABLE plugin_origins DROP COLUMN adapter;
\ No newline at end of file
@@ -0,0 +1,24 @@
+-- +goose Up
+CREATE TABLE jobs (
+    id INTEGER PRIMARY KEY,
+    name TEXT UNIQUE NOT NULL,
+    schedule TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+CREATE TABLE job_runs (
+    id INTEGER PRIMARY KEY,
+    job_id INTEGER NOT NULL,
+    trigger TEXT NOT NULL,
+    status TEXT NOT NULL,
+    error TEXT DEFAULT '' NOT NULL,
+    started_at TEXT NOT NULL,
+    finished_at TEXT DEFAULT '' NOT NULL,
+
+    FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE job_runs;
+DROP TABLE jobs;
\ No newline at end of file
@@ -0,0 +1,19 @@
+-- +goose Up
+ALTER TABLE plugin_commands ADD COLUMN cmd_type TEXT DEFAULT 'chat' NOT NULL
+    CHECK (cmd_type IN ('chat', 'console', 'both'));
+ALTER TABLE plugin_commands ADD COLUMN args TEXT DEFAULT '' NOT NULL;
+-- comma-separated alternative names
+ALTER TABLE plugin_commands ADD COLUMN aliases TEXT DEFAULT '' NOT NULL;
+ALTER TABLE plugin_commands ADD COLUMN permission TEXT DEFAULT '' NOT NULL;
+-- order the commands were entered in
+ALTER TABLE plugin_commands ADD COLUMN position INTEGER DEFAULT 0 NOT NULL;
+
+-- keep the insertion order of existing commands
+UPDATE plugin_commands SET position = id;
+
+-- +goose Down
+ALTER TABLE plugin_commands DROP COLUMN position;
+ALTER TABLE plugin_commands DROP COLUMN permission;
+ALTER TABLE plugin_commands DROP COLUMN aliases;
+ALTER TABLE plugin_commands DROP COLUMN args;
+ALTER TABLE plugin_commands DROP COLUMN cmd_type;
\ No newline at end of file
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE plugin_permissions (
+    id INTEGER PRIMARY KEY,
+    plugin_id INTEGER NOT NULL,
+    permission TEXT NOT NULL,
+    description TEXT DEFAULT '' NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+
+    UNIQUE (plugin_id, permission),
+    FOREIGN KEY (plugin_id) REFERENCES plugins(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE plugin_permissions;
\ No newline at end of file
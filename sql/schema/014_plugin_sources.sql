@@ -0,0 +1,27 @@
+-- +goose Up
+-- source contents addressed by their SHA-256 checksum, so equal files
+-- uploaded for several versions are stored once
+CREATE TABLE source_blobs (
+    sha256 TEXT PRIMARY KEY,
+    content BLOB NOT NULL,
+    size INTEGER NOT NULL,
+    created_at TEXT NOT NULL
+);
+
+CREATE TABLE plugin_sources (
+    id INTEGER PRIMARY KEY,
+    plugin_id INTEGER NOT NULL,
+    changelog_id INTEGER NOT NULL UNIQUE,
+    file_name TEXT NOT NULL,
+    sha256 TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+
+    FOREIGN KEY (plugin_id) REFERENCES plugins(id) ON DELETE CASCADE,
+    FOREIGN KEY (changelog_id) REFERENCES plugin_changelogs(id) ON DELETE CASCADE,
+    FOREIGN KEY (sha256) REFERENCES source_blobs(sha256)
+);
+
+-- +goose Down
+DROP TABLE plugin_sources;
+DROP TABLE source_blobs;
\ No newline at end of file
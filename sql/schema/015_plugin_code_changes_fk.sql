@@ -0,0 +1,45 @@
+-- +goose Up
+-- the table referenced a non-existent "plugin" table, SQLite can't alter
+-- foreign keys, so the table is recreated
+CREATE TABLE plugin_manual_code_changes_new (
+    id INTEGER PRIMARY KEY,
+    plugin_id INTEGER NOT NULL,
+    modified_part TEXT NOT NULL,
+    row_start INTEGER NOT NULL,
+    row_end INTEGER NOT NULL,
+    comment TEXT NOT NULL,
+    is_relevant INTEGER DEFAULT 0 NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+
+    FOREIGN KEY (plugin_id) REFERENCES plugins(id) ON DELETE CASCADE
+);
+
+INSERT INTO plugin_manual_code_changes_new
+SELECT *
+FROM plugin_manual_code_changes
+WHERE plugin_id IN (
+    SELECT id
+    FROM plugins
+);
+
+DROP TABLE plugin_manual_code_changes;
+ALTER TABLE plugin_manual_code_changes_new RENAME TO plugin_manual_code_changes;
+
+-- +goose Down
+CREATE TABLE plugin_manual_code_changes_old (
+    id INTEGER PRIMARY KEY,
+    plugin_id INTEGER NOT NULL,
+    modified_part TEXT NOT NULL,
+    row_start INTEGER NOT NULL,
+    row_end INTEGER NOT NULL,
+    comment TEXT NOT NULL,
+    is_relevant INTEGER DEFAULT 0 NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+
+    FOREIGN KEY (plugin_id) REFERENCES plugin(id) ON DELETE CASCADE
+);
+
+DROP TABLE plugin_manual_code_changes;
+ALTER TABLE plugin_manual_code_changes_old RENAME TO plugin_manual_code_changes;
\ No newline at end of file
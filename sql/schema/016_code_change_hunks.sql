@@ -0,0 +1,27 @@
+-- +goose Up
+-- manual code changes recorded as diff hunks against a stored source
+ALTER TABLE plugin_manual_code_changes ADD COLUMN source_id INTEGER REFERENCES plugin_sources(id) ON DELETE SET NULL;
+ALTER TABLE plugin_manual_code_changes ADD COLUMN hunk TEXT DEFAULT '' NOT NULL;
+
+-- +goose Down
+-- SQLite can't drop columns of foreign keys, so the table is recreated
+CREATE TABLE plugin_manual_code_changes_old (
+    id INTEGER PRIMARY KEY,
+    plugin_id INTEGER NOT NULL,
+    modified_part TEXT NOT NULL,
+    row_start INTEGER NOT NULL,
+    row_end INTEGER NOT NULL,
+    comment TEXT NOT NULL,
+    is_relevant INTEGER DEFAULT 0 NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+
+    FOREIGN KEY (plugin_id) REFERENCES plugins(id) ON DELETE CASCADE
+);
+
+INSERT INTO plugin_manual_code_changes_old
+SELECT id, plugin_id, modified_part, row_start, row_end, comment, is_relevant, created_at, updated_at
+FROM plugin_manual_code_changes;
+
+DROP TABLE plugin_manual_code_changes;
+ALTER TABLE plugin_manual_code_changes_old RENAME TO plugin_manual_code_changes;
\ No newline at end of file
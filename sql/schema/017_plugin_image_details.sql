@@ -0,0 +1,26 @@
+-- +goose Up
+ALTER TABLE plugin_images ADD COLUMN file_name TEXT DEFAULT '' NOT NULL;
+ALTER TABLE plugin_images ADD COLUMN mime_type TEXT DEFAULT 'application/octet-stream' NOT NULL;
+ALTER TABLE plugin_images ADD COLUMN size INTEGER DEFAULT 0 NOT NULL;
+ALTER TABLE plugin_images ADD COLUMN width INTEGER DEFAULT 0 NOT NULL;
+ALTER TABLE plugin_images ADD COLUMN height INTEGER DEFAULT 0 NOT NULL;
+ALTER TABLE plugin_images ADD COLUMN sha256 TEXT DEFAULT '' NOT NULL;
+-- scaled-down copy, empty for images that are small already
+ALTER TABLE plugin_images ADD COLUMN thumbnail BLOB;
+ALTER TABLE plugin_images ADD COLUMN thumbnail_type TEXT DEFAULT '' NOT NULL;
+ALTER TABLE plugin_images ADD COLUMN is_cover INTEGER DEFAULT 0 NOT NULL;
+
+-- a plugin has one cover at most
+CREATE UNIQUE INDEX plugin_images_cover ON plugin_images(plugin_id) WHERE is_cover = 1;
+
+-- +goose Down
+DROP INDEX plugin_images_cover;
+ALTER TABLE plugin_images DROP COLUMN is_cover;
+ALTER TABLE plugin_images DROP COLUMN thumbnail_type;
+ALTER TABLE plugin_images DROP COLUMN thumbnail;
+ALTER TABLE plugin_images DROP COLUMN sha256;
+ALTER TABLE plugin_images DROP COLUMN height;
+ALTER TABLE plugin_images DROP COLUMN width;
+ALTER TABLE plugin_images DROP COLUMN size;
+ALTER TABLE plugin_images DROP COLUMN mime_type;
+ALTER TABLE plugin_images DROP COLUMN file_name;
\ No newline at end of file
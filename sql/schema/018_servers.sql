@@ -0,0 +1,33 @@
+-- +goose Up
+CREATE TABLE servers (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    slug TEXT UNIQUE NOT NULL,
+    -- modding framework: oxide or carbon
+    framework TEXT DEFAULT 'oxide' NOT NULL,
+    -- server installation and framework directories on the server machine
+    root_path TEXT DEFAULT '' NOT NULL,
+    framework_path TEXT DEFAULT '' NOT NULL,
+    notes TEXT DEFAULT '' NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+-- plugin versions installed on servers
+CREATE TABLE server_plugins (
+    id INTEGER PRIMARY KEY,
+    server_id INTEGER NOT NULL,
+    plugin_id INTEGER NOT NULL,
+    version TEXT NOT NULL,
+    installed_at TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+
+    UNIQUE (server_id, plugin_id),
+    FOREIGN KEY (server_id) REFERENCES servers(id) ON DELETE CASCADE,
+    FOREIGN KEY (plugin_id) REFERENCES plugins(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE server_plugins;
+DROP TABLE servers;
\ No newline at end of file
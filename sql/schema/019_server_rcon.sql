@@ -0,0 +1,10 @@
+-- +goose Up
+-- WebRCON listener of the server, an empty host means RCON isn't set up
+ALTER TABLE servers ADD COLUMN rcon_host TEXT DEFAULT '' NOT NULL;
+ALTER TABLE servers ADD COLUMN rcon_port INTEGER DEFAULT 28016 NOT NULL;
+ALTER TABLE servers ADD COLUMN rcon_password TEXT DEFAULT '' NOT NULL;
+
+-- +goose Down
+ALTER TABLE servers DROP COLUMN rcon_password;
+ALTER TABLE servers DROP COLUMN rcon_port;
+ALTER TABLE servers DROP COLUMN rcon_host;
\ No newline at end of file
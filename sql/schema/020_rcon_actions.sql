@@ -0,0 +1,21 @@
+-- +goose Up
+-- plugin actions run on servers over RCON
+CREATE TABLE rcon_actions (
+    id INTEGER PRIMARY KEY,
+    server_id INTEGER NOT NULL,
+    plugin_id INTEGER NOT NULL,
+    action TEXT NOT NULL,
+    command TEXT NOT NULL,
+    -- user named by the authenticating proxy or the client address
+    actor TEXT NOT NULL,
+    status TEXT NOT NULL,
+    output TEXT DEFAULT '' NOT NULL,
+    created_at TEXT NOT NULL,
+    finished_at TEXT DEFAULT '' NOT NULL,
+
+    FOREIGN KEY (server_id) REFERENCES servers(id) ON DELETE CASCADE,
+    FOREIGN KEY (plugin_id) REFERENCES plugins(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE rcon_actions;
\ No newline at end of file
@@ -0,0 +1,28 @@
+-- +goose Up
+-- plugins reported loaded by servers at some point in time
+CREATE TABLE server_snapshots (
+    id INTEGER PRIMARY KEY,
+    server_id INTEGER NOT NULL,
+    created_at TEXT NOT NULL,
+
+    FOREIGN KEY (server_id) REFERENCES servers(id) ON DELETE CASCADE
+);
+
+CREATE TABLE server_snapshot_plugins (
+    id INTEGER PRIMARY KEY,
+    snapshot_id INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    version TEXT DEFAULT '' NOT NULL,
+    author TEXT DEFAULT '' NOT NULL,
+    -- seconds spent in hooks since the plugin was loaded
+    hook_time REAL DEFAULT 0 NOT NULL,
+    file_name TEXT NOT NULL,
+    -- why the plugin failed to load
+    error TEXT DEFAULT '' NOT NULL,
+
+    FOREIGN KEY (snapshot_id) REFERENCES server_snapshots(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE server_snapshot_plugins;
+DROP TABLE server_snapshots;
\ No newline at end of file
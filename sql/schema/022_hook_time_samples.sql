@@ -0,0 +1,29 @@
+-- +goose Up
+-- cumulative hook times of loaded plugins sampled on schedule,
+-- looked up by servers and plugin file names over time
+CREATE TABLE hook_time_samples (
+    id INTEGER PRIMARY KEY,
+    server_id INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    file_name TEXT NOT NULL,
+    version TEXT DEFAULT '' NOT NULL,
+    -- seconds spent in hooks since the plugin was loaded
+    hook_time REAL NOT NULL,
+    sampled_at TEXT NOT NULL,
+
+    FOREIGN KEY (server_id) REFERENCES servers(id) ON DELETE CASCADE
+);
+
+CREATE INDEX hook_time_samples_server_sampled
+ON hook_time_samples(server_id, sampled_at);
+
+CREATE INDEX hook_time_samples_file_name
+ON hook_time_samples(file_name COLLATE NOCASE, sampled_at);
+
+-- the latest snapshot of a server is looked up for reconciliation
+CREATE INDEX server_snapshots_server_created
+ON server_snapshots(server_id, created_at);
+
+-- +goose Down
+DROP INDEX server_snapshots_server_created;
+DROP TABLE hook_time_samples;
\ No newline at end of file